@@ -0,0 +1,511 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// AggregateOptions controls how Aggregation opens files.
+type AggregateOptions struct {
+	// MaxOpen is the maximum number of files open at once. The least
+	// recently used file is closed to open another one. The first file
+	// stays open, since attributes are read from it, so it's at least 2.
+	// Zero means 16.
+	MaxOpen int
+
+	// Open opens the file at path for reading. Nil means OpenFile with
+	// mode NOWRITE.
+	Open func(path string) (Dataset, error)
+}
+
+// Aggregation is a read-only view of variables split across many files,
+// concatenated along one dimension, such as files holding one day of
+// records each.
+//
+// Files are opened lazily when reading, and closed when more than
+// MaxOpen files would be open. Attributes and dimensions other than the
+// aggregated one are those of the first file, which stays open. It's safe
+// to use an Aggregation from multiple goroutines; reads are serialized.
+type Aggregation struct {
+	paths   []string
+	dim     string
+	offsets []uint64 // offset of each file along dim, followed by the total length
+	dims    []aggDim // dimensions of the first file
+	vars    []aggVar
+	first   Dataset
+	open    func(path string) (Dataset, error)
+	maxOpen int
+
+	mu     sync.Mutex
+	closed bool
+	files  map[int]Dataset
+	lru    []int // indices of open files, least recently used first
+}
+
+// aggDim is a dimension of the first file of an aggregation.
+type aggDim struct {
+	name string
+	len  uint64
+}
+
+// aggVar is the schema of a variable of an aggregation.
+type aggVar struct {
+	id    int // ID in the first file
+	name  string
+	t     Type
+	dims  []string
+	shape []uint64 // shape in the first file
+	agg   bool     // whether the variable is concatenated along dim
+}
+
+// Aggregate returns the aggregation of the files at paths along the
+// dimension dim, usually the unlimited dimension. It's
+// AggregateWithOptions with default options.
+func Aggregate(paths []string, dim string) (*Aggregation, error) {
+	return AggregateWithOptions(paths, dim, nil)
+}
+
+// AggregateWithOptions returns the aggregation of the files at paths along
+// the dimension dim, in the order of paths. Opts may be nil for default
+// options.
+//
+// All files must have the same variables, with the same types and
+// dimensions, and all dimensions but dim must have the same length in all
+// files. Dim must be the first dimension of variables that have it. The
+// values of variables that don't have dim are read from the first file.
+func AggregateWithOptions(paths []string, dim string, opts *AggregateOptions) (*Aggregation, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files to aggregate")
+	}
+	a := &Aggregation{
+		paths:   append([]string(nil), paths...),
+		dim:     dim,
+		offsets: make([]uint64, len(paths)+1),
+		open:    func(path string) (Dataset, error) { return OpenFile(path, NOWRITE) },
+		maxOpen: 16,
+		files:   make(map[int]Dataset),
+	}
+	if opts != nil && opts.Open != nil {
+		a.open = opts.Open
+	}
+	if opts != nil && opts.MaxOpen > 0 {
+		a.maxOpen = opts.MaxOpen
+	}
+	if a.maxOpen < 2 {
+		a.maxOpen = 2
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, path := range a.paths {
+		ds, err := a.file(i)
+		if err != nil {
+			a.closeAll()
+			return nil, err
+		}
+		vars, n, err := aggSchema(ds, dim)
+		if err == nil && i > 0 {
+			err = a.checkSchema(vars)
+		}
+		if err != nil {
+			a.closeAll()
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if i == 0 {
+			a.first, a.vars = ds, vars
+			if a.dims, err = aggDims(ds); err != nil {
+				a.closeAll()
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+		}
+		a.offsets[i+1] = a.offsets[i] + n
+	}
+	return a, nil
+}
+
+// aggSchema returns the variables of ds and the length of dimension dim.
+func aggSchema(ds Dataset, dim string) (vars []aggVar, n uint64, err error) {
+	d, err := ds.Dim(dim)
+	if err != nil {
+		return nil, 0, fmt.Errorf("dimension %q: %v", dim, err)
+	}
+	if n, err = d.Len(); err != nil {
+		return nil, 0, err
+	}
+	nvars, err := ds.NVars()
+	if err != nil {
+		return nil, 0, err
+	}
+	for id := 0; id < nvars; id++ {
+		v := ds.VarN(id)
+		av := aggVar{id: id}
+		if av.name, err = v.Name(); err != nil {
+			return nil, 0, err
+		}
+		if av.t, err = v.Type(); err != nil {
+			return nil, 0, err
+		}
+		if av.shape, err = v.LenDims(); err != nil {
+			return nil, 0, err
+		}
+		dims, err := v.Dims()
+		if err != nil {
+			return nil, 0, err
+		}
+		for k, d := range dims {
+			name, err := d.Name()
+			if err != nil {
+				return nil, 0, err
+			}
+			if name == dim {
+				if k != 0 {
+					return nil, 0, fmt.Errorf("dimension %q isn't the first dimension of variable %q", dim, av.name)
+				}
+				av.agg = true
+			}
+			av.dims = append(av.dims, name)
+		}
+		vars = append(vars, av)
+	}
+	return vars, n, nil
+}
+
+// aggDims returns the dimensions of ds.
+func aggDims(ds Dataset) ([]aggDim, error) {
+	n, err := ds.NDims()
+	if err != nil {
+		return nil, err
+	}
+	dims := make([]aggDim, n)
+	for i := range dims {
+		d := ds.DimN(i)
+		if dims[i].name, err = d.Name(); err != nil {
+			return nil, err
+		}
+		if dims[i].len, err = d.Len(); err != nil {
+			return nil, err
+		}
+	}
+	return dims, nil
+}
+
+// checkSchema checks that vars match the variables of the first file.
+func (a *Aggregation) checkSchema(vars []aggVar) error {
+	if len(vars) != len(a.vars) {
+		return fmt.Errorf("%d variables; %s has %d", len(vars), a.paths[0], len(a.vars))
+	}
+	for _, v := range vars {
+		w, err := a.aggVar(v.name)
+		if err != nil {
+			return fmt.Errorf("variable %q isn't in %s", v.name, a.paths[0])
+		}
+		if v.t != w.t {
+			return fmt.Errorf("variable %q has type %v; expected %v", v.name, v.t, w.t)
+		}
+		if !reflect.DeepEqual(v.dims, w.dims) {
+			return fmt.Errorf("variable %q has dimensions %v; expected %v", v.name, v.dims, w.dims)
+		}
+		for k := range v.shape {
+			if (k > 0 || !v.agg) && v.shape[k] != w.shape[k] {
+				return fmt.Errorf("dimension %q of variable %q has length %d; expected %d", v.dims[k], v.name, v.shape[k], w.shape[k])
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Aggregation) aggVar(name string) (*aggVar, error) {
+	for i := range a.vars {
+		if a.vars[i].name == name {
+			return &a.vars[i], nil
+		}
+	}
+	return nil, ENOTVAR
+}
+
+// file returns the i-th file, opening it if needed. The caller must hold
+// a.mu.
+func (a *Aggregation) file(i int) (Dataset, error) {
+	if a.closed {
+		return Dataset{}, fmt.Errorf("aggregation is closed")
+	}
+	for k, j := range a.lru {
+		if j == i {
+			a.lru = append(append(a.lru[:k:k], a.lru[k+1:]...), i)
+			return a.files[i], nil
+		}
+	}
+	for len(a.lru) >= a.maxOpen {
+		// The first file stays open.
+		k := 0
+		if a.lru[k] == 0 {
+			k++
+		}
+		j := a.lru[k]
+		a.lru = append(a.lru[:k:k], a.lru[k+1:]...)
+		ds := a.files[j]
+		delete(a.files, j)
+		if err := ds.Close(); err != nil {
+			return Dataset{}, fmt.Errorf("%s: %v", a.paths[j], err)
+		}
+	}
+	ds, err := a.open(a.paths[i])
+	if err != nil {
+		return Dataset{}, fmt.Errorf("%s: %v", a.paths[i], err)
+	}
+	a.files[i] = ds
+	a.lru = append(a.lru, i)
+	return ds, nil
+}
+
+// closeAll closes all open files. The caller must hold a.mu.
+func (a *Aggregation) closeAll() error {
+	var err error
+	for _, i := range a.lru {
+		if e := a.files[i].Close(); e != nil && err == nil {
+			err = fmt.Errorf("%s: %v", a.paths[i], e)
+		}
+		delete(a.files, i)
+	}
+	a.lru = nil
+	return err
+}
+
+// Close closes the files of a that are open. The aggregation can't be used
+// afterwards.
+func (a *Aggregation) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	return a.closeAll()
+}
+
+// Paths returns the paths of the files of a.
+func (a *Aggregation) Paths() []string {
+	return append([]string(nil), a.paths...)
+}
+
+// Dim returns the name of the dimension along which files are
+// concatenated.
+func (a *Aggregation) Dim() string {
+	return a.dim
+}
+
+// Len returns the length of the aggregated dimension, which is the sum of
+// its lengths in all files.
+func (a *Aggregation) Len() uint64 {
+	return a.offsets[len(a.paths)]
+}
+
+// DimNames returns the names of the dimensions of the first file.
+func (a *Aggregation) DimNames() []string {
+	names := make([]string, len(a.dims))
+	for i, d := range a.dims {
+		names[i] = d.name
+	}
+	return names
+}
+
+// DimLen returns the length of the dimension named name in the
+// aggregation. It's Len for the aggregated dimension, and the length in the
+// first file for the others.
+func (a *Aggregation) DimLen(name string) (uint64, error) {
+	if name == a.dim {
+		return a.Len(), nil
+	}
+	for _, d := range a.dims {
+		if d.name == name {
+			return d.len, nil
+		}
+	}
+	return 0, EBADDIM
+}
+
+// NAttrs returns the number of global attributes of the first file.
+func (a *Aggregation) NAttrs() (int, error) {
+	return a.first.NAttrs()
+}
+
+// Attr returns the global attribute named name of the first file. It can
+// be read until a is closed.
+func (a *Aggregation) Attr(name string) Attr {
+	return a.first.Attr(name)
+}
+
+// AttrN returns the n-th global attribute of the first file.
+func (a *Aggregation) AttrN(n int) (Attr, error) {
+	return a.first.AttrN(n)
+}
+
+// NumOpen returns the number of files currently open.
+func (a *Aggregation) NumOpen() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.lru)
+}
+
+// VarNames returns the names of the variables of a, in the order of the
+// first file.
+func (a *Aggregation) VarNames() []string {
+	names := make([]string, len(a.vars))
+	for i, v := range a.vars {
+		names[i] = v.name
+	}
+	return names
+}
+
+// Var returns the aggregated variable named name.
+func (a *Aggregation) Var(name string) (AggVar, error) {
+	v, err := a.aggVar(name)
+	if err != nil {
+		return AggVar{}, err
+	}
+	return AggVar{a, v}, nil
+}
+
+// AggVar is a variable of an aggregation.
+type AggVar struct {
+	a *Aggregation
+	v *aggVar
+}
+
+// Name returns the name of v.
+func (v AggVar) Name() string {
+	return v.v.name
+}
+
+// Type returns the type of v.
+func (v AggVar) Type() Type {
+	return v.v.t
+}
+
+// Dims returns the names of the dimensions of v.
+func (v AggVar) Dims() []string {
+	return append([]string(nil), v.v.dims...)
+}
+
+// LenDims returns the length of each dimension of v in the aggregation.
+func (v AggVar) LenDims() []uint64 {
+	shape := append([]uint64(nil), v.v.shape...)
+	if v.v.agg {
+		shape[0] = v.a.Len()
+	}
+	return shape
+}
+
+// NAttrs returns the number of attributes of v in the first file.
+func (v AggVar) NAttrs() (int, error) {
+	return v.a.first.VarN(v.v.id).NAttrs()
+}
+
+// Attr returns the attribute named name of v in the first file, such as
+// its units or _FillValue. It can be read until the aggregation is closed.
+func (v AggVar) Attr(name string) Attr {
+	return v.a.first.VarN(v.v.id).Attr(name)
+}
+
+// AttrN returns the n-th attribute of v in the first file.
+func (v AggVar) AttrN(n int) (Attr, error) {
+	return v.a.first.VarN(v.v.id).AttrN(n)
+}
+
+// Len returns the number of values of v in the aggregation.
+func (v AggVar) Len() uint64 {
+	return product(v.LenDims())
+}
+
+// Read reads the entire variable v into data, which must be a slice of the
+// Go type corresponding to the type of v, with enough space for all the
+// values.
+func (v AggVar) Read(data interface{}) error {
+	shape := v.LenDims()
+	return v.ReadSlice(data, make([]uint64, len(shape)), shape)
+}
+
+// ReadSlice reads the slice of variable v specified by start and count
+// into data, like ReadFloat64Slice and friends. The slice is read from the
+// files holding it, which are opened if needed.
+func (v AggVar) ReadSlice(data interface{}, start, count []uint64) error {
+	shape := v.LenDims()
+	if len(start) != len(shape) || len(count) != len(shape) {
+		return fmt.Errorf("incorrect number of dimensions in slice: %d, %d != %d", len(start), len(count), len(shape))
+	}
+	for i := range shape {
+		if start[i]+count[i] > shape[i] {
+			return fmt.Errorf("dimension %d of slice is out of range: %d + %d > %d", i, start[i], count[i], shape[i])
+		}
+	}
+	_, n, err := sliceType(data)
+	if err != nil {
+		return err
+	}
+	if l := product(count); uint64(n) < l {
+		return fmt.Errorf("data length %d is smaller than %d", n, l)
+	}
+	if product(count) == 0 {
+		return nil
+	}
+
+	a := v.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !v.v.agg {
+		return a.readFile(0, v.v.name, data, start, count)
+	}
+	// The aggregated dimension is the first one, so the values from each
+	// file are contiguous in data.
+	rowLen := product(count[1:])
+	lo, hi := start[0], start[0]+count[0]
+	off := uint64(0)
+	for i := range a.paths {
+		flo, fhi := a.offsets[i], a.offsets[i+1]
+		if fhi <= lo || flo >= hi {
+			continue
+		}
+		s := append([]uint64{maxUint64(lo, flo) - flo}, start[1:]...)
+		c := append([]uint64{minUint64(hi, fhi) - flo - s[0]}, count[1:]...)
+		m := c[0] * rowLen
+		part := reflect.ValueOf(data).Slice(int(off), int(off+m)).Interface()
+		if err := a.readFile(i, v.v.name, part, s, c); err != nil {
+			return err
+		}
+		off += m
+	}
+	return nil
+}
+
+// readFile reads a slice of the variable named name from the i-th file.
+// The caller must hold a.mu.
+func (a *Aggregation) readFile(i int, name string, data interface{}, start, count []uint64) error {
+	ds, err := a.file(i)
+	if err != nil {
+		return err
+	}
+	v, err := ds.Var(name)
+	if err != nil {
+		return fmt.Errorf("%s: variable %q: %v", a.paths[i], name, err)
+	}
+	if _, err := v.ReadSliceCtx(context.Background(), data, start, count, nil); err != nil {
+		return fmt.Errorf("%s: %v", a.paths[i], err)
+	}
+	return nil
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+// dayFixture returns the fixture of the file for day d, which has d%3+1
+// records. The values of temp are 1000*d + 10*record + x.
+func dayFixture(d int) netcdftest.Fixture {
+	n := d%3 + 1
+	var temp []float64
+	var time []float64
+	for r := 0; r < n; r++ {
+		time = append(time, float64(d)+float64(r)/float64(n))
+		for x := 0; x < 2; x++ {
+			temp = append(temp, float64(1000*d+10*r+x))
+		}
+	}
+	return netcdftest.Fixture{
+		Dims:  []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "x", Len: 2}},
+		Attrs: []netcdftest.Attr{{Name: "title", Value: fmt.Sprintf("day %d", d)}},
+		Vars: []netcdftest.Var{
+			{Name: "time", Dims: []string{"time"}, Data: time},
+			{Name: "x", Dims: []string{"x"}, Data: []int32{10, 20}},
+			{Name: "temp", Dims: []string{"time", "x"}, Data: temp,
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "K"}, {Name: "_FillValue", Value: -999.0}}},
+		},
+	}
+}
+
+// dayOpener opens the files named dayN with fixture(N), and keeps track
+// of the files open.
+type dayOpener struct {
+	fixture func(d int) netcdftest.Fixture
+	dss     []netcdf.Dataset
+	maxOpen int
+}
+
+func (o *dayOpener) Open(path string) (netcdf.Dataset, error) {
+	var d int
+	if _, err := fmt.Sscanf(path, "day%d", &d); err != nil {
+		return netcdf.Dataset{}, err
+	}
+	ds, err := netcdftest.Build(o.fixture(d))
+	if err != nil {
+		return netcdf.Dataset{}, err
+	}
+	o.dss = append(o.dss, ds)
+	if n := o.numOpen(); n > o.maxOpen {
+		o.maxOpen = n
+	}
+	return ds, nil
+}
+
+func (o *dayOpener) numOpen() int {
+	n := 0
+	for _, ds := range o.dss {
+		if _, err := ds.NVars(); err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAggregate(t *testing.T) {
+	o := &dayOpener{fixture: dayFixture}
+	opts := &netcdf.AggregateOptions{MaxOpen: 2, Open: o.Open}
+	paths := []string{"day0", "day1", "day2", "day3", "day4"}
+	a, err := netcdf.AggregateWithOptions(paths, "time", opts)
+	if err != nil {
+		t.Fatalf("AggregateWithOptions failed: %v\n", err)
+	}
+	if a.Len() != 1+2+3+1+2 {
+		t.Errorf("length of aggregation is %d; expected 9\n", a.Len())
+	}
+	if got, want := a.VarNames(), []string{"time", "x", "temp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("VarNames returned %v; expected %v\n", got, want)
+	}
+
+	v, err := a.Var("temp")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if got, want := v.LenDims(), []uint64{9, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LenDims returned %v; expected %v\n", got, want)
+	}
+	data := make([]float64, 5)
+	if err := v.ReadSlice(data, []uint64{2, 1}, []uint64{5, 1}); err != nil {
+		t.Fatalf("ReadSlice failed: %v\n", err)
+	}
+	if want := []float64{1011, 2001, 2011, 2021, 3001}; !reflect.DeepEqual(data, want) {
+		t.Errorf("ReadSlice read %v; expected %v\n", data, want)
+	}
+	data = make([]float64, v.Len())
+	if err := v.Read(data); err != nil {
+		t.Fatalf("Read failed: %v\n", err)
+	}
+	if data[17] != 4011 {
+		t.Errorf("last value is %v; expected 4011\n", data[17])
+	}
+
+	x, err := a.Var("x")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	xs := make([]int32, 2)
+	if err := x.Read(xs); err != nil {
+		t.Fatalf("Read failed: %v\n", err)
+	}
+	if want := []int32{10, 20}; !reflect.DeepEqual(xs, want) {
+		t.Errorf("Read read %v; expected %v\n", xs, want)
+	}
+
+	// Attributes and dimensions come from the first file.
+	if got, want := a.DimNames(), []string{"time", "x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DimNames returned %v; expected %v\n", got, want)
+	}
+	for name, want := range map[string]uint64{"time": 9, "x": 2} {
+		if n, err := a.DimLen(name); err != nil || n != want {
+			t.Errorf("DimLen(%q) returned %d, %v; expected %d\n", name, n, err, want)
+		}
+	}
+	if _, err := a.DimLen("y"); err != netcdf.EBADDIM {
+		t.Errorf("DimLen of unknown dimension returned %v; expected %v\n", err, netcdf.EBADDIM)
+	}
+	if n, err := a.NAttrs(); err != nil || n != 1 {
+		t.Errorf("NAttrs returned %d, %v; expected 1\n", n, err)
+	}
+	if title, err := netcdf.GetBytes(a.Attr("title")); err != nil || string(title) != "day 0" {
+		t.Errorf("title is %q (error %v); expected \"day 0\"\n", title, err)
+	}
+	if n, err := v.NAttrs(); err != nil || n != 2 {
+		t.Errorf("NAttrs of temp returned %d, %v; expected 2\n", n, err)
+	}
+	if units, err := netcdf.GetBytes(v.Attr("units")); err != nil || string(units) != "K" {
+		t.Errorf("units are %q (error %v); expected \"K\"\n", units, err)
+	}
+	fill, err := v.AttrN(1)
+	if err != nil {
+		t.Fatalf("AttrN failed: %v\n", err)
+	}
+	if fv, err := netcdf.GetFloat64s(fill); err != nil || fill.Name() != "_FillValue" || fv[0] != -999 {
+		t.Errorf("second attribute of temp is %s = %v (error %v)\n", fill.Name(), fv, err)
+	}
+
+	if o.maxOpen > 2 {
+		t.Errorf("%d files were open at once; expected at most 2\n", o.maxOpen)
+	}
+	if n := a.NumOpen(); n != o.numOpen() {
+		t.Errorf("NumOpen returned %d; %d files are open\n", n, o.numOpen())
+	}
+	if err := v.ReadSlice(make([]float64, 2), []uint64{8, 0}, []uint64{2, 1}); err == nil {
+		t.Errorf("ReadSlice out of range succeeded\n")
+	}
+	if err := v.ReadSlice(make([]int32, 2), []uint64{0, 0}, []uint64{2, 1}); err == nil {
+		t.Errorf("ReadSlice into slice of the wrong type succeeded\n")
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+	if n := o.numOpen(); n != 0 {
+		t.Errorf("%d files are open after Close\n", n)
+	}
+	if err := v.Read(data); err == nil {
+		t.Errorf("Read after Close succeeded\n")
+	}
+	if _, err := v.Attr("units").Len(); err == nil {
+		t.Errorf("reading an attribute after Close succeeded\n")
+	}
+}
+
+func TestAggregateSchema(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(f *netcdftest.Fixture)
+		err    string
+	}{
+		{"type", func(f *netcdftest.Fixture) { f.Vars[1].Data = []int16{10, 20} },
+			"day1: variable \"x\" has type SHORT; expected INT"},
+		{"dims", func(f *netcdftest.Fixture) { f.Vars[1].Dims = []string{"time"}; f.Vars[1].Data = []int32{1, 2} },
+			"day1: variable \"x\" has dimensions [time]; expected [x]"},
+		{"length", func(f *netcdftest.Fixture) {
+			f.Dims[1].Len = 1
+			f.Vars[1].Data = []int32{10}
+			f.Vars[2].Data = []float64{1, 2}
+		}, "day1: dimension \"x\" of variable \"x\" has length 1; expected 2"},
+		{"vars", func(f *netcdftest.Fixture) { f.Vars = f.Vars[:2] },
+			"day1: 2 variables; day0 has 3"},
+		{"name", func(f *netcdftest.Fixture) { f.Vars[0].Name = "t" },
+			"day1: variable \"t\" isn't in day0"},
+		{"missing dim", func(f *netcdftest.Fixture) {
+			f.Dims[0].Name = "t"
+			for i := range f.Vars {
+				for j, d := range f.Vars[i].Dims {
+					if d == "time" {
+						f.Vars[i].Dims[j] = "t"
+					}
+				}
+			}
+		}, "day1: dimension \"time\""},
+	} {
+		o := &dayOpener{fixture: func(d int) netcdftest.Fixture {
+			f := dayFixture(d)
+			if d == 1 {
+				tc.modify(&f)
+			}
+			return f
+		}}
+		_, err := netcdf.AggregateWithOptions([]string{"day0", "day1"}, "time", &netcdf.AggregateOptions{Open: o.Open})
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: AggregateWithOptions returned %v; expected error containing %q\n", tc.name, err, tc.err)
+		}
+	}
+	if _, err := netcdf.Aggregate(nil, "time"); err == nil {
+		t.Errorf("Aggregate of no files succeeded\n")
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"context"
+	"fmt"
+)
+
+// Number is the set of Go types holding the values of variables, as used
+// by ReadFloat64s and friends. Byte slices hold the values of both UBYTE
+// and CHAR variables.
+type Number interface {
+	int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64 | float32 | float64
+}
+
+// Array is a multi-dimensional array of values of type T. It's a view of
+// a slice holding the values: arrays returned by View, Index and Transpose
+// share the values of the array they're taken from, so Set on one changes
+// the others.
+//
+// Indices are checked like for slices: methods given an index out of range
+// panic.
+type Array[T Number] struct {
+	data    []T
+	offset  int   // position of the first value in data
+	shape   []int // length along each dimension
+	strides []int // distance in data between neighbors along each dimension
+}
+
+// NewArray returns a new array of the given shape, whose values are 0.
+func NewArray[T Number](shape ...int) *Array[T] {
+	n := 1
+	for _, s := range shape {
+		if s < 0 {
+			panic(fmt.Sprintf("netcdf: negative length in shape %v", shape))
+		}
+		n *= s
+	}
+	return newArray(make([]T, n), shape)
+}
+
+// ArrayOf returns an array of the given shape whose values are those in
+// data, in row-major order. The array shares data; it doesn't copy it.
+func ArrayOf[T Number](data []T, shape ...int) (*Array[T], error) {
+	n := 1
+	for _, s := range shape {
+		if s < 0 {
+			return nil, fmt.Errorf("negative length in shape %v", shape)
+		}
+		n *= s
+	}
+	if n != len(data) {
+		return nil, fmt.Errorf("data length %d doesn't match shape %v", len(data), shape)
+	}
+	return newArray(data, shape), nil
+}
+
+// newArray returns a row-major array of the given shape for data.
+func newArray[T Number](data []T, shape []int) *Array[T] {
+	a := &Array[T]{
+		data:    data,
+		shape:   append([]int(nil), shape...),
+		strides: make([]int, len(shape)),
+	}
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		a.strides[i] = stride
+		stride *= shape[i]
+	}
+	return a
+}
+
+// Shape returns the length of a along each dimension.
+func (a *Array[T]) Shape() []int {
+	return append([]int(nil), a.shape...)
+}
+
+// NDim returns the number of dimensions of a.
+func (a *Array[T]) NDim() int {
+	return len(a.shape)
+}
+
+// Len returns the number of values in a.
+func (a *Array[T]) Len() int {
+	n := 1
+	for _, s := range a.shape {
+		n *= s
+	}
+	return n
+}
+
+// pos returns the position in a.data of the value at index idx.
+func (a *Array[T]) pos(idx []int) int {
+	if len(idx) != len(a.shape) {
+		panic(fmt.Sprintf("netcdf: index %v for array of %d dimensions", idx, len(a.shape)))
+	}
+	p := a.offset
+	for i, n := range idx {
+		if n < 0 || n >= a.shape[i] {
+			panic(fmt.Sprintf("netcdf: index %v out of range for shape %v", idx, a.shape))
+		}
+		p += n * a.strides[i]
+	}
+	return p
+}
+
+// At returns the value at index idx.
+func (a *Array[T]) At(idx ...int) T {
+	return a.data[a.pos(idx)]
+}
+
+// Set sets the value at index idx to val.
+func (a *Array[T]) Set(val T, idx ...int) {
+	a.data[a.pos(idx)] = val
+}
+
+// Range selects the indices Start, Start+Step, Start+2*Step... that are
+// less than Stop along a dimension. A Step of 0 means 1.
+type Range struct {
+	Start, Stop, Step int
+}
+
+// View returns the view of a selected by the ranges, which are for the
+// first dimensions of a; the remaining dimensions are selected entirely.
+// The view shares the values of a.
+func (a *Array[T]) View(ranges ...Range) *Array[T] {
+	if len(ranges) > len(a.shape) {
+		panic(fmt.Sprintf("netcdf: %d ranges for array of %d dimensions", len(ranges), len(a.shape)))
+	}
+	v := &Array[T]{
+		data:    a.data,
+		offset:  a.offset,
+		shape:   a.Shape(),
+		strides: append([]int(nil), a.strides...),
+	}
+	for i, r := range ranges {
+		if r.Step == 0 {
+			r.Step = 1
+		}
+		if r.Step < 0 || r.Start < 0 || r.Stop > a.shape[i] || r.Start > r.Stop {
+			panic(fmt.Sprintf("netcdf: range %+v out of range for dimension %d of length %d", r, i, a.shape[i]))
+		}
+		v.offset += r.Start * a.strides[i]
+		v.shape[i] = (r.Stop - r.Start + r.Step - 1) / r.Step
+		v.strides[i] = a.strides[i] * r.Step
+	}
+	return v
+}
+
+// Index returns the view of a at index i along dimension dim, which has
+// one dimension less than a. The view shares the values of a.
+func (a *Array[T]) Index(dim, i int) *Array[T] {
+	if dim < 0 || dim >= len(a.shape) || i < 0 || i >= a.shape[dim] {
+		panic(fmt.Sprintf("netcdf: index %d of dimension %d out of range for shape %v", i, dim, a.shape))
+	}
+	v := &Array[T]{
+		data:    a.data,
+		offset:  a.offset + i*a.strides[dim],
+		shape:   append(a.Shape()[:dim], a.shape[dim+1:]...),
+		strides: append(append([]int(nil), a.strides[:dim]...), a.strides[dim+1:]...),
+	}
+	return v
+}
+
+// Transpose returns the view of a whose dimensions are those of a in the
+// order given by axes, which is a permutation of 0, 1, ... a.NDim()-1. No
+// axes means the reverse order. The view shares the values of a.
+func (a *Array[T]) Transpose(axes ...int) *Array[T] {
+	n := len(a.shape)
+	if len(axes) == 0 {
+		axes = make([]int, n)
+		for i := range axes {
+			axes[i] = n - 1 - i
+		}
+	}
+	if len(axes) != n {
+		panic(fmt.Sprintf("netcdf: axes %v for array of %d dimensions", axes, n))
+	}
+	v := &Array[T]{
+		data:    a.data,
+		offset:  a.offset,
+		shape:   make([]int, n),
+		strides: make([]int, n),
+	}
+	seen := make([]bool, n)
+	for i, ax := range axes {
+		if ax < 0 || ax >= n || seen[ax] {
+			panic(fmt.Sprintf("netcdf: axes %v aren't a permutation", axes))
+		}
+		seen[ax] = true
+		v.shape[i] = a.shape[ax]
+		v.strides[i] = a.strides[ax]
+	}
+	return v
+}
+
+// contiguous reports whether the values of a are consecutive in a.data,
+// in row-major order.
+func (a *Array[T]) contiguous() bool {
+	stride := 1
+	for i := len(a.shape) - 1; i >= 0; i-- {
+		if a.shape[i] != 1 && a.strides[i] != stride {
+			return false
+		}
+		stride *= a.shape[i]
+	}
+	return true
+}
+
+// Reshape returns an array with the values of a in row-major order, and
+// the given shape. It shares the values of a if they're in row-major order
+// already, as in arrays returned by NewArray, and copies them otherwise.
+func (a *Array[T]) Reshape(shape ...int) (*Array[T], error) {
+	var data []T
+	if a.contiguous() {
+		data = a.data[a.offset : a.offset+a.Len()]
+	} else {
+		data = a.Values()
+	}
+	b, err := ArrayOf(data, shape...)
+	if err != nil {
+		return nil, fmt.Errorf("can't reshape array of shape %v: %v", a.shape, err)
+	}
+	return b, nil
+}
+
+// Each calls f with the index and the value of each value of a, in
+// row-major order. F must not modify idx, which is reused between calls.
+func (a *Array[T]) Each(f func(idx []int, val T)) {
+	if a.Len() == 0 {
+		return
+	}
+	idx := make([]int, len(a.shape))
+	p := a.offset
+	for {
+		f(idx, a.data[p])
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			p += a.strides[k]
+			if idx[k] < a.shape[k] {
+				break
+			}
+			p -= idx[k] * a.strides[k]
+			idx[k] = 0
+		}
+		if k < 0 {
+			return
+		}
+	}
+}
+
+// Values returns a new slice holding the values of a in row-major order.
+func (a *Array[T]) Values() []T {
+	vals := make([]T, 0, a.Len())
+	a.Each(func(_ []int, val T) {
+		vals = append(vals, val)
+	})
+	return vals
+}
+
+// Copy returns a new array with the shape and the values of a, which
+// doesn't share the values of a.
+func (a *Array[T]) Copy() *Array[T] {
+	return newArray(a.Values(), a.shape)
+}
+
+// ReadArray reads the entire variable v into a new array of the shape of
+// v. T must be the Go type of the type of v (e.g. float64 for DOUBLE).
+func ReadArray[T Number](v Var) (*Array[T], error) {
+	shape, err := v.LenDims()
+	if err != nil {
+		return nil, err
+	}
+	return ReadArraySlice[T](v, make([]uint64, len(shape)), shape)
+}
+
+// ReadArraySlice reads the slice of variable v specified by start and count
+// into a new array of shape count.
+func ReadArraySlice[T Number](v Var, start, count []uint64) (*Array[T], error) {
+	data := make([]T, product(count))
+	if _, err := v.ReadSliceCtx(context.Background(), data, start, count, nil); err != nil {
+		return nil, err
+	}
+	return newArray(data, intShape(count)), nil
+}
+
+// WriteArray writes a as the entire data of variable v, which must have
+// the shape of a.
+func WriteArray[T Number](v Var, a *Array[T]) error {
+	shape, err := v.LenDims()
+	if err != nil {
+		return err
+	}
+	if !equalShape(a.shape, shape) {
+		return fmt.Errorf("array of shape %v for variable of shape %v", a.shape, shape)
+	}
+	return WriteArraySlice(v, a, make([]uint64, len(shape)))
+}
+
+// WriteArraySlice writes a as the slice of variable v starting at start,
+// whose shape is that of a.
+func WriteArraySlice[T Number](v Var, a *Array[T], start []uint64) error {
+	count := make([]uint64, len(a.shape))
+	for i, n := range a.shape {
+		count[i] = uint64(n)
+	}
+	_, err := v.WriteSliceCtx(context.Background(), a.Values(), start, count, nil)
+	return err
+}
+
+func intShape(shape []uint64) []int {
+	s := make([]int, len(shape))
+	for i, n := range shape {
+		s[i] = int(n)
+	}
+	return s
+}
+
+func equalShape(a []int, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if uint64(a[i]) != b[i] {
+			return false
+		}
+	}
+	return true
+}
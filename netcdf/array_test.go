@@ -0,0 +1,159 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+func TestArray(t *testing.T) {
+	a, err := netcdf.ArrayOf([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, 3, 4)
+	if err != nil {
+		t.Fatalf("ArrayOf failed: %v\n", err)
+	}
+	if a.NDim() != 2 || a.Len() != 12 || !reflect.DeepEqual(a.Shape(), []int{3, 4}) {
+		t.Errorf("array has %d dimensions, %d values and shape %v\n", a.NDim(), a.Len(), a.Shape())
+	}
+	if v := a.At(1, 2); v != 6 {
+		t.Errorf("At(1, 2) is %v; expected 6\n", v)
+	}
+
+	view := a.View(netcdf.Range{Start: 0, Stop: 3, Step: 2}, netcdf.Range{Start: 1, Stop: 4})
+	if s, want := view.Values(), []int32{1, 2, 3, 9, 10, 11}; !reflect.DeepEqual(s, want) {
+		t.Errorf("view is %v; expected %v\n", s, want)
+	}
+	view.Set(-1, 1, 0)
+	if v := a.At(2, 1); v != -1 {
+		t.Errorf("Set on a view didn't change the array: At(2, 1) is %v\n", v)
+	}
+
+	row := a.Index(0, 1)
+	if s, want := row.Values(), []int32{4, 5, 6, 7}; !reflect.DeepEqual(s, want) {
+		t.Errorf("row 1 is %v; expected %v\n", s, want)
+	}
+	col := a.Index(1, 1)
+	if s, want := col.Values(), []int32{1, 5, -1}; !reflect.DeepEqual(s, want) {
+		t.Errorf("column 1 is %v; expected %v\n", s, want)
+	}
+
+	tr := a.Transpose()
+	if !reflect.DeepEqual(tr.Shape(), []int{4, 3}) || tr.At(3, 1) != 7 {
+		t.Errorf("transpose has shape %v and At(3, 1) = %v\n", tr.Shape(), tr.At(3, 1))
+	}
+	var idxs [][]int
+	tr.View(netcdf.Range{Start: 0, Stop: 2}).Each(func(idx []int, val int32) {
+		if val != a.At(idx[1], idx[0]) {
+			t.Errorf("value at %v of transpose is %v\n", idx, val)
+		}
+		idxs = append(idxs, append([]int(nil), idx...))
+	})
+	if want := [][]int{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}}; !reflect.DeepEqual(idxs, want) {
+		t.Errorf("Each visited %v; expected %v\n", idxs, want)
+	}
+
+	r, err := tr.Reshape(2, 6)
+	if err != nil {
+		t.Fatalf("Reshape failed: %v\n", err)
+	}
+	if s, want := r.Values(), []int32{0, 4, 8, 1, 5, -1, 2, 6, 10, 3, 7, 11}; !reflect.DeepEqual(s, want) {
+		t.Errorf("reshaped transpose is %v; expected %v\n", s, want)
+	}
+	r.Set(100, 0, 0)
+	if a.At(0, 0) != 0 {
+		t.Errorf("reshaped transpose shares values with the array\n")
+	}
+	r, err = a.Reshape(12)
+	if err != nil {
+		t.Fatalf("Reshape failed: %v\n", err)
+	}
+	r.Set(100, 0)
+	if a.At(0, 0) != 100 {
+		t.Errorf("reshaped array doesn't share values with the array\n")
+	}
+	if _, err := a.Reshape(5); err == nil {
+		t.Errorf("Reshape to a different length succeeded\n")
+	}
+	c := a.Copy()
+	c.Set(0, 0, 0)
+	if a.At(0, 0) != 100 {
+		t.Errorf("copy shares values with the array\n")
+	}
+
+	if _, err := netcdf.ArrayOf([]int32{1, 2}, 3); err == nil {
+		t.Errorf("ArrayOf with wrong shape succeeded\n")
+	}
+	z := netcdf.NewArray[float64]()
+	if z.Len() != 1 || z.At() != 0 {
+		t.Errorf("scalar array has %d values\n", z.Len())
+	}
+}
+
+func TestArrayPanics(t *testing.T) {
+	a := netcdf.NewArray[uint8](2, 3)
+	for name, f := range map[string]func(){
+		"At out of range":       func() { a.At(2, 0) },
+		"At with too few":       func() { a.At(1) },
+		"View out of range":     func() { a.View(netcdf.Range{Start: 0, Stop: 3}) },
+		"Index out of range":    func() { a.Index(2, 0) },
+		"Transpose bad axes":    func() { a.Transpose(0, 0) },
+		"NewArray bad shape":    func() { netcdf.NewArray[int8](-1) },
+		"View too many ranges":  func() { a.View(netcdf.Range{}, netcdf.Range{}, netcdf.Range{}) },
+		"Set with too many idx": func() { a.Set(1, 0, 0, 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s didn't panic\n", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestReadWriteArray(t *testing.T) {
+	ds := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "y", Len: 2}, {Name: "x", Len: 3}},
+		Vars: []netcdftest.Var{{Name: "v", Type: netcdf.FLOAT, Dims: []string{"y", "x"}}},
+	})
+	defer ds.Close()
+	v, err := ds.Var("v")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	a, _ := netcdf.ArrayOf([]float32{1, 2, 3, 4, 5, 6}, 3, 2)
+	if err := netcdf.WriteArray(v, a); err == nil {
+		t.Errorf("WriteArray with the wrong shape succeeded\n")
+	}
+	if err := netcdf.WriteArray(v, a.Transpose()); err != nil {
+		t.Fatalf("WriteArray failed: %v\n", err)
+	}
+	b, err := netcdf.ReadArray[float32](v)
+	if err != nil {
+		t.Fatalf("ReadArray failed: %v\n", err)
+	}
+	if s, want := b.Values(), []float32{1, 3, 5, 2, 4, 6}; !reflect.DeepEqual(s, want) || !reflect.DeepEqual(b.Shape(), []int{2, 3}) {
+		t.Errorf("ReadArray returned %v of shape %v; expected %v\n", s, b.Shape(), want)
+	}
+	if _, err := netcdf.ReadArray[float64](v); err == nil {
+		t.Errorf("ReadArray of the wrong type succeeded\n")
+	}
+
+	col, _ := netcdf.ArrayOf([]float32{-1, -2}, 2, 1)
+	if err := netcdf.WriteArraySlice(v, col, []uint64{0, 2}); err != nil {
+		t.Fatalf("WriteArraySlice failed: %v\n", err)
+	}
+	b, err = netcdf.ReadArraySlice[float32](v, []uint64{0, 1}, []uint64{2, 2})
+	if err != nil {
+		t.Fatalf("ReadArraySlice failed: %v\n", err)
+	}
+	if s, want := b.Values(), []float32{3, -1, 4, -2}; !reflect.DeepEqual(s, want) {
+		t.Errorf("ReadArraySlice returned %v; expected %v\n", s, want)
+	}
+}
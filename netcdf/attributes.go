@@ -4,13 +4,7 @@
 
 package netcdf
 
-// #include <stdlib.h>
-// #include <netcdf.h>
-import "C"
-
-import (
-	"unsafe"
-)
+import "github.com/fhs/go-netcdf/netcdf/internal/driver"
 
 // Attr represents an attribute associated with a variable.
 type Attr struct {
@@ -25,24 +19,22 @@ func (a Attr) Name() string {
 
 // Type returns the data type of attribute a.
 func (a Attr) Type() (t Type, err error) {
-	// TODO: convert a.name to CString only once instead of in
-	// each method of a.
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	var ct C.nc_type
-	err = newError(C.nc_inq_atttype(C.int(a.v.ds), C.int(a.v.id), cname, &ct))
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return
+	}
+	ct, err := d.AttrType(a.v.id, a.name)
 	t = Type(ct)
 	return
 }
 
 // Len returns the length of the attribute value.
 func (a Attr) Len() (n uint64, err error) {
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	var cn C.size_t
-	err = newError(C.nc_inq_attlen(C.int(a.v.ds), C.int(a.v.id), cname, &cn))
-	n = uint64(cn)
-	return
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.AttrLen(a.v.id, a.name)
 }
 
 // Attr returns attribute named name. If the attribute does not yet exist,
@@ -53,20 +45,59 @@ func (v Var) Attr(name string) (a Attr) {
 
 // AttrN returns attribute for attribute number n.
 func (v Var) AttrN(n int) (a Attr, err error) {
-	buf := C.CString(string(make([]byte, C.NC_MAX_NAME+1)))
-	defer C.free(unsafe.Pointer(buf))
-	err = newError(C.nc_inq_attname(C.int(v.ds), C.int(v.id), C.int(n), buf))
-	a = Attr{v: v, name: C.GoString(buf)}
+	d, err := v.ds.driver()
+	if err != nil {
+		return
+	}
+	name, err := d.AttrName(v.id, n)
+	a = Attr{v: v, name: name}
 	return
 }
 
 // Attr returns global attribute named name. If the attribute does not yet
 // exist, it'll be created once it's written.
 func (ds Dataset) Attr(name string) (a Attr) {
-	return Var{ds, C.NC_GLOBAL}.Attr(name)
+	return ds.globals().Attr(name)
 }
 
 // AttrN returns global attribute for attribute number n.
 func (ds Dataset) AttrN(n int) (a Attr, err error) {
-	return Var{ds, C.NC_GLOBAL}.AttrN(n)
+	return ds.globals().AttrN(n)
+}
+
+// globals returns a Var standing for the global attributes of ds.
+func (ds Dataset) globals() Var {
+	return Var{ds, driver.Global}
+}
+
+// copyAttr copies attribute a into the attributes of variable v, which
+// may be in a different dataset.
+func copyAttr(v Var, a Attr) error {
+	src, err := a.v.ds.driver()
+	if err != nil {
+		return err
+	}
+	dst, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	if ok, err := copyDriverAttr(src, a.v.id, a.name, dst, v.id); ok {
+		return err
+	}
+	t, err := src.AttrType(a.v.id, a.name)
+	if err != nil {
+		return err
+	}
+	n, err := src.AttrLen(a.v.id, a.name)
+	if err != nil {
+		return err
+	}
+	val, err := makeSlice(Type(t), n)
+	if err != nil {
+		return err
+	}
+	if err := src.GetAttr(a.v.id, a.name, t, val); err != nil {
+		return err
+	}
+	return dst.PutAttr(v.id, a.name, t, val)
 }
@@ -0,0 +1,166 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package cdf reads and writes netCDF datasets in the classic format family
+// (CDF-1, CDF-2 and CDF-5) without using the netCDF C library.
+//
+// Unlike the C library, this package doesn't use cgo, so it can be used in
+// programs built with CGO_ENABLED=0. Its datasets are netcdf.Dataset
+// values, whose storage is an io.ReaderAt or an io.WriteSeeker instead of
+// the C library. Importing this package also makes netcdf.OpenFile and
+// netcdf.CreateFile use it in programs built without cgo:
+//
+//	import _ "github.com/fhs/go-netcdf/netcdf/cdf"
+//
+// The classic format is documented here:
+// https://www.unidata.ucar.edu/software/netcdf/docs/file_format_specifications.html
+package cdf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// Format is a version of the classic format.
+type Format int
+
+// Versions of the classic format.
+const (
+	CDF1 Format = 1 // classic format
+	CDF2 Format = 2 // 64-bit offset format
+	CDF5 Format = 5 // 64-bit data format
+)
+
+// String converts a Format to its string representation.
+func (f Format) String() string {
+	return fmt.Sprintf("CDF-%d", int(f))
+}
+
+// dataMode is NC_64BIT_DATA, the file mode of the C library for creating
+// CDF-5 datasets.
+const dataMode = netcdf.FileMode(0x20)
+
+func init() {
+	driver.CreateFile = func(path string, mode int) (driver.Dataset, error) {
+		m := netcdf.FileMode(mode)
+		if m&netcdf.NETCDF4 != 0 {
+			return nil, errNetCDF4
+		}
+		f := CDF1
+		switch {
+		case m&dataMode != 0:
+			f = CDF5
+		case m&netcdf.OFFSET_64BIT != 0:
+			f = CDF2
+		}
+		flag := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+		if m&netcdf.NOCLOBBER != 0 {
+			flag |= os.O_EXCL
+		}
+		ds, err := createFile(path, flag, f)
+		if os.IsExist(err) {
+			return nil, netcdf.EEXIST
+		}
+		return ds, err
+	}
+	driver.OpenFile = func(path string, mode int) (driver.Dataset, error) {
+		if netcdf.FileMode(mode)&netcdf.WRITE != 0 {
+			return nil, netcdf.EPERM
+		}
+		return openFile(path)
+	}
+}
+
+// size returns the size in bytes of a value of type t,
+// or 0 for types that the classic format doesn't support.
+func size(t netcdf.Type) uint64 {
+	switch t {
+	case netcdf.BYTE, netcdf.CHAR, netcdf.UBYTE:
+		return 1
+	case netcdf.SHORT, netcdf.USHORT:
+		return 2
+	case netcdf.INT, netcdf.FLOAT, netcdf.UINT:
+		return 4
+	case netcdf.DOUBLE, netcdf.INT64, netcdf.UINT64:
+		return 8
+	}
+	return 0
+}
+
+// goTypes maps the types of the classic format to Go types.
+var goTypes = map[netcdf.Type]reflect.Type{
+	netcdf.BYTE:   reflect.TypeOf(int8(0)),
+	netcdf.CHAR:   reflect.TypeOf(byte(0)),
+	netcdf.SHORT:  reflect.TypeOf(int16(0)),
+	netcdf.INT:    reflect.TypeOf(int32(0)),
+	netcdf.FLOAT:  reflect.TypeOf(float32(0)),
+	netcdf.DOUBLE: reflect.TypeOf(float64(0)),
+	netcdf.UBYTE:  reflect.TypeOf(uint8(0)),
+	netcdf.USHORT: reflect.TypeOf(uint16(0)),
+	netcdf.UINT:   reflect.TypeOf(uint32(0)),
+	netcdf.INT64:  reflect.TypeOf(int64(0)),
+	netcdf.UINT64: reflect.TypeOf(uint64(0)),
+}
+
+var (
+	// errWriteOnly is returned when reading data from a dataset created
+	// with a writer that isn't an io.ReaderAt.
+	errWriteOnly = errors.New("cdf: dataset is write-only")
+
+	// errRedef is returned by Redef, since data would have to be moved
+	// to make room for new definitions.
+	errRedef = errors.New("cdf: definitions can't be changed after EndDef")
+
+	// errNetCDF4 is returned when creating a netCDF-4 dataset file.
+	errNetCDF4 = errors.New("cdf: netCDF-4 datasets aren't supported")
+)
+
+// checkConvert returns an error if values of type from can't be converted
+// to values of type to, or the reverse.
+func checkConvert(from, to netcdf.Type) error {
+	if _, ok := goTypes[from]; !ok {
+		return netcdf.EBADTYPE
+	}
+	if (from == netcdf.CHAR) != (to == netcdf.CHAR) {
+		return netcdf.ECHAR
+	}
+	return nil
+}
+
+// buffer returns slice data if it holds values of type t, and otherwise a
+// new slice of n values of type t.
+func buffer(data interface{}, t netcdf.Type, n int) interface{} {
+	gt := goTypes[t]
+	if reflect.TypeOf(data).Elem() == gt {
+		return data
+	}
+	return reflect.MakeSlice(reflect.SliceOf(gt), n, n).Interface()
+}
+
+// convertInto sets the first n values of slice dst to those of slice src,
+// converted to the type of dst, unless they have the same type, as dst and
+// the buffer returned for it do. Out of range conversions aren't reported.
+func convertInto(dst, src interface{}, n int) {
+	d, s := reflect.ValueOf(dst), reflect.ValueOf(src)
+	if d.Type() == s.Type() {
+		return
+	}
+	et := d.Type().Elem()
+	for i := 0; i < n; i++ {
+		d.Index(i).Set(s.Index(i).Convert(et))
+	}
+}
+
+func product(nums []uint64) (prod uint64) {
+	prod = 1
+	for _, i := range nums {
+		prod *= i
+	}
+	return
+}
@@ -0,0 +1,610 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package cdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// memFile is an in-memory io.WriteSeeker and io.ReaderAt.
+type memFile struct {
+	b   []byte
+	off int64
+}
+
+func (m *memFile) Write(p []byte) (int, error) {
+	if end := m.off + int64(len(p)); end > int64(len(m.b)) {
+		m.b = append(m.b, make([]byte, end-int64(len(m.b)))...)
+	}
+	copy(m.b[m.off:], p)
+	m.off += int64(len(p))
+	return len(p), nil
+}
+
+func (m *memFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += m.off
+	case io.SeekEnd:
+		offset += int64(len(m.b))
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset %d", offset)
+	}
+	m.off = offset
+	return offset, nil
+}
+
+func (m *memFile) ReadAt(p []byte, off int64) (int, error) {
+	return bytes.NewReader(m.b).ReadAt(p, off)
+}
+
+// testFile returns a dataset with fixed, record and scalar variables,
+// written in format f.
+func testFile(t *testing.T, f Format) []byte {
+	m := &memFile{}
+	ds, err := Create(m, f)
+	if err != nil {
+		t.Fatalf("Create failed: %v\n", err)
+	}
+	fatal := func(what string, err error) {
+		if err != nil {
+			t.Fatalf("%v: %s failed: %v\n", f, what, err)
+		}
+	}
+	x, err := ds.AddDim("x", 3)
+	fatal("AddDim", err)
+	y, err := ds.AddDim("y", 4)
+	fatal("AddDim", err)
+	time, err := ds.AddDim("time", 0)
+	fatal("AddDim", err)
+	fatal("WriteBytes", ds.Attr("title").WriteBytes([]byte("gopher test")))
+
+	fixed, err := ds.AddVar("fixed", netcdf.INT, []netcdf.Dim{x, y})
+	fatal("AddVar", err)
+	fatal("WriteBytes", fixed.Attr("units").WriteBytes([]byte("furlongs")))
+	fatal("WriteInt32s", fixed.Attr("range").WriteInt32s([]int32{0, 11}))
+	rec, err := ds.AddVar("rec", netcdf.SHORT, []netcdf.Dim{time, y})
+	fatal("AddVar", err)
+	scalar, err := ds.AddVar("scalar", netcdf.FLOAT, nil)
+	fatal("AddVar", err)
+	rec2, err := ds.AddVar("rec2", netcdf.BYTE, []netcdf.Dim{time, x})
+	fatal("AddVar", err)
+	text, err := ds.AddVar("text", netcdf.CHAR, []netcdf.Dim{x})
+	fatal("AddVar", err)
+	var big netcdf.Var
+	if f == CDF5 {
+		big, err = ds.AddVar("big", netcdf.UINT64, []netcdf.Dim{time, x})
+		fatal("AddVar", err)
+	}
+	fatal("EndDef", ds.EndDef())
+
+	fatal("WriteInt32s", fixed.WriteInt32s([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}))
+	// Records are added by writing past the end of the variables, and
+	// then filled by writing slices.
+	fatal("WriteInt16At", rec.WriteInt16At([]uint64{1, 3}, 23))
+	fatal("WriteInt16Slice", rec.WriteInt16Slice([]int16{10, 11, 12, 13, 20, 21, 22, 23},
+		[]uint64{0, 0}, []uint64{2, 4}))
+	fatal("WriteFloat32At", scalar.WriteFloat32At(nil, 1.5))
+	fatal("WriteInt8At", rec2.WriteInt8At([]uint64{1, 2}, 3))
+	fatal("WriteInt8Slice", rec2.WriteInt8Slice([]int8{-1, -2, -3, 1, 2, 3},
+		[]uint64{0, 0}, []uint64{2, 3}))
+	fatal("WriteBytes", text.WriteBytes([]byte("abc")))
+	if f == CDF5 {
+		fatal("WriteUint64At", big.WriteUint64At([]uint64{1, 2}, 6<<40))
+		fatal("WriteUint64Slice", big.WriteUint64Slice(
+			[]uint64{1 << 40, 2 << 40, 3 << 40, 4 << 40, 5 << 40, 6 << 40},
+			[]uint64{0, 0}, []uint64{2, 3}))
+	}
+	fatal("Close", ds.Close())
+	return m.b
+}
+
+func openBytes(t *testing.T, b []byte) netcdf.Dataset {
+	ds, err := Open(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	return ds
+}
+
+func TestOpen(t *testing.T) {
+	for _, f := range []Format{CDF1, CDF2, CDF5} {
+		b := testFile(t, f)
+		if got := Format(b[3]); got != f {
+			t.Errorf("format is %v; expected %v\n", got, f)
+		}
+		ds := openBytes(t, b)
+		if n, err := ds.NDims(); err != nil || n != 3 {
+			t.Errorf("%v: NDims is %v, %v; expected 3\n", f, n, err)
+		}
+		ud, err := ds.UnlimitedDims()
+		if err != nil || len(ud) != 1 || ud[0].ID() != 2 {
+			t.Errorf("%v: UnlimitedDims is %v, %v; expected dimension 2\n", f, ud, err)
+		}
+		if n, err := ud[0].Len(); err != nil || n != 2 {
+			t.Errorf("%v: length of unlimited dimension is %v, %v; expected 2\n", f, n, err)
+		}
+		title, err := netcdf.GetBytes(ds.Attr("title"))
+		if err != nil || string(title) != "gopher test" {
+			t.Errorf("%v: title is %q, %v\n", f, title, err)
+		}
+
+		v, err := ds.Var("fixed")
+		if err != nil {
+			t.Fatalf("%v: Var failed: %v\n", f, err)
+		}
+		shape, err := v.LenDims()
+		if err != nil || !reflect.DeepEqual(shape, []uint64{3, 4}) {
+			t.Errorf("%v: LenDims is %v, %v; expected [3 4]\n", f, shape, err)
+		}
+		ints, err := netcdf.GetInt32s(v)
+		if err != nil || !reflect.DeepEqual(ints, []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}) {
+			t.Errorf("%v: fixed is %v, %v\n", f, ints, err)
+		}
+		ints = make([]int32, 4)
+		if err := v.ReadInt32StridedSlice(ints, []uint64{0, 1}, []uint64{2, 2}, []int64{2, 2}); err != nil {
+			t.Errorf("%v: ReadInt32StridedSlice failed: %v\n", f, err)
+		} else if want := []int32{1, 3, 9, 11}; !reflect.DeepEqual(ints, want) {
+			t.Errorf("%v: strided slice is %v; expected %v\n", f, ints, want)
+		}
+		units, err := netcdf.GetBytes(v.Attr("units"))
+		if err != nil || string(units) != "furlongs" {
+			t.Errorf("%v: units is %q, %v\n", f, units, err)
+		}
+		a, err := v.AttrN(1)
+		if err != nil || a.Name() != "range" {
+			t.Errorf("%v: AttrN(1) is %v, %v\n", f, a.Name(), err)
+		}
+		if r, err := netcdf.GetInt32s(a); err != nil || !reflect.DeepEqual(r, []int32{0, 11}) {
+			t.Errorf("%v: range is %v, %v\n", f, r, err)
+		}
+
+		v, err = ds.Var("rec")
+		if err != nil {
+			t.Fatalf("%v: Var failed: %v\n", f, err)
+		}
+		shorts, err := netcdf.GetInt16s(v)
+		if err != nil || !reflect.DeepEqual(shorts, []int16{10, 11, 12, 13, 20, 21, 22, 23}) {
+			t.Errorf("%v: rec is %v, %v\n", f, shorts, err)
+		}
+		shorts = make([]int16, 4)
+		if err := v.ReadInt16Slice(shorts, []uint64{0, 1}, []uint64{2, 2}); err != nil {
+			t.Errorf("%v: ReadInt16Slice failed: %v\n", f, err)
+		} else if want := []int16{11, 12, 21, 22}; !reflect.DeepEqual(shorts, want) {
+			t.Errorf("%v: slice is %v; expected %v\n", f, shorts, want)
+		}
+		if val, err := v.ReadInt16At([]uint64{1, 3}); err != nil || val != 23 {
+			t.Errorf("%v: ReadInt16At is %v, %v; expected 23\n", f, val, err)
+		}
+
+		v, err = ds.Var("rec2")
+		if err != nil {
+			t.Fatalf("%v: Var failed: %v\n", f, err)
+		}
+		bytes, err := netcdf.GetInt8s(v)
+		if err != nil || !reflect.DeepEqual(bytes, []int8{-1, -2, -3, 1, 2, 3}) {
+			t.Errorf("%v: rec2 is %v, %v\n", f, bytes, err)
+		}
+
+		v, err = ds.Var("scalar")
+		if err != nil {
+			t.Fatalf("%v: Var failed: %v\n", f, err)
+		}
+		if val, err := v.ReadFloat32At(nil); err != nil || val != 1.5 {
+			t.Errorf("%v: scalar is %v, %v; expected 1.5\n", f, val, err)
+		}
+		if text, err := netcdf.GetBytes(ds.VarN(4)); err != nil || string(text) != "abc" {
+			t.Errorf("%v: text is %q, %v\n", f, text, err)
+		}
+
+		if f == CDF5 {
+			v, err = ds.Var("big")
+			if err != nil {
+				t.Fatalf("%v: Var failed: %v\n", f, err)
+			}
+			big, err := netcdf.GetUint64s(v)
+			want := []uint64{1 << 40, 2 << 40, 3 << 40, 4 << 40, 5 << 40, 6 << 40}
+			if err != nil || !reflect.DeepEqual(big, want) {
+				t.Errorf("%v: big is %v, %v; expected %v\n", f, big, err, want)
+			}
+		}
+		if err := ds.Close(); err != nil {
+			t.Errorf("Close failed: %v\n", err)
+		}
+	}
+}
+
+func TestSingleRecordVar(t *testing.T) {
+	// A single record variable has no padding between records, and
+	// the number of records may be left for the reader to compute.
+	data := []uint8{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	for _, numRecs := range []uint64{3, streaming} {
+		m := &memFile{}
+		ds, err := Create(m, CDF5)
+		if err != nil {
+			t.Fatalf("Create failed: %v\n", err)
+		}
+		time, _ := ds.AddDim("time", 0)
+		x, _ := ds.AddDim("x", 3)
+		v, err := ds.AddVar("gopher", netcdf.UBYTE, []netcdf.Dim{time, x})
+		if err != nil {
+			t.Fatalf("AddVar failed: %v\n", err)
+		}
+		if err := ds.EndDef(); err != nil {
+			t.Fatalf("EndDef failed: %v\n", err)
+		}
+		for r := uint64(0); r < 3; r++ {
+			if err := v.WriteUint8At([]uint64{r, 2}, data[3*r+2]); err != nil {
+				t.Fatalf("WriteUint8At failed: %v\n", err)
+			}
+			if err := v.WriteUint8Slice(data[3*r:], []uint64{r, 0}, []uint64{1, 3}); err != nil {
+				t.Fatalf("WriteUint8Slice failed: %v\n", err)
+			}
+		}
+		if err := ds.Close(); err != nil {
+			t.Fatalf("Close failed: %v\n", err)
+		}
+		d, err := open(bytes.NewReader(m.b), int64(len(m.b)))
+		if err != nil {
+			t.Fatalf("open failed: %v\n", err)
+		}
+		if begin := d.vars[0].begin; len(m.b) != int(begin)+9 {
+			t.Errorf("dataset size is %d; expected %d\n", len(m.b), begin+9)
+		}
+		if numRecs == streaming {
+			binary.BigEndian.PutUint64(m.b[4:], streaming)
+		}
+
+		ds = openBytes(t, m.b)
+		v, err = ds.Var("gopher")
+		if err != nil {
+			t.Fatalf("Var failed: %v\n", err)
+		}
+		got, err := netcdf.GetUint8s(v)
+		if err != nil || !reflect.DeepEqual(got, data) {
+			t.Errorf("data is %v, %v; expected %v\n", got, err, data)
+		}
+		col := make([]uint8, 3)
+		if err := v.ReadUint8Slice(col, []uint64{0, 1}, []uint64{3, 1}); err != nil {
+			t.Errorf("ReadUint8Slice failed: %v\n", err)
+		} else if want := []uint8{2, 5, 8}; !reflect.DeepEqual(col, want) {
+			t.Errorf("column is %v; expected %v\n", col, want)
+		}
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "cdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	if _, err := f.Write(testFile(t, CDF2)); err != nil {
+		t.Fatalf("writing temporary file failed: %v\n", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("closing temporary file failed: %v\n", err)
+	}
+
+	ds, err := OpenFile(f.Name())
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v\n", err)
+	}
+	v, err := ds.Var("fixed")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if val, err := v.ReadInt32At([]uint64{2, 3}); err != nil || val != 11 {
+		t.Errorf("ReadInt32At is %v, %v; expected 11\n", val, err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Errorf("Close failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Errorf("second Close returned %v; expected nil\n", err)
+	}
+	if _, err := v.ReadInt32At([]uint64{2, 3}); err != netcdf.ErrClosed {
+		t.Errorf("read after Close returned %v; expected %v\n", err, netcdf.ErrClosed)
+	}
+	if _, err := ds.Dim("x"); err != netcdf.ErrClosed {
+		t.Errorf("Dim after Close returned %v; expected %v\n", err, netcdf.ErrClosed)
+	}
+	if _, err := v.Attr("units").Len(); err != netcdf.ErrClosed {
+		t.Errorf("Attr.Len after Close returned %v; expected %v\n", err, netcdf.ErrClosed)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	b := testFile(t, CDF1)
+	if _, err := Open(bytes.NewReader(b[:3]), 3); err != netcdf.ENOTNC {
+		t.Errorf("Open of truncated magic returned %v; expected %v\n", err, netcdf.ENOTNC)
+	}
+	if _, err := Open(bytes.NewReader([]byte("CDF\x03")), 4); err != netcdf.ENOTNC {
+		t.Errorf("Open of unknown version returned %v; expected %v\n", err, netcdf.ENOTNC)
+	}
+	if _, err := Open(bytes.NewReader(b[:40]), 40); err == nil {
+		t.Errorf("Open of truncated header succeeded\n")
+	}
+
+	ds := openBytes(t, b)
+	if _, err := ds.Var("nonexistent"); err != netcdf.ENOTVAR {
+		t.Errorf("Var returned %v; expected %v\n", err, netcdf.ENOTVAR)
+	}
+	if _, err := ds.Dim("nonexistent"); err != netcdf.EBADDIM {
+		t.Errorf("Dim returned %v; expected %v\n", err, netcdf.EBADDIM)
+	}
+	if _, err := ds.Attr("nonexistent").Len(); err != netcdf.ENOTATT {
+		t.Errorf("Attr.Len returned %v; expected %v\n", err, netcdf.ENOTATT)
+	}
+	if _, err := ds.VarN(42).Type(); err != netcdf.ENOTVAR {
+		t.Errorf("Type of invalid variable returned %v; expected %v\n", err, netcdf.ENOTVAR)
+	}
+	v, err := ds.Var("fixed")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if err := v.ReadFloat64s(make([]float64, 12)); err == nil {
+		t.Errorf("reading netcdf.INT variable as netcdf.DOUBLE succeeded\n")
+	}
+	if err := v.ReadInt32s(make([]int32, 11)); err == nil {
+		t.Errorf("reading into short buffer succeeded\n")
+	}
+
+	// Package netcdf checks slices before calling the driver, so the
+	// driver is called directly.
+	d, err := open(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("open failed: %v\n", err)
+	}
+	if err := d.GetVars(0, int(netcdf.INT), []uint64{2, 2}, []uint64{2, 2}, nil, make([]int32, 4)); err != netcdf.EEDGE {
+		t.Errorf("reading past the end returned %v; expected %v\n", err, netcdf.EEDGE)
+	}
+	if _, err := v.ReadInt32At([]uint64{3, 0}); err != netcdf.EINVALCOORDS {
+		t.Errorf("reading at invalid index returned %v; expected %v\n", err, netcdf.EINVALCOORDS)
+	}
+	if err := d.GetVars(0, int(netcdf.INT), []uint64{0, 0}, []uint64{2, 2}, []int64{0, 1}, make([]int32, 4)); err != netcdf.ESTRIDE {
+		t.Errorf("reading with zero stride returned %v; expected %v\n", err, netcdf.ESTRIDE)
+	}
+}
+
+// TestHeaderOverflow opens CDF-5 headers whose sizes overflow 64 bits.
+func TestHeaderOverflow(t *testing.T) {
+	// cdf5 returns the header made of the given values, in the CDF-5
+	// encoding: strings are names and uint32 values are tags and types.
+	cdf5 := func(values ...interface{}) []byte {
+		var buf bytes.Buffer
+		buf.WriteString("CDF\x05")
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				binary.Write(&buf, binary.BigEndian, uint64(len(s)))
+				buf.WriteString(s)
+				buf.Write(make([]byte, pad4(uint64(len(s)))-uint64(len(s))))
+				continue
+			}
+			binary.Write(&buf, binary.BigEndian, v)
+		}
+		return buf.Bytes()
+	}
+	absent := []interface{}{uint32(tagAbsent), uint64(0)}
+
+	attr := cdf5(append([]interface{}{uint64(0)}, append(absent,
+		uint32(tagAttribute), uint64(1), "a", uint32(netcdf.SHORT), uint64(1<<63+2), uint32(0), uint32(tagAbsent), uint64(0))...)...)
+	if _, err := Open(bytes.NewReader(attr), int64(len(attr))); err == nil {
+		t.Errorf("Open of attribute with 2^63+2 values succeeded\n")
+	}
+
+	// A name of 1 GiB in a 40 byte file isn't allocated.
+	long := cdf5(uint64(0), uint32(tagDimension), uint64(1), uint64(1<<30))
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := Open(bytes.NewReader(long), int64(len(long))); err == nil {
+		t.Errorf("Open of truncated name of 1 GiB succeeded\n")
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Errorf("Open of truncated name of 1 GiB allocated %d bytes\n", n)
+	}
+
+	dims := []interface{}{uint64(0), uint32(tagDimension), uint64(2), "x", uint64(1 << 40), "y", uint64(1 << 40)}
+	dims = append(dims, absent...)
+	vars := append(dims, uint32(tagVariable), uint64(1), "v", uint64(2), uint64(0), uint64(1))
+	vars = append(vars, absent...)
+	vars = append(vars, uint32(netcdf.INT), uint64(0), uint64(0))
+	b := cdf5(vars...)
+	if _, err := Open(bytes.NewReader(b), int64(len(b))); err != netcdf.EVARSIZE {
+		t.Errorf("Open of variable of 2^82 bytes returned %v; expected %v\n", err, netcdf.EVARSIZE)
+	}
+}
+
+func TestCreateErrors(t *testing.T) {
+	if _, err := Create(&memFile{}, Format(3)); err != netcdf.EINVAL {
+		t.Errorf("Create with invalid format returned %v; expected %v\n", err, netcdf.EINVAL)
+	}
+	d, err := create(&memFile{}, CDF1)
+	if err != nil {
+		t.Fatalf("create failed: %v\n", err)
+	}
+	ds := driver.NewDataset(d, "").(netcdf.Dataset)
+	time, err := ds.AddDim("time", 0)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	x, err := ds.AddDim("x", 2)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	if _, err := ds.AddDim("time2", 0); err != netcdf.EUNLIMIT {
+		t.Errorf("adding second unlimited dimension returned %v; expected %v\n", err, netcdf.EUNLIMIT)
+	}
+	if _, err := ds.AddDim("x", 3); err != netcdf.ENAMEINUSE {
+		t.Errorf("adding duplicate dimension returned %v; expected %v\n", err, netcdf.ENAMEINUSE)
+	}
+	if _, err := ds.AddDim("", 3); err != netcdf.EBADNAME {
+		t.Errorf("adding unnamed dimension returned %v; expected %v\n", err, netcdf.EBADNAME)
+	}
+	if _, err := ds.AddVar("v", netcdf.UINT64, []netcdf.Dim{x}); err != netcdf.EBADTYPE {
+		t.Errorf("adding netcdf.UINT64 variable to CDF-1 dataset returned %v; expected %v\n", err, netcdf.EBADTYPE)
+	}
+	if _, err := ds.AddVar("v", netcdf.INT, []netcdf.Dim{x, time}); err != netcdf.EUNLIMPOS {
+		t.Errorf("adding variable with unlimited dimension last returned %v; expected %v\n", err, netcdf.EUNLIMPOS)
+	}
+	if _, err := ds.AddVar("v", netcdf.INT, []netcdf.Dim{ds.DimN(5)}); err != netcdf.EBADDIM {
+		t.Errorf("adding variable with invalid dimension returned %v; expected %v\n", err, netcdf.EBADDIM)
+	}
+	v, err := ds.AddVar("v", netcdf.INT, []netcdf.Dim{time, x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if _, err := ds.AddVar("v", netcdf.INT, nil); err != netcdf.ENAMEINUSE {
+		t.Errorf("adding duplicate variable returned %v; expected %v\n", err, netcdf.ENAMEINUSE)
+	}
+	if err := v.WriteInt32s([]int32{1, 2}); err != netcdf.EINDEFINE {
+		t.Errorf("writing in define mode returned %v; expected %v\n", err, netcdf.EINDEFINE)
+	}
+	if err := ds.EndDef(); err != nil {
+		t.Fatalf("EndDef failed: %v\n", err)
+	}
+	if err := ds.EndDef(); err != netcdf.ENOTINDEFINE {
+		t.Errorf("second EndDef returned %v; expected %v\n", err, netcdf.ENOTINDEFINE)
+	}
+	if _, err := ds.AddDim("y", 3); err != netcdf.ENOTINDEFINE {
+		t.Errorf("AddDim in data mode returned %v; expected %v\n", err, netcdf.ENOTINDEFINE)
+	}
+	if err := v.Attr("units").WriteBytes([]byte("K")); err != netcdf.ENOTINDEFINE {
+		t.Errorf("writing attribute in data mode returned %v; expected %v\n", err, netcdf.ENOTINDEFINE)
+	}
+	if err := d.PutVars(0, int(netcdf.INT), []uint64{0, 1}, []uint64{1, 2}, nil, []int32{1, 2}); err != netcdf.EEDGE {
+		t.Errorf("writing past the end returned %v; expected %v\n", err, netcdf.EEDGE)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+
+	ds = openBytes(t, testFile(t, CDF1))
+	if _, err := ds.AddDim("y", 3); err != netcdf.EPERM {
+		t.Errorf("AddDim on read-only dataset returned %v; expected %v\n", err, netcdf.EPERM)
+	}
+	if err := ds.VarN(0).WriteInt32At([]uint64{0, 0}, 1); err != netcdf.EPERM {
+		t.Errorf("writing to read-only dataset returned %v; expected %v\n", err, netcdf.EPERM)
+	}
+}
+
+func TestEndDefWithOptions(t *testing.T) {
+	m := &memFile{}
+	d, err := create(m, CDF2)
+	if err != nil {
+		t.Fatalf("create failed: %v\n", err)
+	}
+	ds := driver.NewDataset(d, "").(netcdf.Dataset)
+	time, _ := ds.AddDim("time", 0)
+	x, _ := ds.AddDim("x", 3)
+	fixed, err := ds.AddVar("fixed", netcdf.DOUBLE, []netcdf.Dim{x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	rec, err := ds.AddVar("rec", netcdf.SHORT, []netcdf.Dim{time, x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := ds.EndDefWithOptions(100, 512, 50, 1024); err != nil {
+		t.Fatalf("EndDefWithOptions failed: %v\n", err)
+	}
+	if err := fixed.WriteFloat64s([]float64{1, 2, 3}); err != nil {
+		t.Fatalf("WriteFloat64s failed: %v\n", err)
+	}
+	// Records are only partially written, leaving the rest as zeros.
+	if err := rec.WriteInt16At([]uint64{2, 1}, 42); err != nil {
+		t.Fatalf("WriteInt16At failed: %v\n", err)
+	}
+	hlen := len(d.encode())
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+
+	d, err = open(bytes.NewReader(m.b), int64(len(m.b)))
+	if err != nil {
+		t.Fatalf("open failed: %v\n", err)
+	}
+	h := d.header
+	if b := h.vars[0].begin; b != 512 || b < uint64(hlen)+100 {
+		t.Errorf("fixed variable begins at %d; expected 512\n", b)
+	}
+	if b := h.vars[1].begin; b != 1024 {
+		t.Errorf("record variable begins at %d; expected 1024\n", b)
+	}
+	if h.numRecs != 3 {
+		t.Errorf("number of records is %d; expected 3\n", h.numRecs)
+	}
+	if size, want := len(m.b), 1024+3*6; size != want {
+		t.Errorf("dataset size is %d; expected %d\n", size, want)
+	}
+	ds = openBytes(t, m.b)
+	doubles, err := netcdf.GetFloat64s(ds.VarN(0))
+	if err != nil || !reflect.DeepEqual(doubles, []float64{1, 2, 3}) {
+		t.Errorf("fixed is %v, %v\n", doubles, err)
+	}
+	shorts, err := netcdf.GetInt16s(ds.VarN(1))
+	if want := []int16{0, 0, 0, 0, 0, 0, 0, 42, 0}; err != nil || !reflect.DeepEqual(shorts, want) {
+		t.Errorf("rec is %v, %v; expected %v\n", shorts, err, want)
+	}
+}
+
+func TestCloseInDefineMode(t *testing.T) {
+	f, err := ioutil.TempFile("", "cdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	ds, err := CreateFile(f.Name(), CDF5)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %v\n", err)
+	}
+	x, _ := ds.AddDim("x", 2)
+	if _, err := ds.AddVar("v", netcdf.UINT, []netcdf.Dim{x}); err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := ds.Attr("title").WriteBytes([]byte("empty")); err != nil {
+		t.Fatalf("WriteBytes failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil || len(b) < 4 || Format(b[3]) != CDF5 {
+		t.Errorf("file doesn't start with the magic number of %v: %q, %v\n", CDF5, b, err)
+	}
+	ds, err = OpenFile(f.Name())
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v\n", err)
+	}
+	defer ds.Close()
+	uints, err := netcdf.GetUint32s(ds.VarN(0))
+	if err != nil || !reflect.DeepEqual(uints, []uint32{0, 0}) {
+		t.Errorf("v is %v, %v; expected [0 0]\n", uints, err)
+	}
+	title, err := netcdf.GetBytes(ds.Attr("title"))
+	if err != nil || string(title) != "empty" {
+		t.Errorf("title is %q, %v\n", title, err)
+	}
+}
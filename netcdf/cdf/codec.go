@@ -0,0 +1,192 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package cdf
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// decode converts the big-endian raw values in b into the values of slice
+// dst starting at index off.
+func decode(dst interface{}, off int, b []byte) {
+	switch dst := dst.(type) {
+	case []int8:
+		decodeInt8s(dst[off:], b)
+	case []byte:
+		decodeBytes(dst[off:], b)
+	case []int16:
+		decodeInt16s(dst[off:], b)
+	case []uint16:
+		decodeUint16s(dst[off:], b)
+	case []int32:
+		decodeInt32s(dst[off:], b)
+	case []uint32:
+		decodeUint32s(dst[off:], b)
+	case []float32:
+		decodeFloat32s(dst[off:], b)
+	case []int64:
+		decodeInt64s(dst[off:], b)
+	case []uint64:
+		decodeUint64s(dst[off:], b)
+	case []float64:
+		decodeFloat64s(dst[off:], b)
+	}
+}
+
+// encode converts the values of slice src starting at index off into
+// big-endian raw values filling b.
+func encode(b []byte, src interface{}, off int) {
+	switch src := src.(type) {
+	case []int8:
+		encodeInt8s(b, src[off:])
+	case []byte:
+		encodeBytes(b, src[off:])
+	case []int16:
+		encodeInt16s(b, src[off:])
+	case []uint16:
+		encodeUint16s(b, src[off:])
+	case []int32:
+		encodeInt32s(b, src[off:])
+	case []uint32:
+		encodeUint32s(b, src[off:])
+	case []float32:
+		encodeFloat32s(b, src[off:])
+	case []int64:
+		encodeInt64s(b, src[off:])
+	case []uint64:
+		encodeUint64s(b, src[off:])
+	case []float64:
+		encodeFloat64s(b, src[off:])
+	}
+}
+
+// The decode functions convert big-endian raw values in b into the first
+// values of dst, and the encode functions convert the first values of src
+// into big-endian raw values in b.
+
+func decodeBytes(dst []byte, b []byte) {
+	copy(dst, b)
+}
+
+func decodeInt8s(dst []int8, b []byte) {
+	for i, c := range b {
+		dst[i] = int8(c)
+	}
+}
+
+func decodeUint8s(dst []uint8, b []byte) {
+	copy(dst, b)
+}
+
+func decodeInt16s(dst []int16, b []byte) {
+	for i := range dst[:len(b)/2] {
+		dst[i] = int16(binary.BigEndian.Uint16(b[2*i:]))
+	}
+}
+
+func decodeUint16s(dst []uint16, b []byte) {
+	for i := range dst[:len(b)/2] {
+		dst[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+}
+
+func decodeInt32s(dst []int32, b []byte) {
+	for i := range dst[:len(b)/4] {
+		dst[i] = int32(binary.BigEndian.Uint32(b[4*i:]))
+	}
+}
+
+func decodeUint32s(dst []uint32, b []byte) {
+	for i := range dst[:len(b)/4] {
+		dst[i] = binary.BigEndian.Uint32(b[4*i:])
+	}
+}
+
+func decodeFloat32s(dst []float32, b []byte) {
+	for i := range dst[:len(b)/4] {
+		dst[i] = math.Float32frombits(binary.BigEndian.Uint32(b[4*i:]))
+	}
+}
+
+func decodeInt64s(dst []int64, b []byte) {
+	for i := range dst[:len(b)/8] {
+		dst[i] = int64(binary.BigEndian.Uint64(b[8*i:]))
+	}
+}
+
+func decodeUint64s(dst []uint64, b []byte) {
+	for i := range dst[:len(b)/8] {
+		dst[i] = binary.BigEndian.Uint64(b[8*i:])
+	}
+}
+
+func decodeFloat64s(dst []float64, b []byte) {
+	for i := range dst[:len(b)/8] {
+		dst[i] = math.Float64frombits(binary.BigEndian.Uint64(b[8*i:]))
+	}
+}
+
+func encodeBytes(b []byte, src []byte) {
+	copy(b, src)
+}
+
+func encodeInt8s(b []byte, src []int8) {
+	for i := range b {
+		b[i] = byte(src[i])
+	}
+}
+
+func encodeUint8s(b []byte, src []uint8) {
+	copy(b, src)
+}
+
+func encodeInt16s(b []byte, src []int16) {
+	for i := range src[:len(b)/2] {
+		binary.BigEndian.PutUint16(b[2*i:], uint16(src[i]))
+	}
+}
+
+func encodeUint16s(b []byte, src []uint16) {
+	for i := range src[:len(b)/2] {
+		binary.BigEndian.PutUint16(b[2*i:], src[i])
+	}
+}
+
+func encodeInt32s(b []byte, src []int32) {
+	for i := range src[:len(b)/4] {
+		binary.BigEndian.PutUint32(b[4*i:], uint32(src[i]))
+	}
+}
+
+func encodeUint32s(b []byte, src []uint32) {
+	for i := range src[:len(b)/4] {
+		binary.BigEndian.PutUint32(b[4*i:], src[i])
+	}
+}
+
+func encodeFloat32s(b []byte, src []float32) {
+	for i := range src[:len(b)/4] {
+		binary.BigEndian.PutUint32(b[4*i:], math.Float32bits(src[i]))
+	}
+}
+
+func encodeInt64s(b []byte, src []int64) {
+	for i := range src[:len(b)/8] {
+		binary.BigEndian.PutUint64(b[8*i:], uint64(src[i]))
+	}
+}
+
+func encodeUint64s(b []byte, src []uint64) {
+	for i := range src[:len(b)/8] {
+		binary.BigEndian.PutUint64(b[8*i:], src[i])
+	}
+}
+
+func encodeFloat64s(b []byte, src []float64) {
+	for i := range src[:len(b)/8] {
+		binary.BigEndian.PutUint64(b[8*i:], math.Float64bits(src[i]))
+	}
+}
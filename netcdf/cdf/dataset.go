@@ -0,0 +1,427 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package cdf
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// dataset is an open classic dataset. It implements driver.Dataset.
+type dataset struct {
+	mu sync.Mutex
+	header
+	r      io.ReaderAt
+	w      io.WriteSeeker // nil if the dataset is read-only
+	c      io.Closer      // closed by Close, if not nil
+	closed bool
+	define bool   // in define mode
+	hlen   uint64 // length of the header, including free space
+}
+
+var _ driver.Dataset = (*dataset)(nil)
+
+// Open opens the classic dataset read from r, whose size in bytes is size.
+// The size is only used for datasets written in streaming mode, whose
+// header doesn't give the number of records. The dataset is read-only.
+func Open(r io.ReaderAt, size int64) (netcdf.Dataset, error) {
+	ds, err := open(r, size)
+	if err != nil {
+		return netcdf.Dataset{}, err
+	}
+	return driver.NewDataset(ds, "").(netcdf.Dataset), nil
+}
+
+// OpenFile opens an existing classic dataset file at path for reading.
+func OpenFile(path string) (netcdf.Dataset, error) {
+	ds, err := openFile(path)
+	if err != nil {
+		return netcdf.Dataset{}, err
+	}
+	return driver.NewDataset(ds, path).(netcdf.Dataset), nil
+}
+
+func open(r io.ReaderAt, size int64) (*dataset, error) {
+	h, err := decodeHeader(io.NewSectionReader(r, 0, size), size)
+	if err != nil {
+		return nil, err
+	}
+	if h.numRecs == streaming {
+		h.numRecs = 0
+		for _, v := range h.vars {
+			if v.record && h.recSize > 0 && uint64(size) > v.begin {
+				h.numRecs = (uint64(size) - v.begin) / h.recSize
+				break
+			}
+		}
+	}
+	return &dataset{header: *h, r: r}, nil
+}
+
+func openFile(path string) (*dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	ds, err := open(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	ds.c = f
+	return ds, nil
+}
+
+// lock locks ds, and returns an error if it's closed.
+func (ds *dataset) lock() error {
+	ds.mu.Lock()
+	if ds.closed {
+		ds.mu.Unlock()
+		return netcdf.EBADID
+	}
+	return nil
+}
+
+// Close closes the dataset. For a dataset being written, it leaves define
+// mode if needed, updates the number of records in the header and extends
+// the output to the full size of the dataset.
+func (ds *dataset) Close() (err error) {
+	if err := ds.lock(); err != nil {
+		return err
+	}
+	defer ds.mu.Unlock()
+	if ds.w != nil {
+		err = ds.flush()
+	}
+	ds.closed = true
+	if ds.c != nil {
+		if e := ds.c.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (ds *dataset) NDims() (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	return len(ds.dims), nil
+}
+
+func (ds *dataset) NVars() (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	return len(ds.vars), nil
+}
+
+func (ds *dataset) UnlimitedDims() ([]int, error) {
+	if err := ds.lock(); err != nil {
+		return nil, err
+	}
+	defer ds.mu.Unlock()
+	var ids []int
+	for i, d := range ds.dims {
+		if d.len == 0 {
+			ids = append(ids, i)
+		}
+	}
+	return ids, nil
+}
+
+// DimIDs returns the IDs of all the dimensions, since classic datasets
+// have no groups.
+func (ds *dataset) DimIDs() ([]int, error) {
+	n, err := ds.NDims()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids, nil
+}
+
+func (ds *dataset) DimID(name string) (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	for i, d := range ds.dims {
+		if d.name == name {
+			return i, nil
+		}
+	}
+	return 0, netcdf.EBADDIM
+}
+
+// dim returns the dimension with ID id.
+func (ds *dataset) dim(id int) (*dim, error) {
+	if id < 0 || id >= len(ds.dims) {
+		return nil, netcdf.EBADDIM
+	}
+	return &ds.dims[id], nil
+}
+
+func (ds *dataset) DimName(dimid int) (string, error) {
+	if err := ds.lock(); err != nil {
+		return "", err
+	}
+	defer ds.mu.Unlock()
+	d, err := ds.dim(dimid)
+	if err != nil {
+		return "", err
+	}
+	return d.name, nil
+}
+
+// DimLen returns the length of a dimension. The length of the unlimited
+// dimension is the number of records.
+func (ds *dataset) DimLen(dimid int) (uint64, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	d, err := ds.dim(dimid)
+	if err != nil {
+		return 0, err
+	}
+	if d.len == 0 {
+		return ds.numRecs, nil
+	}
+	return d.len, nil
+}
+
+func (ds *dataset) VarID(name string) (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	for i, v := range ds.vars {
+		if v.name == name {
+			return i, nil
+		}
+	}
+	return 0, netcdf.ENOTVAR
+}
+
+// variable returns the variable with ID id.
+func (ds *dataset) variable(id int) (*variable, error) {
+	if id < 0 || id >= len(ds.vars) {
+		return nil, netcdf.ENOTVAR
+	}
+	return &ds.vars[id], nil
+}
+
+func (ds *dataset) VarName(varid int) (string, error) {
+	if err := ds.lock(); err != nil {
+		return "", err
+	}
+	defer ds.mu.Unlock()
+	v, err := ds.variable(varid)
+	if err != nil {
+		return "", err
+	}
+	return v.name, nil
+}
+
+func (ds *dataset) VarType(varid int) (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	v, err := ds.variable(varid)
+	if err != nil {
+		return 0, err
+	}
+	return int(v.typ), nil
+}
+
+func (ds *dataset) VarDims(varid int) ([]int, error) {
+	if err := ds.lock(); err != nil {
+		return nil, err
+	}
+	defer ds.mu.Unlock()
+	v, err := ds.variable(varid)
+	if err != nil {
+		return nil, err
+	}
+	return append([]int(nil), v.dims...), nil
+}
+
+// SetDeflate returns ENOTNC4, since the classic format doesn't support
+// compression.
+func (ds *dataset) SetDeflate(varid int, shuffle, deflate bool, level int) error {
+	if _, _, _, err := ds.Deflate(varid); err != nil {
+		return err
+	}
+	return netcdf.ENOTNC4
+}
+
+// Deflate reports that compression is off, as it always is in the classic
+// format.
+func (ds *dataset) Deflate(varid int) (shuffle, deflate bool, level int, err error) {
+	if err := ds.lock(); err != nil {
+		return false, false, 0, err
+	}
+	defer ds.mu.Unlock()
+	_, err = ds.variable(varid)
+	return false, false, 0, err
+}
+
+// SetChunking returns ENOTNC4, since the classic format doesn't support
+// chunking.
+func (ds *dataset) SetChunking(varid int, contiguous bool, sizes []uint64) error {
+	if _, _, err := ds.Chunking(varid); err != nil {
+		return err
+	}
+	return netcdf.ENOTNC4
+}
+
+// Chunking reports that the storage of a variable is contiguous, as it
+// always is in the classic format.
+func (ds *dataset) Chunking(varid int) (contiguous bool, sizes []uint64, err error) {
+	if err := ds.lock(); err != nil {
+		return false, nil, err
+	}
+	defer ds.mu.Unlock()
+	_, err = ds.variable(varid)
+	return err == nil, nil, err
+}
+
+// attrList returns the attributes of variable varid, which may be
+// driver.Global.
+func (ds *dataset) attrList(varid int) (*[]attr, error) {
+	if varid == driver.Global {
+		return &ds.attrs, nil
+	}
+	v, err := ds.variable(varid)
+	if err != nil {
+		return nil, err
+	}
+	return &v.attrs, nil
+}
+
+// attr returns the attribute named name of variable varid.
+func (ds *dataset) attr(varid int, name string) (*attr, error) {
+	attrs, err := ds.attrList(varid)
+	if err != nil {
+		return nil, err
+	}
+	i := findAttr(*attrs, name)
+	if i < 0 {
+		return nil, netcdf.ENOTATT
+	}
+	return &(*attrs)[i], nil
+}
+
+func (ds *dataset) NAttrs(varid int) (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	attrs, err := ds.attrList(varid)
+	if err != nil {
+		return 0, err
+	}
+	return len(*attrs), nil
+}
+
+func (ds *dataset) AttrName(varid, n int) (string, error) {
+	if err := ds.lock(); err != nil {
+		return "", err
+	}
+	defer ds.mu.Unlock()
+	attrs, err := ds.attrList(varid)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 || n >= len(*attrs) {
+		return "", netcdf.ENOTATT
+	}
+	return (*attrs)[n].name, nil
+}
+
+func (ds *dataset) AttrType(varid int, name string) (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	a, err := ds.attr(varid, name)
+	if err != nil {
+		return 0, err
+	}
+	return int(a.typ), nil
+}
+
+func (ds *dataset) AttrLen(varid int, name string) (uint64, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	a, err := ds.attr(varid, name)
+	if err != nil {
+		return 0, err
+	}
+	return a.n, nil
+}
+
+func (ds *dataset) GetAttr(varid int, name string, t int, val interface{}) error {
+	if err := ds.lock(); err != nil {
+		return err
+	}
+	defer ds.mu.Unlock()
+	a, err := ds.attr(varid, name)
+	if err != nil {
+		return err
+	}
+	if err := checkConvert(netcdf.Type(t), a.typ); err != nil {
+		return err
+	}
+	buf := buffer(val, a.typ, int(a.n))
+	decode(buf, 0, a.value)
+	convertInto(val, buf, int(a.n))
+	return nil
+}
+
+// DefGrp returns ENOTNC4, since classic datasets have no groups.
+func (ds *dataset) DefGrp(name string) (driver.Dataset, error) {
+	if err := ds.lock(); err != nil {
+		return nil, err
+	}
+	ds.mu.Unlock()
+	return nil, netcdf.ENOTNC4
+}
+
+// Grp returns ENOGRP, since classic datasets have no groups.
+func (ds *dataset) Grp(name string) (driver.Dataset, error) {
+	if err := ds.lock(); err != nil {
+		return nil, err
+	}
+	ds.mu.Unlock()
+	return nil, netcdf.ENOGRP
+}
+
+// GrpNames returns no names, since classic datasets have no groups.
+func (ds *dataset) GrpNames() ([]string, error) {
+	if err := ds.lock(); err != nil {
+		return nil, err
+	}
+	ds.mu.Unlock()
+	return nil, nil
+}
@@ -0,0 +1,396 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package cdf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+// Tags of the lists in the header.
+const (
+	tagAbsent    = 0x00
+	tagDimension = 0x0A
+	tagVariable  = 0x0B
+	tagAttribute = 0x0C
+)
+
+// streaming is the value of numrecs in the header of a dataset whose
+// number of records must be computed from the size of the file.
+const streaming = ^uint64(0)
+
+// header is the decoded header of a classic dataset.
+type header struct {
+	format  Format
+	numRecs uint64
+	dims    []dim
+	attrs   []attr
+	vars    []variable
+	recSize uint64 // distance in bytes between records
+}
+
+type dim struct {
+	name string
+	len  uint64 // 0 for the unlimited dimension
+}
+
+type attr struct {
+	name  string
+	typ   netcdf.Type
+	n     uint64 // number of values
+	value []byte // raw values, without padding
+}
+
+type variable struct {
+	name   string
+	dims   []int
+	attrs  []attr
+	typ    netcdf.Type
+	vsize  uint64 // size in bytes, or size of one record for record variables
+	begin  uint64 // offset of the data in the file
+	record bool
+}
+
+// findAttr returns the index of the attribute named name, or -1.
+func findAttr(attrs []attr, name string) int {
+	for i, a := range attrs {
+		if a.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// decoder reads the values of a header, remembering the first error.
+type decoder struct {
+	r      *bufio.Reader
+	left   int64 // bytes left to read
+	format Format
+	err    error
+}
+
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	// Lengths come from the file, so they're checked against its size
+	// before allocating.
+	if int64(n) > d.left {
+		d.err = fmt.Errorf("reading header: value of %d bytes past the end of the file", n)
+		return nil
+	}
+	d.left -= int64(n)
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		d.err = fmt.Errorf("reading header: %v", err)
+		return nil
+	}
+	return b
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.read(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (d *decoder) uint64() uint64 {
+	b := d.read(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// nonNeg reads a NON_NEG value, which is 8 bytes long in CDF-5 and 4 bytes
+// long otherwise. A 4 byte value of all ones is returned as streaming.
+func (d *decoder) nonNeg() uint64 {
+	if d.format == CDF5 {
+		return d.uint64()
+	}
+	n := d.uint32()
+	if n == ^uint32(0) {
+		return streaming
+	}
+	return uint64(n)
+}
+
+// offset reads the OFFSET of a variable, which is 4 bytes long in CDF-1.
+func (d *decoder) offset() uint64 {
+	if d.format == CDF1 {
+		return uint64(d.uint32())
+	}
+	return d.uint64()
+}
+
+// padded reads n bytes followed by the padding to a 4 byte boundary.
+func (d *decoder) padded(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > 1<<31 {
+		d.err = fmt.Errorf("reading header: value of %d bytes is too long", n)
+		return nil
+	}
+	b := d.read(int(pad4(n)))
+	if b == nil {
+		return nil
+	}
+	return b[:n]
+}
+
+func (d *decoder) name() string {
+	return string(d.padded(d.nonNeg()))
+}
+
+// list reads the tag and length of a list. An absent list has length 0.
+func (d *decoder) list(tag uint32) uint64 {
+	t := d.uint32()
+	n := d.nonNeg()
+	if d.err == nil && t != tag && (t != tagAbsent || n != 0) {
+		d.err = fmt.Errorf("reading header: invalid list tag %#x", t)
+	}
+	return n
+}
+
+func (d *decoder) attrs() []attr {
+	n := d.list(tagAttribute)
+	var attrs []attr
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		a := attr{name: d.name(), typ: netcdf.Type(d.uint32())}
+		a.n = d.nonNeg()
+		if d.err == nil && !validType(d.format, a.typ) {
+			d.err = netcdf.EBADTYPE
+		}
+		if d.err == nil && a.n > maxSize/size(a.typ) {
+			d.err = fmt.Errorf("reading header: attribute %q has too many values: %d", a.name, a.n)
+		}
+		a.value = d.padded(a.n * size(a.typ))
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// decodeHeader decodes the header of a classic dataset read from r, which
+// holds size bytes.
+func decodeHeader(r io.Reader, size int64) (*header, error) {
+	d := &decoder{r: bufio.NewReader(r), left: size}
+	magic := d.read(4)
+	if d.err != nil || string(magic[:3]) != "CDF" {
+		return nil, netcdf.ENOTNC
+	}
+	h := &header{format: Format(magic[3])}
+	switch h.format {
+	case CDF1, CDF2, CDF5:
+	default:
+		return nil, netcdf.ENOTNC
+	}
+	d.format = h.format
+	h.numRecs = d.nonNeg()
+
+	n := d.list(tagDimension)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		h.dims = append(h.dims, dim{name: d.name(), len: d.nonNeg()})
+	}
+	h.attrs = d.attrs()
+	n = d.list(tagVariable)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		v := variable{name: d.name()}
+		ndims := d.nonNeg()
+		for j := uint64(0); j < ndims && d.err == nil; j++ {
+			id := d.nonNeg()
+			if id >= uint64(len(h.dims)) {
+				d.err = netcdf.EBADDIM
+				break
+			}
+			v.dims = append(v.dims, int(id))
+		}
+		v.attrs = d.attrs()
+		v.typ = netcdf.Type(d.uint32())
+		if d.err == nil && !validType(d.format, v.typ) {
+			d.err = netcdf.EBADTYPE
+		}
+		v.vsize = d.nonNeg()
+		v.begin = d.offset()
+		h.vars = append(h.vars, v)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if err := h.layout(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// layout computes the sizes of the variables and of the records. The
+// vsize stored in the header isn't used, since it saturates for large
+// variables. It returns EVARSIZE if a variable is too large.
+func (h *header) layout() error {
+	h.recSize = 0
+	var nrec int
+	var last uint64 // unpadded size of the last record variable
+	for i := range h.vars {
+		v := &h.vars[i]
+		v.record = len(v.dims) > 0 && h.dims[v.dims[0]].len == 0
+		n := size(v.typ)
+		for j, id := range v.dims {
+			if j > 0 || !v.record {
+				l := h.dims[id].len
+				if l != 0 && n > maxSize/l {
+					return netcdf.EVARSIZE
+				}
+				n *= l
+			}
+		}
+		v.vsize = pad4(n)
+		if v.record {
+			if h.recSize > maxSize-v.vsize {
+				return netcdf.EVARSIZE
+			}
+			h.recSize += v.vsize
+			nrec++
+			last = n
+		}
+	}
+	if nrec == 1 {
+		// A single record variable has no padding between records.
+		h.recSize = last
+	}
+	return nil
+}
+
+// encoder writes the values of a header.
+type encoder struct {
+	b      []byte
+	format Format
+}
+
+func (e *encoder) uint32(n uint32) {
+	e.b = append(e.b, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func (e *encoder) uint64(n uint64) {
+	e.uint32(uint32(n >> 32))
+	e.uint32(uint32(n))
+}
+
+// nonNeg writes a NON_NEG value, saturating it if it doesn't fit in 4 bytes.
+func (e *encoder) nonNeg(n uint64) {
+	switch {
+	case e.format == CDF5:
+		e.uint64(n)
+	case n > uint64(^uint32(0)):
+		e.uint32(^uint32(0))
+	default:
+		e.uint32(uint32(n))
+	}
+}
+
+func (e *encoder) offset(n uint64) {
+	if e.format == CDF1 {
+		e.uint32(uint32(n))
+	} else {
+		e.uint64(n)
+	}
+}
+
+func (e *encoder) padded(b []byte) {
+	e.b = append(e.b, b...)
+	e.b = append(e.b, make([]byte, pad4(uint64(len(b)))-uint64(len(b)))...)
+}
+
+func (e *encoder) name(s string) {
+	e.nonNeg(uint64(len(s)))
+	e.padded([]byte(s))
+}
+
+// list writes the tag and length of a list, or ABSENT for an empty list.
+func (e *encoder) list(tag uint32, n int) {
+	if n == 0 {
+		tag = tagAbsent
+	}
+	e.uint32(tag)
+	e.nonNeg(uint64(n))
+}
+
+func (e *encoder) attrs(attrs []attr) {
+	e.list(tagAttribute, len(attrs))
+	for _, a := range attrs {
+		e.name(a.name)
+		e.uint32(uint32(a.typ))
+		e.nonNeg(a.n)
+		e.padded(a.value)
+	}
+}
+
+// encode returns the encoding of h.
+func (h *header) encode() []byte {
+	e := &encoder{format: h.format}
+	e.b = append(e.b, 'C', 'D', 'F', byte(h.format))
+	e.nonNeg(h.numRecs)
+	e.list(tagDimension, len(h.dims))
+	for _, d := range h.dims {
+		e.name(d.name)
+		e.nonNeg(d.len)
+	}
+	e.attrs(h.attrs)
+	e.list(tagVariable, len(h.vars))
+	for _, v := range h.vars {
+		e.name(v.name)
+		e.nonNeg(uint64(len(v.dims)))
+		for _, id := range v.dims {
+			e.nonNeg(uint64(id))
+		}
+		e.attrs(v.attrs)
+		e.uint32(uint32(v.typ))
+		e.nonNeg(v.vsize)
+		e.offset(v.begin)
+	}
+	return e.b
+}
+
+// numRecsPos is the position of numrecs in the header.
+const numRecsPos = 4
+
+// encodeNumRecs returns the encoding of the number of records.
+func (h *header) encodeNumRecs() []byte {
+	e := &encoder{format: h.format}
+	e.nonNeg(h.numRecs)
+	return e.b
+}
+
+// size returns the size of a dataset with header h.
+func (h *header) size(headerLen uint64) uint64 {
+	size := headerLen
+	for _, v := range h.vars {
+		var end uint64
+		if v.record {
+			end = v.begin + h.numRecs*h.recSize
+		} else {
+			end = v.begin + v.vsize
+		}
+		if end > size {
+			size = end
+		}
+	}
+	return size
+}
+
+// maxSize is the largest size in bytes of a value or a variable, so sizes
+// can be padded and converted to int64 without overflow.
+const maxSize = math.MaxInt64 - 3
+
+// pad4 returns n rounded up to a multiple of 4.
+func pad4(n uint64) uint64 {
+	return (n + 3) &^ 3
+}
@@ -0,0 +1,335 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+// +build cgo
+
+package cdf_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/cdf"
+)
+
+// cdf5 is NC_64BIT_DATA, the mode for creating CDF-5 files.
+const cdf5 = netcdf.FileMode(0x20)
+
+var goTypes = map[netcdf.Type]reflect.Type{
+	netcdf.BYTE:   reflect.TypeOf(int8(0)),
+	netcdf.CHAR:   reflect.TypeOf(byte(0)),
+	netcdf.SHORT:  reflect.TypeOf(int16(0)),
+	netcdf.INT:    reflect.TypeOf(int32(0)),
+	netcdf.FLOAT:  reflect.TypeOf(float32(0)),
+	netcdf.DOUBLE: reflect.TypeOf(float64(0)),
+	netcdf.UBYTE:  reflect.TypeOf(uint8(0)),
+	netcdf.USHORT: reflect.TypeOf(uint16(0)),
+	netcdf.UINT:   reflect.TypeOf(uint32(0)),
+	netcdf.INT64:  reflect.TypeOf(int64(0)),
+	netcdf.UINT64: reflect.TypeOf(uint64(0)),
+}
+
+// testData returns a slice of n values of type t.
+func testData(t netcdf.Type, n uint64) interface{} {
+	s := reflect.MakeSlice(reflect.SliceOf(goTypes[t]), int(n), int(n))
+	for i := 0; i < int(n); i++ {
+		s.Index(i).Set(reflect.ValueOf(i%100 + 1).Convert(goTypes[t]))
+	}
+	return s.Interface()
+}
+
+// createClassicFile creates a dataset with the C library, holding a fixed
+// and a record variable for each type supported by mode.
+func createClassicFile(filename string, mode netcdf.FileMode) error {
+	types := []netcdf.Type{netcdf.BYTE, netcdf.CHAR, netcdf.SHORT, netcdf.INT, netcdf.FLOAT, netcdf.DOUBLE}
+	if mode == cdf5 {
+		types = append(types, netcdf.UBYTE, netcdf.USHORT, netcdf.UINT, netcdf.INT64, netcdf.UINT64)
+	}
+	ds, err := netcdf.CreateFile(filename, netcdf.CLOBBER|mode)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+	time, err := ds.AddDim("time", 0)
+	if err != nil {
+		return err
+	}
+	x, err := ds.AddDim("x", 5)
+	if err != nil {
+		return err
+	}
+	y, err := ds.AddDim("y", 3)
+	if err != nil {
+		return err
+	}
+	if err := ds.Attr("title").WriteBytes([]byte("gopher test")); err != nil {
+		return err
+	}
+	if err := ds.Attr("version").WriteFloat64s([]float64{1.5, 2}); err != nil {
+		return err
+	}
+	var vars []netcdf.Var
+	for _, t := range types {
+		fixed, err := ds.AddVar("fixed_"+t.String(), t, []netcdf.Dim{x, y})
+		if err != nil {
+			return err
+		}
+		if err := fixed.Attr("units").WriteBytes([]byte("furlongs")); err != nil {
+			return err
+		}
+		rec, err := ds.AddVar("rec_"+t.String(), t, []netcdf.Dim{time, y})
+		if err != nil {
+			return err
+		}
+		vars = append(vars, fixed, rec)
+	}
+	if err := ds.EndDef(); err != nil {
+		return err
+	}
+	for i, v := range vars {
+		t, err := v.Type()
+		if err != nil {
+			return err
+		}
+		count := []uint64{5, 3}
+		if i%2 == 1 {
+			count[0] = 4 // records
+		}
+		data := testData(t, count[0]*count[1])
+		if err := writeSlice(v, data, []uint64{0, 0}, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareDatasets checks that the dataset read through the C library
+// and through package cdf have the same dimensions, attributes, variables
+// and data.
+func compareDatasets(t *testing.T, nds, cds netcdf.Dataset) {
+	r, err := netcdf.Diff(nds, cds, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v\n", err)
+	}
+	if !r.Equal() {
+		t.Errorf("datasets differ:\n%v", r)
+	}
+}
+
+// fileFormat returns the format given by the magic number of a file.
+func fileFormat(t *testing.T, filename string) cdf.Format {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil || len(b) < 4 {
+		t.Fatalf("reading magic number failed: %v\n", err)
+	}
+	return cdf.Format(b[3])
+}
+
+func TestCompareWithCLibrary(t *testing.T) {
+	for _, test := range []struct {
+		mode   netcdf.FileMode
+		format cdf.Format
+	}{
+		{0, cdf.CDF1},
+		{netcdf.OFFSET_64BIT, cdf.CDF2},
+		{cdf5, cdf.CDF5},
+	} {
+		f, err := ioutil.TempFile("", "cdf_test")
+		if err != nil {
+			t.Fatalf("creating temporary file failed: %v\n", err)
+		}
+		if err := createClassicFile(f.Name(), test.mode); err != nil {
+			t.Fatalf("%v: creating file failed: %v\n", test.format, err)
+		}
+		nds, err := netcdf.OpenFile(f.Name(), netcdf.NOWRITE)
+		if err != nil {
+			t.Fatalf("netcdf.OpenFile failed: %v\n", err)
+		}
+		cds, err := cdf.OpenFile(f.Name())
+		if err != nil {
+			t.Fatalf("%v: cdf.OpenFile failed: %v\n", test.format, err)
+		}
+		if format := fileFormat(t, f.Name()); format != test.format {
+			t.Errorf("format is %v; expected %v\n", format, test.format)
+		}
+		compareDatasets(t, nds, cds)
+		nds.Close()
+		cds.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}
+}
+
+// writeSlice writes data, a slice of values of the type of v, to the
+// hyperslab of v given by start and count. The last value is written first,
+// since slices can't extend the record dimension.
+func writeSlice(v netcdf.Var, data interface{}, start, count []uint64) error {
+	last := make([]uint64, len(start))
+	for i := range start {
+		last[i] = start[i] + count[i] - 1
+	}
+	n := reflect.ValueOf(data).Len() - 1
+	var err error
+	switch d := data.(type) {
+	case []int8:
+		err = v.WriteInt8At(last, d[n])
+	case []byte:
+		var t netcdf.Type
+		if t, err = v.Type(); err != nil {
+			break
+		}
+		if t == netcdf.UBYTE {
+			err = v.WriteUint8At(last, d[n])
+		} else {
+			err = v.WriteBytesAt(last, d[n])
+		}
+	case []int16:
+		err = v.WriteInt16At(last, d[n])
+	case []int32:
+		err = v.WriteInt32At(last, d[n])
+	case []float32:
+		err = v.WriteFloat32At(last, d[n])
+	case []float64:
+		err = v.WriteFloat64At(last, d[n])
+	case []uint16:
+		err = v.WriteUint16At(last, d[n])
+	case []uint32:
+		err = v.WriteUint32At(last, d[n])
+	case []int64:
+		err = v.WriteInt64At(last, d[n])
+	case []uint64:
+		err = v.WriteUint64At(last, d[n])
+	default:
+		err = fmt.Errorf("unexpected data type %T", data)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = v.WriteSliceCtx(context.Background(), data, start, count, nil)
+	return err
+}
+
+// createCDFFile creates with package cdf the same dataset as
+// createClassicFile, in format f.
+func createCDFFile(filename string, f cdf.Format) error {
+	types := []netcdf.Type{netcdf.BYTE, netcdf.CHAR, netcdf.SHORT, netcdf.INT, netcdf.FLOAT, netcdf.DOUBLE}
+	if f == cdf.CDF5 {
+		types = append(types, netcdf.UBYTE, netcdf.USHORT, netcdf.UINT, netcdf.INT64, netcdf.UINT64)
+	}
+	ds, err := cdf.CreateFile(filename, f)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+	time, err := ds.AddDim("time", 0)
+	if err != nil {
+		return err
+	}
+	x, err := ds.AddDim("x", 5)
+	if err != nil {
+		return err
+	}
+	y, err := ds.AddDim("y", 3)
+	if err != nil {
+		return err
+	}
+	if err := ds.Attr("title").WriteBytes([]byte("gopher test")); err != nil {
+		return err
+	}
+	if err := ds.Attr("version").WriteFloat64s([]float64{1.5, 2}); err != nil {
+		return err
+	}
+	var vars []netcdf.Var
+	for _, t := range types {
+		fixed, err := ds.AddVar("fixed_"+t.String(), t, []netcdf.Dim{x, y})
+		if err != nil {
+			return err
+		}
+		if err := fixed.Attr("units").WriteBytes([]byte("furlongs")); err != nil {
+			return err
+		}
+		rec, err := ds.AddVar("rec_"+t.String(), t, []netcdf.Dim{time, y})
+		if err != nil {
+			return err
+		}
+		vars = append(vars, fixed, rec)
+	}
+	if err := ds.EndDef(); err != nil {
+		return err
+	}
+	for i, v := range vars {
+		t, err := v.Type()
+		if err != nil {
+			return err
+		}
+		count := []uint64{5, 3}
+		if i%2 == 1 {
+			count[0] = 4 // records
+		}
+		data := testData(t, count[0]*count[1])
+		if err := writeSlice(v, data, []uint64{0, 0}, count); err != nil {
+			return err
+		}
+	}
+	return ds.Close()
+}
+
+func TestWriteWithCLibrary(t *testing.T) {
+	for _, format := range []cdf.Format{cdf.CDF1, cdf.CDF2, cdf.CDF5} {
+		f, err := ioutil.TempFile("", "cdf_test")
+		if err != nil {
+			t.Fatalf("creating temporary file failed: %v\n", err)
+		}
+		f.Close()
+		if err := createCDFFile(f.Name(), format); err != nil {
+			t.Fatalf("%v: creating file failed: %v\n", format, err)
+		}
+		nds, err := netcdf.OpenFile(f.Name(), netcdf.NOWRITE)
+		if err != nil {
+			t.Fatalf("%v: netcdf.OpenFile failed: %v\n", format, err)
+		}
+		cds, err := cdf.OpenFile(f.Name())
+		if err != nil {
+			t.Fatalf("%v: cdf.OpenFile failed: %v\n", format, err)
+		}
+		compareDatasets(t, nds, cds)
+
+		// The C library must also read back what was written.
+		nv, err := nds.NVars()
+		if err != nil {
+			t.Fatalf("NVars failed: %v\n", err)
+		}
+		for id := 0; id < nv; id++ {
+			v := nds.VarN(id)
+			typ, err := v.Type()
+			if err != nil {
+				t.Fatalf("Type failed: %v\n", err)
+			}
+			n, err := v.Len()
+			if err != nil {
+				t.Fatalf("Len failed: %v\n", err)
+			}
+			got := reflect.MakeSlice(reflect.SliceOf(goTypes[typ]), int(n), int(n)).Interface()
+			if _, err := v.ReadCtx(context.Background(), got, nil); err != nil {
+				t.Fatalf("ReadCtx failed: %v\n", err)
+			}
+			if want := testData(typ, n); !reflect.DeepEqual(got, want) {
+				name, _ := v.Name()
+				t.Errorf("%v: %s is %v; expected %v\n", format, name, got, want)
+			}
+		}
+		nds.Close()
+		cds.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+// +build !cgo
+
+package cdf_test
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	_ "github.com/fhs/go-netcdf/netcdf/cdf"
+)
+
+// TestNetCDFFile checks that netcdf.CreateFile and netcdf.OpenFile use
+// package cdf when the C library isn't available.
+func TestNetCDFFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "cdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if _, err := netcdf.CreateFile(f.Name(), netcdf.NOCLOBBER); err != netcdf.EEXIST {
+		t.Errorf("CreateFile of existing file with NOCLOBBER returned %v; expected %v\n", err, netcdf.EEXIST)
+	}
+	if _, err := netcdf.CreateFile(f.Name(), netcdf.CLOBBER|netcdf.NETCDF4); err == nil {
+		t.Errorf("CreateFile of netCDF-4 dataset succeeded\n")
+	}
+	ds, err := netcdf.CreateFile(f.Name(), netcdf.CLOBBER|netcdf.OFFSET_64BIT)
+	if err != nil {
+		t.Fatalf("CreateFile failed: %v\n", err)
+	}
+	x, err := ds.AddDim("x", 3)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	v, err := ds.AddVar("gopher", netcdf.DOUBLE, []netcdf.Dim{x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := ds.EndDef(); err != nil {
+		t.Fatalf("EndDef failed: %v\n", err)
+	}
+	if err := v.WriteFloat64s([]float64{1, 2, 3}); err != nil {
+		t.Fatalf("WriteFloat64s failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil || len(b) < 4 || string(b[:4]) != "CDF\x02" {
+		t.Errorf("file doesn't start with the magic number of CDF-2: %q, %v\n", b, err)
+	}
+	if _, err := netcdf.OpenFile(f.Name(), netcdf.WRITE); err != netcdf.EPERM {
+		t.Errorf("OpenFile for writing returned %v; expected %v\n", err, netcdf.EPERM)
+	}
+	ds, err = netcdf.OpenFile(f.Name(), netcdf.NOWRITE)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v\n", err)
+	}
+	defer ds.Close()
+	got, err := netcdf.GetFloat64s(ds.VarN(0))
+	if want := []float64{1, 2, 3}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("gopher is %v, %v; expected %v\n", got, err, want)
+	}
+}
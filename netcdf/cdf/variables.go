@@ -0,0 +1,231 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package cdf
+
+import (
+	"io"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+func (ds *dataset) GetVar(varid, t int, data interface{}) error {
+	return ds.transfer(varid, t, nil, nil, nil, data, false)
+}
+
+func (ds *dataset) PutVar(varid, t int, data interface{}) error {
+	return ds.transfer(varid, t, nil, nil, nil, data, true)
+}
+
+func (ds *dataset) GetVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	if count == nil {
+		count = ones(len(start))
+	}
+	return ds.transfer(varid, t, start, count, stride, data, false)
+}
+
+func (ds *dataset) PutVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	if count == nil {
+		count = ones(len(start))
+	}
+	return ds.transfer(varid, t, start, count, stride, data, true)
+}
+
+// ones returns a slice of n ones, the count of a single value.
+func ones(n int) []uint64 {
+	c := make([]uint64, n)
+	for i := range c {
+		c[i] = 1
+	}
+	return c
+}
+
+// shape returns the current shape of variable v.
+func (ds *dataset) shape(v *variable) []uint64 {
+	shape := make([]uint64, len(v.dims))
+	for i, id := range v.dims {
+		shape[i] = ds.dims[id].len
+		if shape[i] == 0 {
+			shape[i] = ds.numRecs
+		}
+	}
+	return shape
+}
+
+// transfer reads (or writes, if write is true) the values of variable varid
+// selected by start, count and stride from data, which holds values of type
+// t. A nil count selects all the values, and a nil stride means a stride of
+// 1 along each dimension. Writes may extend the record dimension.
+func (ds *dataset) transfer(varid, t int, start, count []uint64, stride []int64, data interface{}, write bool) error {
+	if err := ds.lock(); err != nil {
+		return err
+	}
+	defer ds.mu.Unlock()
+	v, err := ds.variable(varid)
+	if err != nil {
+		return err
+	}
+	if err := checkConvert(netcdf.Type(t), v.typ); err != nil {
+		return err
+	}
+	shape := ds.shape(v)
+	if count == nil {
+		start, count = make([]uint64, len(shape)), shape
+	}
+	if len(start) != len(shape) || len(count) != len(shape) || stride != nil && len(stride) != len(shape) {
+		return netcdf.EINVALCOORDS
+	}
+	for i, n := range shape {
+		if i == 0 && write && v.record {
+			n = ^uint64(0)
+		}
+		s := int64(1)
+		if stride != nil {
+			s = stride[i]
+		}
+		if s < 1 {
+			return netcdf.ESTRIDE
+		}
+		if start[i] > n || start[i] == n && count[i] > 0 {
+			return netcdf.EINVALCOORDS
+		}
+		if count[i] > 0 && start[i]+(count[i]-1)*uint64(s) >= n {
+			return netcdf.EEDGE
+		}
+	}
+	if ds.define {
+		return netcdf.EINDEFINE
+	}
+
+	n := int(product(count))
+	buf := buffer(data, v.typ, n)
+	if !write {
+		if ds.r == nil {
+			return errWriteOnly
+		}
+		err := ds.runs(v, start, count, stride, func(off int, pos uint64, b []byte) error {
+			if err := readAt(ds.r, b, pos); err != nil {
+				return err
+			}
+			decode(buf, off, b)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		convertInto(data, buf, n)
+		return nil
+	}
+
+	if ds.w == nil {
+		return netcdf.EPERM
+	}
+	convertInto(buf, data, n)
+	err = ds.runs(v, start, count, stride, func(off int, pos uint64, b []byte) error {
+		encode(b, buf, off)
+		return writeAt(ds.w, b, pos)
+	})
+	if err != nil || !v.record || count[0] == 0 {
+		return err
+	}
+	last := start[0] + count[0]
+	if stride != nil {
+		last = start[0] + (count[0]-1)*uint64(stride[0]) + 1
+	}
+	if last > ds.numRecs {
+		ds.numRecs = last
+	}
+	return nil
+}
+
+// runs calls f for each contiguous run of values of the hyperslab of v
+// given by start, count and stride, in row-major order. Off is the position
+// of the first value of the run within the hyperslab, pos is its position
+// in the file and b is a buffer the size of the run. The arguments must
+// have been checked by transfer.
+func (ds *dataset) runs(v *variable, start, count []uint64, stride []int64, f func(off int, pos uint64, b []byte) error) error {
+	tsize := size(v.typ)
+	n := len(v.dims)
+	if n == 0 {
+		return f(0, v.begin, make([]byte, tsize))
+	}
+	if product(count) == 0 {
+		return nil
+	}
+	if stride == nil {
+		stride = make([]int64, n)
+		for i := range stride {
+			stride[i] = 1
+		}
+	}
+	shape := make([]uint64, n)
+	for i, id := range v.dims {
+		shape[i] = ds.dims[id].len
+	}
+
+	// Distance in the file between consecutive values along each dimension.
+	dist := make([]uint64, n)
+	d := tsize
+	for i := n - 1; i >= 0; i-- {
+		dist[i] = d
+		d *= shape[i]
+	}
+	if v.record {
+		dist[0] = ds.recSize
+	}
+
+	// The dimensions from inner onwards are transferred as one run. They're
+	// merged while the inner dimension is transferred entirely and the next
+	// one out isn't strided or the record dimension.
+	inner, run := n, uint64(1)
+	if stride[n-1] == 1 {
+		inner, run = n-1, count[n-1]
+		for inner > 0 && start[inner] == 0 && count[inner] == shape[inner] &&
+			stride[inner-1] == 1 && !(inner == 1 && v.record) {
+			inner--
+			run *= count[inner]
+		}
+	}
+
+	buf := make([]byte, run*tsize)
+	idx := make([]uint64, inner)
+	for off := 0; ; off += int(run) {
+		pos := v.begin
+		for i := range shape {
+			p := start[i]
+			if i < inner {
+				p += idx[i] * uint64(stride[i])
+			}
+			pos += p * dist[i]
+		}
+		if err := f(off, pos, buf); err != nil {
+			return err
+		}
+
+		// Advance to the next run in row-major order.
+		i := inner - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < count[i] {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return nil
+		}
+	}
+}
+
+// readAt reads len(b) bytes from r at offset off.
+func readAt(r io.ReaderAt, b []byte, off uint64) error {
+	n, err := r.ReadAt(b, int64(off))
+	if n == len(b) {
+		return nil
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package cdf
+
+import (
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// Create creates a new classic dataset in format f, written to w. The
+// dataset is in define mode, so dimensions, variables and attributes can
+// be added to it. EndDef must be called before writing the data of the
+// variables, and Redef isn't supported.
+//
+// The header is written by EndDef and updated by Close. Values that are
+// never written are left as they are in w, which is zeros for files, as
+// with the NOFILL mode of the C library. If w is also an io.ReaderAt, the
+// data can be read back while the dataset is open.
+func Create(w io.WriteSeeker, f Format) (netcdf.Dataset, error) {
+	ds, err := create(w, f)
+	if err != nil {
+		return netcdf.Dataset{}, err
+	}
+	return driver.NewDataset(ds, "").(netcdf.Dataset), nil
+}
+
+// CreateFile creates a new classic dataset file at path, in format f.
+// An existing file is truncated. See Create.
+func CreateFile(path string, f Format) (netcdf.Dataset, error) {
+	ds, err := createFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, f)
+	if err != nil {
+		return netcdf.Dataset{}, err
+	}
+	return driver.NewDataset(ds, path).(netcdf.Dataset), nil
+}
+
+func create(w io.WriteSeeker, f Format) (*dataset, error) {
+	switch f {
+	case CDF1, CDF2, CDF5:
+	default:
+		return nil, netcdf.EINVAL
+	}
+	ds := &dataset{header: header{format: f}, w: w, define: true}
+	if r, ok := w.(io.ReaderAt); ok {
+		ds.r = r
+	}
+	return ds, nil
+}
+
+// createFile creates a dataset file at path, which is opened with the
+// given flags of os.OpenFile.
+func createFile(path string, flag int, f Format) (*dataset, error) {
+	if _, err := create(nil, f); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, flag, 0666)
+	if err != nil {
+		return nil, err
+	}
+	ds, _ := create(file, f)
+	ds.c = file
+	return ds, nil
+}
+
+// defining returns an error if ds isn't in define mode.
+func (ds *dataset) defining() error {
+	if ds.w == nil {
+		return netcdf.EPERM
+	}
+	if !ds.define {
+		return netcdf.ENOTINDEFINE
+	}
+	return nil
+}
+
+// checkName returns an error if name can't be used for a new dimension,
+// variable or attribute, given the names already in use.
+func checkName(name string, used []string) error {
+	if name == "" || name[0] == '/' {
+		return netcdf.EBADNAME
+	}
+	for _, u := range used {
+		if u == name {
+			return netcdf.ENAMEINUSE
+		}
+	}
+	return nil
+}
+
+// DefDim adds a new dimension named name of length length. A length of 0
+// makes it the unlimited dimension, of which there can only be one.
+func (ds *dataset) DefDim(name string, length uint64) (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	if err := ds.defining(); err != nil {
+		return 0, err
+	}
+	var used []string
+	for _, d := range ds.dims {
+		if length == 0 && d.len == 0 {
+			return 0, netcdf.EUNLIMIT
+		}
+		used = append(used, d.name)
+	}
+	if err := checkName(name, used); err != nil {
+		return 0, err
+	}
+	if ds.format != CDF5 && length > uint64(^uint32(0)>>1) {
+		return 0, netcdf.EDIMSIZE
+	}
+	ds.dims = append(ds.dims, dim{name: name, len: length})
+	return len(ds.dims) - 1, nil
+}
+
+// validType reports whether values of type t can be stored in format f.
+func validType(f Format, t netcdf.Type) bool {
+	if f == CDF5 {
+		return t >= netcdf.BYTE && t <= netcdf.UINT64
+	}
+	return t >= netcdf.BYTE && t <= netcdf.DOUBLE
+}
+
+// DefVar adds a new variable named name of type t and dimensions dimids.
+// The unlimited dimension may only be the first one.
+func (ds *dataset) DefVar(name string, t int, dimids []int) (int, error) {
+	if err := ds.lock(); err != nil {
+		return 0, err
+	}
+	defer ds.mu.Unlock()
+	if err := ds.defining(); err != nil {
+		return 0, err
+	}
+	used := make([]string, len(ds.vars))
+	for i, v := range ds.vars {
+		used[i] = v.name
+	}
+	if err := checkName(name, used); err != nil {
+		return 0, err
+	}
+	if !validType(ds.format, netcdf.Type(t)) {
+		return 0, netcdf.EBADTYPE
+	}
+	v := variable{name: name, typ: netcdf.Type(t), dims: make([]int, len(dimids))}
+	for i, id := range dimids {
+		d, err := ds.dim(id)
+		if err != nil {
+			return 0, err
+		}
+		if i > 0 && d.len == 0 {
+			return 0, netcdf.EUNLIMPOS
+		}
+		v.dims[i] = id
+	}
+	ds.vars = append(ds.vars, v)
+	if err := ds.layout(); err != nil {
+		ds.vars = ds.vars[:len(ds.vars)-1]
+		ds.layout()
+		return 0, err
+	}
+	return len(ds.vars) - 1, nil
+}
+
+// PutAttr creates or replaces an attribute. The dataset must be in define
+// mode.
+func (ds *dataset) PutAttr(varid int, name string, t int, val interface{}) error {
+	if err := ds.lock(); err != nil {
+		return err
+	}
+	defer ds.mu.Unlock()
+	if err := ds.defining(); err != nil {
+		return err
+	}
+	attrs, err := ds.attrList(varid)
+	if err != nil {
+		return err
+	}
+	typ := netcdf.Type(t)
+	gt, ok := goTypes[typ]
+	src := reflect.ValueOf(val)
+	if !ok || !validType(ds.format, typ) || src.Type().Elem() != gt {
+		return netcdf.EBADTYPE
+	}
+	n := src.Len()
+	b := make([]byte, uint64(n)*size(typ))
+	encode(b, val, 0)
+	a := attr{name: name, typ: typ, n: uint64(n), value: b}
+	if i := findAttr(*attrs, name); i >= 0 {
+		(*attrs)[i] = a
+		return nil
+	}
+	if err := checkName(name, nil); err != nil {
+		return err
+	}
+	*attrs = append(*attrs, a)
+	return nil
+}
+
+// EndDef leaves define mode and enters data mode, so variable data
+// can be written, and writes the header. It's the same as
+// EndDefWithOptions(0, 4, 0, 4).
+func (ds *dataset) EndDef() error {
+	return ds.EndDefWithOptions(0, 4, 0, 4)
+}
+
+// EndDefWithOptions is like EndDef, but controls the layout of the dataset
+// as nc__enddef of the C library does. HMinFree is the free space left
+// after the header, so attributes can be added later without moving the
+// data. VAlign is the alignment of the start of the data of the fixed-size
+// variables, which are followed by vMinFree bytes of free space. RAlign is
+// the alignment of the start of the record variables.
+func (ds *dataset) EndDefWithOptions(hMinFree, vAlign, vMinFree, rAlign uint64) error {
+	if err := ds.lock(); err != nil {
+		return err
+	}
+	defer ds.mu.Unlock()
+	return ds.endDef(hMinFree, vAlign, vMinFree, rAlign)
+}
+
+func (ds *dataset) endDef(hMinFree, vAlign, vMinFree, rAlign uint64) error {
+	if err := ds.defining(); err != nil {
+		return err
+	}
+	h := &ds.header
+	if err := h.layout(); err != nil {
+		return err
+	}
+	for i := range h.vars {
+		h.vars[i].begin = 0
+	}
+	hlen := uint64(len(h.encode()))
+
+	off := align(hlen+hMinFree, vAlign)
+	ds.hlen = off
+	for i := range h.vars {
+		if !h.vars[i].record {
+			h.vars[i].begin = off
+			off += h.vars[i].vsize
+		}
+	}
+	off = align(off+vMinFree, rAlign)
+	for i := range h.vars {
+		if h.vars[i].record {
+			h.vars[i].begin = off
+			off += h.vars[i].vsize
+		}
+	}
+	if h.format == CDF1 {
+		for _, v := range h.vars {
+			if v.begin > uint64(^uint32(0)>>1) {
+				return netcdf.EVARSIZE
+			}
+		}
+	}
+
+	b := h.encode()
+	b = append(b, make([]byte, ds.hlen-uint64(len(b)))...)
+	if err := writeAt(ds.w, b, 0); err != nil {
+		return err
+	}
+	ds.define = false
+	return nil
+}
+
+// Redef returns an error, since the data written so far would have to be
+// moved to make room for new definitions.
+func (ds *dataset) Redef() error {
+	if err := ds.lock(); err != nil {
+		return err
+	}
+	defer ds.mu.Unlock()
+	if ds.w == nil {
+		return netcdf.EPERM
+	}
+	if ds.define {
+		return netcdf.EINDEFINE
+	}
+	return errRedef
+}
+
+// align returns n rounded up to a multiple of a.
+func align(n, a uint64) uint64 {
+	if a <= 1 {
+		return n
+	}
+	return (n + a - 1) / a * a
+}
+
+// writeAt writes b to w at offset off.
+func writeAt(w io.WriteSeeker, b []byte, off uint64) error {
+	if _, err := w.Seek(int64(off), io.SeekStart); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// flush leaves define mode if needed, writes the number of records and
+// extends the output to the size of the dataset.
+func (ds *dataset) flush() error {
+	if ds.define {
+		if err := ds.endDef(0, 4, 0, 4); err != nil {
+			return err
+		}
+	}
+	h := &ds.header
+	if err := writeAt(ds.w, h.encodeNumRecs(), numRecsPos); err != nil {
+		return err
+	}
+	end, err := ds.w.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if size := h.size(ds.hlen); uint64(end) < size {
+		_, err = ds.w.Write(make([]byte, size-uint64(end)))
+	}
+	return err
+}
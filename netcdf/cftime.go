@@ -0,0 +1,152 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// TimeUnits is the encoding of the values of a CF time variable, given by
+// units such as "days since 2000-01-01 00:00:00". Values are counts of
+// Unit since Epoch.
+//
+// Only the standard, gregorian and proleptic_gregorian calendars are
+// supported, which are treated as the proleptic Gregorian calendar of
+// package time. Months and years aren't supported as units, as their
+// length varies.
+type TimeUnits struct {
+	Unit  time.Duration
+	Epoch time.Time
+}
+
+var timeUnits = map[string]time.Duration{
+	"days":         24 * time.Hour,
+	"day":          24 * time.Hour,
+	"d":            24 * time.Hour,
+	"hours":        time.Hour,
+	"hour":         time.Hour,
+	"hr":           time.Hour,
+	"h":            time.Hour,
+	"minutes":      time.Minute,
+	"minute":       time.Minute,
+	"min":          time.Minute,
+	"seconds":      time.Second,
+	"second":       time.Second,
+	"sec":          time.Second,
+	"s":            time.Second,
+	"milliseconds": time.Millisecond,
+	"millisecond":  time.Millisecond,
+	"ms":           time.Millisecond,
+	"microseconds": time.Microsecond,
+	"microsecond":  time.Microsecond,
+	"us":           time.Microsecond,
+}
+
+// Layouts of the epoch of time units. Parsing accepts one or two digits
+// for all fields but the year, and fractional seconds.
+var epochLayouts = []string{
+	"2006-1-2 15:4:5",
+	"2006-1-2T15:4:5",
+	"2006-1-2 15:4",
+	"2006-1-2T15:4",
+	"2006-1-2",
+}
+
+// ParseTimeUnits parses the units and the calendar attributes of a CF time
+// variable. An empty calendar means the standard one.
+func ParseTimeUnits(units, calendar string) (TimeUnits, error) {
+	switch strings.ToLower(calendar) {
+	case "", "standard", "gregorian", "proleptic_gregorian":
+	default:
+		return TimeUnits{}, fmt.Errorf("unsupported calendar %q", calendar)
+	}
+	f := strings.Fields(units)
+	if len(f) < 3 || strings.ToLower(f[1]) != "since" {
+		return TimeUnits{}, fmt.Errorf("invalid time units %q", units)
+	}
+	unit, ok := timeUnits[strings.ToLower(f[0])]
+	if !ok {
+		return TimeUnits{}, fmt.Errorf("unsupported time unit %q", f[0])
+	}
+
+	// The epoch is a date, an optional time, and an optional time zone,
+	// which is UTC or an offset.
+	ref := f[2:]
+	loc := time.UTC
+	if n := len(ref); n > 1 {
+		switch z := ref[n-1]; {
+		case strings.EqualFold(z, "UTC"), z == "Z":
+			ref = ref[:n-1]
+		case z[0] == '+' || z[0] == '-':
+			off, err := parseZone(z)
+			if err != nil {
+				return TimeUnits{}, fmt.Errorf("invalid time units %q: %v", units, err)
+			}
+			loc = time.FixedZone(z, off)
+			ref = ref[:n-1]
+		}
+	}
+	s := strings.TrimSuffix(strings.Join(ref, " "), "Z")
+	for _, layout := range epochLayouts {
+		if epoch, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return TimeUnits{Unit: unit, Epoch: epoch.UTC()}, nil
+		}
+	}
+	return TimeUnits{}, fmt.Errorf("invalid time units %q: can't parse %q as a date", units, s)
+}
+
+// parseZone returns the offset in seconds given by a time zone like +1,
+// -05:30 or +0100.
+func parseZone(z string) (int, error) {
+	sign := 1
+	if z[0] == '-' {
+		sign = -1
+	}
+	var h, m int
+	var err error
+	if strings.Contains(z, ":") {
+		_, err = fmt.Sscanf(z[1:], "%d:%d", &h, &m)
+	} else if len(z) > 3 {
+		_, err = fmt.Sscanf(z[1:], "%2d%2d", &h, &m)
+	} else {
+		_, err = fmt.Sscanf(z[1:], "%d", &h)
+	}
+	if err != nil || h > 23 || m > 59 {
+		return 0, fmt.Errorf("invalid time zone %q", z)
+	}
+	return sign * (h*3600 + m*60), nil
+}
+
+// TimeUnits returns the encoding of the values of v, given by its units and
+// calendar attributes.
+func (v Var) TimeUnits() (TimeUnits, error) {
+	units, err := GetBytes(v.Attr("units"))
+	if err != nil {
+		return TimeUnits{}, err
+	}
+	calendar, err := GetBytes(v.Attr("calendar"))
+	if err != nil && err != ENOTATT {
+		return TimeUnits{}, err
+	}
+	return ParseTimeUnits(string(units), string(calendar))
+}
+
+// Time returns the time encoded by value x.
+func (u TimeUnits) Time(x float64) time.Time {
+	secs := x * u.Unit.Seconds()
+	whole := math.Floor(secs)
+	ns := math.Round((secs - whole) * 1e9)
+	return time.Unix(u.Epoch.Unix()+int64(whole), int64(u.Epoch.Nanosecond())+int64(ns)).UTC()
+}
+
+// Value returns the value encoding time t. It doesn't use Time.Sub, so it
+// works for times more than 292 years away from the epoch.
+func (u TimeUnits) Value(t time.Time) float64 {
+	secs := float64(t.Unix()-u.Epoch.Unix()) + float64(t.Nanosecond()-u.Epoch.Nanosecond())/1e9
+	return secs / u.Unit.Seconds()
+}
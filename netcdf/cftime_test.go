@@ -0,0 +1,64 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeUnits(t *testing.T) {
+	for _, tc := range []struct {
+		units, calendar string
+		want            TimeUnits
+	}{
+		{"days since 2000-01-01", "", TimeUnits{24 * time.Hour, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{"hours since 1900-1-1 0:0:0", "standard", TimeUnits{time.Hour, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{"seconds since 1970-01-01T00:00:00Z", "gregorian", TimeUnits{time.Second, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{"minutes since 2020-06-15 12:30:00.5 UTC", "", TimeUnits{time.Minute, time.Date(2020, 6, 15, 12, 30, 0, 5e8, time.UTC)}},
+		{"Days Since 2020-06-15 12:30 -06:00", "", TimeUnits{24 * time.Hour, time.Date(2020, 6, 15, 18, 30, 0, 0, time.UTC)}},
+		{"ms since 1850-01-01 00:00:00 +0100", "proleptic_gregorian", TimeUnits{time.Millisecond, time.Date(1849, 12, 31, 23, 0, 0, 0, time.UTC)}},
+	} {
+		u, err := ParseTimeUnits(tc.units, tc.calendar)
+		if err != nil {
+			t.Errorf("ParseTimeUnits(%q, %q) failed: %v\n", tc.units, tc.calendar, err)
+		} else if u.Unit != tc.want.Unit || !u.Epoch.Equal(tc.want.Epoch) {
+			t.Errorf("ParseTimeUnits(%q, %q) is %v; expected %v\n", tc.units, tc.calendar, u, tc.want)
+		}
+	}
+	for _, tc := range []struct{ units, calendar string }{
+		{"days since 2000-01-01", "noleap"},
+		{"months since 2000-01-01", ""},
+		{"days after 2000-01-01", ""},
+		{"days since", ""},
+		{"days since yesterday", ""},
+		{"days since 2000-01-01 00:00 +25", ""},
+	} {
+		if _, err := ParseTimeUnits(tc.units, tc.calendar); err == nil {
+			t.Errorf("ParseTimeUnits(%q, %q) succeeded\n", tc.units, tc.calendar)
+		}
+	}
+}
+
+func TestTimeUnitsValue(t *testing.T) {
+	u := TimeUnits{24 * time.Hour, time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC)}
+	for _, tc := range []struct {
+		x float64
+		t time.Time
+	}{
+		{0, u.Epoch},
+		{0.25, time.Date(1850, 1, 1, 6, 0, 0, 0, time.UTC)},
+		{-1, time.Date(1849, 12, 31, 0, 0, 0, 0, time.UTC)},
+		// More than 292 years, the range of time.Duration.
+		{127835, time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		if got := u.Time(tc.x); !got.Equal(tc.t) {
+			t.Errorf("Time(%v) is %v; expected %v\n", tc.x, got, tc.t)
+		}
+		if got := u.Value(tc.t); got != tc.x {
+			t.Errorf("Value(%v) is %v; expected %v\n", tc.t, got, tc.x)
+		}
+	}
+}
@@ -0,0 +1,684 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+// +build cgo
+
+package netcdf
+
+// #cgo pkg-config: netcdf
+// #include <stdlib.h>
+// #include <netcdf.h>
+// #include <netcdf_meta.h>
+//
+// // Macros of netcdf_meta.h missing from older versions of the library
+// // are -1.
+// #ifndef NC_HAS_NC4
+// #define NC_HAS_NC4 -1
+// #endif
+// #ifndef NC_HAS_CDF5
+// #define NC_HAS_CDF5 -1
+// #endif
+// #ifndef NC_HAS_DAP
+// #define NC_HAS_DAP -1
+// #endif
+// #ifndef NC_HAS_DAP2
+// #define NC_HAS_DAP2 -1
+// #endif
+// #ifndef NC_HAS_DAP4
+// #define NC_HAS_DAP4 -1
+// #endif
+// #ifndef NC_HAS_NCZARR
+// #define NC_HAS_NCZARR -1
+// #endif
+// #ifndef NC_HAS_SZIP_WRITE
+// #define NC_HAS_SZIP_WRITE -1
+// #endif
+// #ifndef NC_HAS_ZSTD
+// #define NC_HAS_ZSTD -1
+// #endif
+// #ifndef NC_HAS_PARALLEL
+// #define NC_HAS_PARALLEL -1
+// #endif
+//
+// // GO_NC_TYPED returns the result of calling the variant of the
+// // function f for memory type t, with the arguments that follow and ip.
+// #define GO_NC_TYPED(f, t, ...) \
+// 	switch (t) { \
+// 	case NC_BYTE: return f##_schar(__VA_ARGS__, (signed char *)ip); \
+// 	case NC_CHAR: return f##_text(__VA_ARGS__, (char *)ip); \
+// 	case NC_SHORT: return f##_short(__VA_ARGS__, (short *)ip); \
+// 	case NC_INT: return f##_int(__VA_ARGS__, (int *)ip); \
+// 	case NC_FLOAT: return f##_float(__VA_ARGS__, (float *)ip); \
+// 	case NC_DOUBLE: return f##_double(__VA_ARGS__, (double *)ip); \
+// 	case NC_UBYTE: return f##_uchar(__VA_ARGS__, (unsigned char *)ip); \
+// 	case NC_USHORT: return f##_ushort(__VA_ARGS__, (unsigned short *)ip); \
+// 	case NC_UINT: return f##_uint(__VA_ARGS__, (unsigned int *)ip); \
+// 	case NC_INT64: return f##_longlong(__VA_ARGS__, (long long *)ip); \
+// 	case NC_UINT64: return f##_ulonglong(__VA_ARGS__, (unsigned long long *)ip); \
+// 	} \
+// 	return NC_EBADTYPE;
+//
+// static int go_nc_get_var(int ncid, int varid, nc_type t, void *ip) {
+// 	GO_NC_TYPED(nc_get_var, t, ncid, varid)
+// }
+//
+// static int go_nc_put_var(int ncid, int varid, nc_type t, void *ip) {
+// 	GO_NC_TYPED(nc_put_var, t, ncid, varid)
+// }
+//
+// static int go_nc_get_var1(int ncid, int varid, nc_type t, const size_t *index, void *ip) {
+// 	GO_NC_TYPED(nc_get_var1, t, ncid, varid, index)
+// }
+//
+// static int go_nc_put_var1(int ncid, int varid, nc_type t, const size_t *index, void *ip) {
+// 	GO_NC_TYPED(nc_put_var1, t, ncid, varid, index)
+// }
+//
+// static int go_nc_get_vara(int ncid, int varid, nc_type t, const size_t *start, const size_t *count, void *ip) {
+// 	GO_NC_TYPED(nc_get_vara, t, ncid, varid, start, count)
+// }
+//
+// static int go_nc_put_vara(int ncid, int varid, nc_type t, const size_t *start, const size_t *count, void *ip) {
+// 	GO_NC_TYPED(nc_put_vara, t, ncid, varid, start, count)
+// }
+//
+// static int go_nc_get_vars(int ncid, int varid, nc_type t, const size_t *start, const size_t *count, const ptrdiff_t *stride, void *ip) {
+// 	GO_NC_TYPED(nc_get_vars, t, ncid, varid, start, count, stride)
+// }
+//
+// static int go_nc_put_vars(int ncid, int varid, nc_type t, const size_t *start, const size_t *count, const ptrdiff_t *stride, void *ip) {
+// 	GO_NC_TYPED(nc_put_vars, t, ncid, varid, start, count, stride)
+// }
+//
+// static int go_nc_get_att(int ncid, int varid, const char *name, nc_type t, void *ip) {
+// 	GO_NC_TYPED(nc_get_att, t, ncid, varid, name)
+// }
+//
+// // nc_put_att_text has no type argument, unlike the other variants
+// // of nc_put_att, so GO_NC_TYPED can't be used.
+// static int go_nc_put_att(int ncid, int varid, const char *name, nc_type t, size_t len, void *ip) {
+// 	switch (t) {
+// 	case NC_CHAR: return nc_put_att_text(ncid, varid, name, len, (char *)ip);
+// 	case NC_BYTE: return nc_put_att_schar(ncid, varid, name, t, len, (signed char *)ip);
+// 	case NC_SHORT: return nc_put_att_short(ncid, varid, name, t, len, (short *)ip);
+// 	case NC_INT: return nc_put_att_int(ncid, varid, name, t, len, (int *)ip);
+// 	case NC_FLOAT: return nc_put_att_float(ncid, varid, name, t, len, (float *)ip);
+// 	case NC_DOUBLE: return nc_put_att_double(ncid, varid, name, t, len, (double *)ip);
+// 	case NC_UBYTE: return nc_put_att_uchar(ncid, varid, name, t, len, (unsigned char *)ip);
+// 	case NC_USHORT: return nc_put_att_ushort(ncid, varid, name, t, len, (unsigned short *)ip);
+// 	case NC_UINT: return nc_put_att_uint(ncid, varid, name, t, len, (unsigned int *)ip);
+// 	case NC_INT64: return nc_put_att_longlong(ncid, varid, name, t, len, (long long *)ip);
+// 	case NC_UINT64: return nc_put_att_ulonglong(ncid, varid, name, t, len, (unsigned long long *)ip);
+// 	}
+// 	return NC_EBADTYPE;
+// }
+import "C"
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+	"github.com/fhs/go-netcdf/netcdf/internal/lock"
+)
+
+func newError(n C.int) error {
+	if n == C.NC_NOERR {
+		return nil
+	}
+	return Error(n)
+}
+
+func strerror(e Error) string {
+	lock.Lock()
+	defer lock.Unlock()
+	return C.GoString(C.nc_strerror(C.int(e)))
+}
+
+// Version returns a string identifying the version of the netCDF library,
+// and when it was built.
+func Version() string {
+	lock.Lock()
+	defer lock.Unlock()
+	return C.GoString(C.nc_inq_libvers())
+}
+
+// libMeta returns the NC_HAS macros of netcdf_meta.h, which describe the
+// features the library was built with. Macros unknown to the library
+// are -1.
+func libMeta() map[string]int {
+	return map[string]int{
+		"NC_HAS_NC4":        int(C.NC_HAS_NC4),
+		"NC_HAS_CDF5":       int(C.NC_HAS_CDF5),
+		"NC_HAS_DAP":        int(C.NC_HAS_DAP),
+		"NC_HAS_DAP2":       int(C.NC_HAS_DAP2),
+		"NC_HAS_DAP4":       int(C.NC_HAS_DAP4),
+		"NC_HAS_NCZARR":     int(C.NC_HAS_NCZARR),
+		"NC_HAS_SZIP_WRITE": int(C.NC_HAS_SZIP_WRITE),
+		"NC_HAS_ZSTD":       int(C.NC_HAS_ZSTD),
+		"NC_HAS_PARALLEL":   int(C.NC_HAS_PARALLEL),
+	}
+}
+
+// NewDataset returns a Dataset for the dataset with netCDF ID id, which was
+// opened or created through the C library by other means, such as the
+// in-memory functions of package ncmem. Path is only used to describe the
+// dataset, and may be empty.
+func NewDataset(id int, path string) Dataset {
+	return newHandle(cdataset(id), path)
+}
+
+func createDriver(path string, mode FileMode) (driver.Dataset, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	if err := newError(C.nc_create(cpath, C.int(mode), &id)); err != nil {
+		return nil, err
+	}
+	return cdataset(id), nil
+}
+
+func createDriverWithOptions(path string, mode FileMode, initialSize, bufferSize uint64) (driver.Dataset, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var id C.int
+	hint := C.size_t(bufferSize)
+	lock.Lock()
+	defer lock.Unlock()
+	if err := newError(C.nc__create(cpath, C.int(mode), C.size_t(initialSize), &hint, &id)); err != nil {
+		return nil, err
+	}
+	return cdataset(id), nil
+}
+
+func openDriver(path string, mode FileMode) (driver.Dataset, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	if err := newError(C.nc_open(cpath, C.int(mode), &id)); err != nil {
+		return nil, err
+	}
+	return cdataset(id), nil
+}
+
+// cdataset is the driver of datasets opened through the netCDF C library.
+// Its value is the netCDF ID of the dataset.
+type cdataset C.int
+
+// ID returns the netCDF ID of the dataset.
+func (d cdataset) ID() int {
+	return int(d)
+}
+
+func (d cdataset) Close() error {
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.nc_close(C.int(d)))
+}
+
+func (d cdataset) EndDef() error {
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.nc_enddef(C.int(d)))
+}
+
+func (d cdataset) EndDefWithOptions(hMinFree, vAlign, vMinFree, rAlign uint64) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.nc__enddef(C.int(d), C.size_t(hMinFree), C.size_t(vAlign), C.size_t(vMinFree), C.size_t(rAlign)))
+}
+
+func (d cdataset) Redef() error {
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.nc_redef(C.int(d)))
+}
+
+func (d cdataset) NDims() (int, error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_ndims(C.int(d), &n))
+	return int(n), err
+}
+
+func (d cdataset) NVars() (int, error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_nvars(C.int(d), &n))
+	return int(n), err
+}
+
+func (d cdataset) NAttrs(varid int) (int, error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	if varid == driver.Global {
+		err := newError(C.nc_inq_natts(C.int(d), &n))
+		return int(n), err
+	}
+	err := newError(C.nc_inq_varnatts(C.int(d), C.int(varid), &n))
+	return int(n), err
+}
+
+func (d cdataset) UnlimitedDims() ([]int, error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_unlimdims(C.int(d), &n, nil))
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	ids := make([]C.int, n)
+	if err := newError(C.nc_inq_unlimdims(C.int(d), &n, &ids[0])); err != nil {
+		return nil, err
+	}
+	return goInts(ids), nil
+}
+
+func (d cdataset) DefGrp(name string) (driver.Dataset, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	if err := newError(C.nc_def_grp(C.int(d), cname, &id)); err != nil {
+		return nil, err
+	}
+	return cdataset(id), nil
+}
+
+func (d cdataset) Grp(name string) (driver.Dataset, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	if err := newError(C.nc_inq_grp_ncid(C.int(d), cname, &id)); err != nil {
+		return nil, err
+	}
+	return cdataset(id), nil
+}
+
+func (d cdataset) GrpNames() ([]string, error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_grps(C.int(d), &n, nil))
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	ids := make([]C.int, n)
+	if err := newError(C.nc_inq_grps(C.int(d), &n, &ids[0])); err != nil {
+		return nil, err
+	}
+	buf := C.CString(string(make([]byte, C.NC_MAX_NAME+1)))
+	defer C.free(unsafe.Pointer(buf))
+	names := make([]string, n)
+	for i, id := range ids {
+		if err := newError(C.nc_inq_grpname(id, buf)); err != nil {
+			return nil, err
+		}
+		names[i] = C.GoString(buf)
+	}
+	return names, nil
+}
+
+func (d cdataset) DimIDs() ([]int, error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_dimids(C.int(d), &n, nil, 0))
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	ids := make([]C.int, n)
+	if err := newError(C.nc_inq_dimids(C.int(d), &n, &ids[0], 0)); err != nil {
+		return nil, err
+	}
+	return goInts(ids), nil
+}
+
+func (d cdataset) DefDim(name string, len uint64) (int, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_def_dim(C.int(d), cname, C.size_t(len), &id))
+	return int(id), err
+}
+
+func (d cdataset) DimID(name string) (int, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_dimid(C.int(d), cname, &id))
+	return int(id), err
+}
+
+func (d cdataset) DimName(dimid int) (string, error) {
+	buf := C.CString(string(make([]byte, C.NC_MAX_NAME+1)))
+	defer C.free(unsafe.Pointer(buf))
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_dimname(C.int(d), C.int(dimid), buf))
+	return C.GoString(buf), err
+}
+
+func (d cdataset) DimLen(dimid int) (uint64, error) {
+	var n C.size_t
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_dimlen(C.int(d), C.int(dimid), &n))
+	return uint64(n), err
+}
+
+func (d cdataset) DefVar(name string, t int, dimids []int) (int, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var dimPtr *C.int
+	if len(dimids) > 0 {
+		ids := make([]C.int, len(dimids))
+		for i, id := range dimids {
+			ids[i] = C.int(id)
+		}
+		dimPtr = &ids[0]
+	}
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_def_var(C.int(d), cname, C.nc_type(t),
+		C.int(len(dimids)), dimPtr, &id))
+	return int(id), err
+}
+
+func (d cdataset) VarID(name string) (int, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var id C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_varid(C.int(d), cname, &id))
+	return int(id), err
+}
+
+func (d cdataset) VarName(varid int) (string, error) {
+	buf := C.CString(string(make([]byte, C.NC_MAX_NAME+1)))
+	defer C.free(unsafe.Pointer(buf))
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_varname(C.int(d), C.int(varid), buf))
+	return C.GoString(buf), err
+}
+
+func (d cdataset) VarType(varid int) (int, error) {
+	var t C.nc_type
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_vartype(C.int(d), C.int(varid), &t))
+	return int(t), err
+}
+
+func (d cdataset) VarDims(varid int) ([]int, error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_varndims(C.int(d), C.int(varid), &n))
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	ids := make([]C.int, n)
+	if err := newError(C.nc_inq_vardimid(C.int(d), C.int(varid), &ids[0])); err != nil {
+		return nil, err
+	}
+	return goInts(ids), nil
+}
+
+func (d cdataset) SetDeflate(varid int, shuffle, deflate bool, level int) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.nc_def_var_deflate(C.int(d), C.int(varid),
+		cbool(shuffle), cbool(deflate), C.int(level)))
+}
+
+func (d cdataset) Deflate(varid int) (shuffle, deflate bool, level int, err error) {
+	var cShuffle, cDeflate, cLevel C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err = newError(C.nc_inq_var_deflate(C.int(d), C.int(varid), &cShuffle, &cDeflate, &cLevel))
+	return cShuffle != 0, cDeflate != 0, int(cLevel), err
+}
+
+func (d cdataset) SetChunking(varid int, contiguous bool, sizes []uint64) error {
+	storage := C.int(C.NC_CHUNKED)
+	if contiguous {
+		storage = C.NC_CONTIGUOUS
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.nc_def_var_chunking(C.int(d), C.int(varid), storage, csizes(sizes)))
+}
+
+func (d cdataset) Chunking(varid int) (contiguous bool, sizes []uint64, err error) {
+	var n C.int
+	lock.Lock()
+	defer lock.Unlock()
+	err = newError(C.nc_inq_varndims(C.int(d), C.int(varid), &n))
+	if err != nil {
+		return
+	}
+	sizes = make([]uint64, n)
+	var storage C.int
+	err = newError(C.nc_inq_var_chunking(C.int(d), C.int(varid), &storage, csizes(sizes)))
+	if err != nil || storage != C.NC_CHUNKED {
+		return true, nil, err
+	}
+	return false, sizes, nil
+}
+
+func (d cdataset) AttrName(varid, n int) (string, error) {
+	buf := C.CString(string(make([]byte, C.NC_MAX_NAME+1)))
+	defer C.free(unsafe.Pointer(buf))
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_attname(C.int(d), C.int(varid), C.int(n), buf))
+	return C.GoString(buf), err
+}
+
+func (d cdataset) AttrType(varid int, name string) (int, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var t C.nc_type
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_atttype(C.int(d), C.int(varid), cname, &t))
+	return int(t), err
+}
+
+func (d cdataset) AttrLen(varid int, name string) (uint64, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var n C.size_t
+	lock.Lock()
+	defer lock.Unlock()
+	err := newError(C.nc_inq_attlen(C.int(d), C.int(varid), cname, &n))
+	return uint64(n), err
+}
+
+func (d cdataset) GetAttr(varid int, name string, t int, val interface{}) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.go_nc_get_att(C.int(d), C.int(varid), cname, C.nc_type(t), pointer(val)))
+}
+
+func (d cdataset) PutAttr(varid int, name string, t int, val interface{}) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	n := reflect.ValueOf(val).Len()
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.go_nc_put_att(C.int(d), C.int(varid), cname, C.nc_type(t),
+		C.size_t(n), pointer(val)))
+}
+
+func (d cdataset) GetVar(varid, t int, data interface{}) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.go_nc_get_var(C.int(d), C.int(varid), C.nc_type(t), pointer(data)))
+}
+
+func (d cdataset) PutVar(varid, t int, data interface{}) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return newError(C.go_nc_put_var(C.int(d), C.int(varid), C.nc_type(t), pointer(data)))
+}
+
+func (d cdataset) GetVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	lock.Lock()
+	defer lock.Unlock()
+	switch {
+	case count == nil:
+		return newError(C.go_nc_get_var1(C.int(d), C.int(varid), C.nc_type(t),
+			csizes(start), pointer(data)))
+	case stride == nil:
+		return newError(C.go_nc_get_vara(C.int(d), C.int(varid), C.nc_type(t),
+			csizes(start), csizes(count), pointer(data)))
+	}
+	return newError(C.go_nc_get_vars(C.int(d), C.int(varid), C.nc_type(t),
+		csizes(start), csizes(count), cstrides(stride), pointer(data)))
+}
+
+func (d cdataset) PutVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	lock.Lock()
+	defer lock.Unlock()
+	switch {
+	case count == nil:
+		return newError(C.go_nc_put_var1(C.int(d), C.int(varid), C.nc_type(t),
+			csizes(start), pointer(data)))
+	case stride == nil:
+		return newError(C.go_nc_put_vara(C.int(d), C.int(varid), C.nc_type(t),
+			csizes(start), csizes(count), pointer(data)))
+	}
+	return newError(C.go_nc_put_vars(C.int(d), C.int(varid), C.nc_type(t),
+		csizes(start), csizes(count), cstrides(stride), pointer(data)))
+}
+
+// copyDriverAttr copies attribute name of variable varid of src into the
+// attributes of variable dstVarid of dst with nc_copy_att, if both datasets
+// are opened through the C library. Ok is false if they aren't.
+func copyDriverAttr(src driver.Dataset, varid int, name string, dst driver.Dataset, dstVarid int) (ok bool, err error) {
+	s, ok1 := src.(cdataset)
+	d, ok2 := dst.(cdataset)
+	if !ok1 || !ok2 {
+		return false, nil
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	lock.Lock()
+	defer lock.Unlock()
+	return true, newError(C.nc_copy_att(C.int(s), C.int(varid), cname, C.int(d), C.int(dstVarid)))
+}
+
+// rawTypeSize returns the size in bytes of values of type t in the dataset
+// of driver d, as reported by nc_inq_type, if d is opened through the C
+// library. Ok is false if it isn't. Types whose values hold pointers to
+// memory allocated by the library, STRING and VLEN types, are EBADTYPE.
+func rawTypeSize(d driver.Dataset, t Type) (size uint64, ok bool, err error) {
+	c, ok := d.(cdataset)
+	if !ok {
+		return 0, false, nil
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if t == STRING {
+		return 0, true, EBADTYPE
+	}
+	var n C.size_t
+	if t > C.NC_MAX_ATOMIC_TYPE {
+		var class C.int
+		if err := newError(C.nc_inq_user_type(C.int(c), C.nc_type(t), nil, &n, nil, nil, &class)); err != nil {
+			return 0, true, err
+		}
+		if class == C.NC_VLEN {
+			return 0, true, EBADTYPE
+		}
+		return uint64(n), true, nil
+	}
+	if err := newError(C.nc_inq_type(C.int(c), C.nc_type(t), nil, &n)); err != nil {
+		return 0, true, err
+	}
+	return uint64(n), true, nil
+}
+
+// transferDriverRaw reads or writes buf as the values of variable varid
+// selected by start and count, with nc_get_vara or nc_put_vara, if d is
+// opened through the C library. Ok is false if it isn't.
+func transferDriverRaw(d driver.Dataset, varid int, start, count []uint64, buf []byte, write bool) (ok bool, err error) {
+	c, ok := d.(cdataset)
+	if !ok {
+		return false, nil
+	}
+	var p unsafe.Pointer
+	if len(buf) > 0 {
+		p = unsafe.Pointer(&buf[0])
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if write {
+		return true, newError(C.nc_put_vara(C.int(c), C.int(varid), csizes(start), csizes(count), p))
+	}
+	return true, newError(C.nc_get_vara(C.int(c), C.int(varid), csizes(start), csizes(count), p))
+}
+
+func goInts(ids []C.int) []int {
+	s := make([]int, len(ids))
+	for i, id := range ids {
+		s[i] = int(id)
+	}
+	return s
+}
+
+func cbool(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// csizes returns a pointer to the first element of s, or nil if it's empty.
+func csizes(s []uint64) *C.size_t {
+	if len(s) == 0 {
+		return nil
+	}
+	return (*C.size_t)(unsafe.Pointer(&s[0]))
+}
+
+// cstrides returns a pointer to the first element of s, or nil if it's empty.
+func cstrides(s []int64) *C.ptrdiff_t {
+	if len(s) == 0 {
+		return nil
+	}
+	return (*C.ptrdiff_t)(unsafe.Pointer(&s[0]))
+}
+
+// pointer returns a pointer to the first element of the slice data,
+// or nil if it's empty.
+func pointer(data interface{}) unsafe.Pointer {
+	v := reflect.ValueOf(data)
+	if v.Len() == 0 {
+		return nil
+	}
+	return unsafe.Pointer(v.Index(0).UnsafeAddr())
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Command ncconvert copies a netCDF file, optionally changing its format,
+// compression and chunking, like nccopy.
+//
+// Usage:
+//
+//	ncconvert [flags] infile outfile
+//
+// The flags are:
+//
+//	-k kind
+//		format of outfile: classic, 64bit, nc4 or nc4classic (default nc4)
+//	-d level
+//		deflate level from 1 to 9 for all variables; 0 keeps the
+//		compression of infile and -1 turns compression off
+//	-s
+//		turn on the shuffle filter for variables compressed with -d
+//	-c dim/n,...
+//		chunk length along dimensions, such as time/1,lat/180,lon/360
+//	-V var,...
+//		copy only the given variables, named by their path in groups
+//	-m bytes
+//		size of the buffer used to copy data (default 8 MiB)
+//
+// Data is copied in pieces that fit in the buffer, so large variables can
+// be copied with little memory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+var kinds = map[string]netcdf.FileMode{
+	"classic":    0,
+	"64bit":      netcdf.OFFSET_64BIT,
+	"nc4":        netcdf.NETCDF4,
+	"nc4classic": netcdf.NETCDF4 | netcdf.CLASSIC_MODEL,
+}
+
+func main() {
+	kind := flag.String("k", "nc4", "format of outfile: classic, 64bit, nc4 or nc4classic")
+	deflate := flag.Int("d", 0, "deflate `level` (1-9); 0 keeps the input compression, -1 turns it off")
+	shuffle := flag.Bool("s", false, "turn on the shuffle filter for variables compressed with -d")
+	chunks := flag.String("c", "", "chunk lengths as `dim/n,...`")
+	vars := flag.String("V", "", "copy only these variables, as `var,...`")
+	mem := flag.Uint64("m", 8<<20, "size of the copy buffer in `bytes`")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: ncconvert [flags] infile outfile\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	mode, ok := kinds[*kind]
+	if !ok {
+		fatalf("unknown format %q", *kind)
+	}
+	opts := &netcdf.CopyOptions{
+		DeflateLevel: *deflate,
+		Shuffle:      *shuffle,
+		// Values take at most 8 bytes.
+		Transfer: &netcdf.TransferOptions{PieceLen: *mem/8 + 1},
+	}
+	if *vars != "" {
+		opts.Vars = strings.Split(*vars, ",")
+	}
+	var err error
+	if opts.Chunks, err = parseChunks(*chunks); err != nil {
+		fatalf("%v", err)
+	}
+
+	if err := convert(flag.Arg(1), flag.Arg(0), mode, opts); err != nil {
+		os.Remove(flag.Arg(1))
+		fatalf("%v", err)
+	}
+}
+
+// convert copies the file at src into a new file at dst of the given
+// format.
+func convert(dst, src string, mode netcdf.FileMode, opts *netcdf.CopyOptions) error {
+	in, err := netcdf.OpenFile(src, netcdf.NOWRITE)
+	if err != nil {
+		return fmt.Errorf("%s: %v", src, err)
+	}
+	defer in.Close()
+	out, err := netcdf.CreateFile(dst, netcdf.CLOBBER|mode)
+	if err != nil {
+		return fmt.Errorf("%s: %v", dst, err)
+	}
+	if err := netcdf.Copy(out, in, opts); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// parseChunks parses chunk lengths given as dim/n,dim/n...
+func parseChunks(spec string) (map[string]uint64, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	chunks := make(map[string]uint64)
+	for _, c := range strings.Split(spec, ",") {
+		i := strings.LastIndex(c, "/")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid chunk length %q: expected dim/n", c)
+		}
+		n, err := strconv.ParseUint(c[i+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chunk length %q: %v", c, err)
+		}
+		chunks[c[:i]] = n
+	}
+	return chunks, nil
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "ncconvert: "+format+"\n", args...)
+	os.Exit(1)
+}
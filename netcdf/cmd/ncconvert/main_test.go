@@ -0,0 +1,33 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChunks(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		want map[string]uint64
+	}{
+		{"", nil},
+		{"time/1", map[string]uint64{"time": 1}},
+		{"time/1,lat/180,lon/0", map[string]uint64{"time": 1, "lat": 180, "lon": 0}},
+	} {
+		got, err := parseChunks(tc.spec)
+		if err != nil {
+			t.Errorf("parseChunks(%q) failed: %v\n", tc.spec, err)
+		} else if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseChunks(%q) is %v; expected %v\n", tc.spec, got, tc.want)
+		}
+	}
+	for _, spec := range []string{"time", "/1", "time/x", "time/-1", "time/1,"} {
+		if _, err := parseChunks(spec); err == nil {
+			t.Errorf("parseChunks(%q) succeeded\n", spec)
+		}
+	}
+}
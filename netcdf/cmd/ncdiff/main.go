@@ -0,0 +1,76 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Command ncdiff compares two netCDF files.
+//
+// Usage:
+//
+//	ncdiff [flags] file1 file2
+//
+// It prints the dimensions, variables, attributes and groups that were
+// added, removed or modified, and for each variable whose values differ,
+// the number of differing values, the index of the first one, and the
+// maximum absolute and relative differences. The flags are:
+//
+//	-abs tol
+//		values within tol of each other are equal
+//	-rel tol
+//		values within tol times the largest magnitude are equal
+//	-nan
+//		NaN values are equal to each other
+//
+// The tolerances only apply to floating point values; integer values are
+// compared exactly.
+//
+// The exit status is 0 if the files are equal, 1 if they differ, and 2 if
+// they can't be compared, like diff.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+func main() {
+	var opts netcdf.DiffOptions
+	flag.Float64Var(&opts.AbsTol, "abs", 0, "absolute `tolerance`")
+	flag.Float64Var(&opts.RelTol, "rel", 0, "relative `tolerance`")
+	flag.BoolVar(&opts.NaNEqual, "nan", false, "NaN values are equal to each other")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: ncdiff [flags] file1 file2\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	r, err := diff(flag.Arg(0), flag.Arg(1), &opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ncdiff: %v\n", err)
+		os.Exit(2)
+	}
+	if !r.Equal() {
+		fmt.Print(r)
+		os.Exit(1)
+	}
+}
+
+func diff(path1, path2 string, opts *netcdf.DiffOptions) (*netcdf.DiffReport, error) {
+	a, err := netcdf.OpenFile(path1, netcdf.NOWRITE)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path1, err)
+	}
+	defer a.Close()
+	b, err := netcdf.OpenFile(path2, netcdf.NOWRITE)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path2, err)
+	}
+	defer b.Close()
+	return netcdf.Diff(a, b, opts)
+}
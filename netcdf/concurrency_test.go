@@ -0,0 +1,270 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// These tests are meant to be run with the race detector enabled
+// (go test -race). They hammer the C library from many goroutines to
+// make sure every call into it is serialized.
+
+const (
+	concurrentWorkers = 16
+	concurrentRounds  = 20
+)
+
+// runConcurrently calls f from n goroutines and reports every error returned.
+func runConcurrently(t *testing.T, n int, f func(worker int) error) {
+	var wg sync.WaitGroup
+	errc := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := f(i); err != nil {
+				errc <- fmt.Errorf("worker %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errc)
+	for err := range errc {
+		t.Error(err)
+	}
+}
+
+// createConcurrentFile creates a dataset at filename with a
+// variable "gopher" of shape rows x cols, filled with testWriteFloat64s data.
+func createConcurrentFile(filename string, mode FileMode, rows, cols uint64) error {
+	ds, err := CreateFile(filename, mode)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+	dims := make([]Dim, 2)
+	if dims[0], err = ds.AddDim("rows", rows); err != nil {
+		return err
+	}
+	if dims[1], err = ds.AddDim("cols", cols); err != nil {
+		return err
+	}
+	v, err := ds.AddVar("gopher", DOUBLE, dims)
+	if err != nil {
+		return err
+	}
+	if err := v.Attr("units").WriteBytes([]byte("furlongs")); err != nil {
+		return err
+	}
+	if err := ds.EndDef(); err != nil {
+		return err
+	}
+	return testWriteFloat64s(v, rows*cols)
+}
+
+// checkConcurrentVar reads v, created by createConcurrentFile, in a few
+// different ways and checks the values.
+func checkConcurrentVar(v Var) error {
+	name, err := v.Name()
+	if err != nil {
+		return err
+	}
+	if name != "gopher" {
+		return fmt.Errorf("variable name is %q; expected %q", name, "gopher")
+	}
+	units, err := GetBytes(v.Attr("units"))
+	if err != nil {
+		return err
+	}
+	if string(units) != "furlongs" {
+		return fmt.Errorf("units is %q; expected %q", units, "furlongs")
+	}
+	n, err := v.Len()
+	if err != nil {
+		return err
+	}
+	if err := testReadFloat64s(v, n); err != nil {
+		return err
+	}
+	shape, err := v.LenDims()
+	if err != nil {
+		return err
+	}
+	row := make([]float64, shape[1])
+	for r := uint64(0); r < shape[0]; r++ {
+		err := v.ReadFloat64Slice(row, []uint64{r, 0}, []uint64{1, shape[1]})
+		if err != nil {
+			return err
+		}
+		for c, val := range row {
+			if want := float64(r*shape[1] + uint64(c) + 10); val != want {
+				return fmt.Errorf("data at (%d, %d) is %v; expected %v", r, c, val, want)
+			}
+		}
+	}
+	return nil
+}
+
+func TestConcurrentFiles(t *testing.T) {
+	skipWithoutC(t)
+	dir, err := ioutil.TempDir("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	modes := []FileMode{CLOBBER, CLOBBER | OFFSET_64BIT, CLOBBER | NETCDF4}
+	runConcurrently(t, concurrentWorkers, func(worker int) error {
+		filename := filepath.Join(dir, fmt.Sprintf("gopher%d.nc", worker))
+		for i := 0; i < concurrentRounds; i++ {
+			mode := modes[(worker+i)%len(modes)]
+			if err := createConcurrentFile(filename, mode, 7, 5); err != nil {
+				return err
+			}
+			ds, err := OpenFile(filename, NOWRITE)
+			if err != nil {
+				return err
+			}
+			v, err := ds.Var("gopher")
+			if err != nil {
+				ds.Close()
+				return err
+			}
+			if err := checkConcurrentVar(v); err != nil {
+				ds.Close()
+				return err
+			}
+			if err := ds.Close(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestConcurrentReads(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	if err := createConcurrentFile(f.Name(), CLOBBER|NETCDF4, 64, 32); err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+
+	shared, err := OpenFile(f.Name(), NOWRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer shared.Close()
+
+	runConcurrently(t, concurrentWorkers, func(worker int) error {
+		for i := 0; i < concurrentRounds; i++ {
+			// Even workers share a handle, odd workers open their own.
+			ds := shared
+			if worker%2 == 1 {
+				own, err := OpenFile(f.Name(), NOWRITE)
+				if err != nil {
+					return err
+				}
+				ds = own
+			}
+			v, err := ds.Var("gopher")
+			if err == nil {
+				err = checkConcurrentVar(v)
+			}
+			if worker%2 == 1 {
+				if cerr := ds.Close(); err == nil {
+					err = cerr
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+
+	ds, err := CreateFile(f.Name(), CLOBBER|NETCDF4)
+	if err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	defer ds.Close()
+	const cols = 100
+	dims := make([]Dim, 2)
+	if dims[0], err = ds.AddDim("rows", concurrentWorkers); err != nil {
+		t.Fatalf("adding dimension failed: %v\n", err)
+	}
+	if dims[1], err = ds.AddDim("cols", cols); err != nil {
+		t.Fatalf("adding dimension failed: %v\n", err)
+	}
+	v, err := ds.AddVar("gopher", INT, dims)
+	if err != nil {
+		t.Fatalf("adding variable failed: %v\n", err)
+	}
+
+	// Each worker owns one row, alternating between whole-row and
+	// element-wise writes, while reading back the rows of other workers.
+	runConcurrently(t, concurrentWorkers, func(worker int) error {
+		row := make([]int32, cols)
+		for i := 0; i < concurrentRounds; i++ {
+			for c := range row {
+				row[c] = int32(worker*cols + c + i)
+			}
+			if i%2 == 0 {
+				err := v.WriteInt32Slice(row, []uint64{uint64(worker), 0}, []uint64{1, cols})
+				if err != nil {
+					return err
+				}
+			} else {
+				for c, val := range row {
+					if err := v.WriteInt32At([]uint64{uint64(worker), uint64(c)}, val); err != nil {
+						return err
+					}
+				}
+			}
+			other := make([]int32, cols)
+			start := []uint64{uint64((worker + 1) % concurrentWorkers), 0}
+			if err := v.ReadInt32Slice(other, start, []uint64{1, cols}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	data, err := GetInt32s(v)
+	if err != nil {
+		t.Fatalf("reading data failed: %v\n", err)
+	}
+	for i, val := range data {
+		if want := int32(i + concurrentRounds - 1); val != want {
+			t.Fatalf("data at position %d is %v; expected %v\n", i, val, want)
+		}
+	}
+}
@@ -4,50 +4,49 @@
 
 package netcdf
 
-// #include <netcdf.h>
-import "C"
-
-// FileMode represents a file's mode.
-type FileMode C.int
+// FileMode represents a file's mode. File modes have the same values as
+// in the C library.
+type FileMode int32
 
 // File modes for Open or Create
 const (
-	SHARE FileMode = C.NC_SHARE // share updates, limit cacheing
+	SHARE FileMode = 0x0800 // share updates, limit cacheing
 )
 
 // File modes for Open
 const (
-	NOWRITE FileMode = C.NC_NOWRITE // set read-only access
-	WRITE   FileMode = C.NC_WRITE   // set read-write access
+	NOWRITE FileMode = 0x0000 // set read-only access
+	WRITE   FileMode = 0x0001 // set read-write access
 )
 
 // File modes for Create
 const (
-	CLOBBER       FileMode = C.NC_CLOBBER       // destroy existing file
-	NOCLOBBER     FileMode = C.NC_NOCLOBBER     // don't destroy existing file
-	CLASSIC_MODEL FileMode = C.NC_CLASSIC_MODEL // enforce classic model
-	NETCDF4       FileMode = C.NC_NETCDF4       // use netCDF-4/HDF5 format
-	OFFSET_64BIT  FileMode = C.NC_64BIT_OFFSET  // use large (64-bit) file offsets
+	CLOBBER       FileMode = 0x0000 // destroy existing file
+	NOCLOBBER     FileMode = 0x0004 // don't destroy existing file
+	CLASSIC_MODEL FileMode = 0x0100 // enforce classic model
+	NETCDF4       FileMode = 0x1000 // use netCDF-4/HDF5 format
+	OFFSET_64BIT  FileMode = 0x0200 // use large (64-bit) file offsets
 )
 
 // Type is a netCDF external data type.
-type Type C.nc_type
+type Type int32
 
-// Type declarations according to C standards
+// Type declarations according to C standards. They have the same values
+// as the types of the C library.
 const (
-	BYTE   Type = C.NC_BYTE   // signed 1 byte integer
-	CHAR   Type = C.NC_CHAR   // ISO/ASCII character
-	SHORT  Type = C.NC_SHORT  // signed 2 byte integer
-	INT    Type = C.NC_INT    // signed 4 byte integer
-	LONG   Type = C.NC_LONG   // deprecated, but required for backward compatibility.
-	FLOAT  Type = C.NC_FLOAT  // single precision floating point number
-	DOUBLE Type = C.NC_DOUBLE // double precision floating point number
-	UBYTE  Type = C.NC_UBYTE  // unsigned 1 byte int
-	USHORT Type = C.NC_USHORT // unsigned 2-byte int
-	UINT   Type = C.NC_UINT   // unsigned 4-byte int
-	INT64  Type = C.NC_INT64  // signed 8-byte int
-	UINT64 Type = C.NC_UINT64 // unsigned 8-byte int
-	STRING Type = C.NC_STRING // string
+	BYTE   Type = 1  // signed 1 byte integer
+	CHAR   Type = 2  // ISO/ASCII character
+	SHORT  Type = 3  // signed 2 byte integer
+	INT    Type = 4  // signed 4 byte integer
+	LONG   Type = 4  // deprecated, but required for backward compatibility.
+	FLOAT  Type = 5  // single precision floating point number
+	DOUBLE Type = 6  // double precision floating point number
+	UBYTE  Type = 7  // unsigned 1 byte int
+	USHORT Type = 8  // unsigned 2-byte int
+	UINT   Type = 9  // unsigned 4-byte int
+	INT64  Type = 10 // signed 8-byte int
+	UINT64 Type = 11 // unsigned 8-byte int
+	STRING Type = 12 // string
 )
 
 var typeNames = map[Type]string{
@@ -69,3 +68,20 @@ var typeNames = map[Type]string{
 func (t Type) String() string {
 	return typeNames[t]
 }
+
+// Size returns the size in bytes of a value of the atomic type t, which is
+// the size nc_inq_type of the C library reports, or 0 for STRING and other
+// types. The size of user-defined types depends on the dataset.
+func (t Type) Size() uint64 {
+	switch t {
+	case BYTE, CHAR, UBYTE:
+		return 1
+	case SHORT, USHORT:
+		return 2
+	case INT, FLOAT, UINT:
+		return 4
+	case DOUBLE, INT64, UINT64:
+		return 8
+	}
+	return 0
+}
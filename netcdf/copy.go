@@ -0,0 +1,334 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// CopyOptions controls Copy. A nil *CopyOptions copies everything as is.
+//
+// The format of the copy is the one of the destination dataset, chosen
+// when it's created. Compression and chunking are only supported by
+// netCDF-4 datasets: asking for them when copying into a classic dataset
+// fails, but the compression and the chunking of source variables are
+// silently dropped.
+type CopyOptions struct {
+	// Vars, if not nil, lists the variables to copy. Variables of groups
+	// are given by their path, such as "forecast/temp". All dimensions,
+	// groups and global attributes are copied.
+	Vars []string
+
+	// DeflateLevel, from 1 to 9, compresses all variables with that
+	// deflate level. Zero keeps the compression of the source variables,
+	// and -1 turns compression off.
+	DeflateLevel int
+
+	// Shuffle turns on the shuffle filter for variables compressed with
+	// DeflateLevel.
+	Shuffle bool
+
+	// Chunks gives the length of chunks along dimensions, by name.
+	// Variables using any of these dimensions are chunked, with chunks
+	// spanning their other dimensions entirely, except unlimited ones
+	// along which chunks have length 1. A length of 0 means the whole
+	// dimension. Other variables keep the chunking of the source.
+	Chunks map[string]uint64
+
+	// Transfer controls the copy of the data, which is done in pieces of
+	// at most Transfer.PieceLen values, so the memory used doesn't depend
+	// on the size of the variables.
+	Transfer *TransferOptions
+}
+
+// Copy copies the dimensions, variables, attributes and groups of dataset
+// src into dst, which must be a newly created dataset in define mode, and
+// then copies the data of the variables, as specified by opts. On return,
+// dst is in data mode.
+//
+// Only variables of atomic types other than STRING can be copied. Copy
+// checks the types of the variables to copy before changing dst.
+//
+// Opts may be nil to copy everything as is.
+func Copy(dst, src Dataset, opts *CopyOptions) error {
+	return copyDataset(context.Background(), dst, src, opts)
+}
+
+// varCopy is a variable to copy.
+type varCopy struct {
+	name     string
+	dst, src Var
+	t        Type
+	shape    []uint64
+}
+
+func copyDataset(ctx context.Context, dst, src Dataset, opts *CopyOptions) error {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	if opts.DeflateLevel < -1 || opts.DeflateLevel > 9 {
+		return fmt.Errorf("invalid deflate level %d", opts.DeflateLevel)
+	}
+	var want map[string]bool
+	if opts.Vars != nil {
+		want = make(map[string]bool)
+		for _, name := range opts.Vars {
+			want[name] = true
+		}
+	}
+	if err := checkCopy(src, "", want); err != nil {
+		return err
+	}
+	var vars []varCopy
+	if err := defineCopy(dst, src, "", opts, want, &vars); err != nil {
+		return err
+	}
+	for _, vc := range vars {
+		delete(want, vc.name)
+	}
+	for _, name := range opts.Vars {
+		if want[name] {
+			return fmt.Errorf("variable %q not found", name)
+		}
+	}
+	if err := dst.EndDef(); err != nil {
+		return err
+	}
+
+	var totalElements, totalBytes uint64
+	for _, vc := range vars {
+		totalElements += product(vc.shape)
+		totalBytes += product(vc.shape) * vc.t.Size()
+	}
+	pr := opts.Transfer.newProgress(totalElements, totalBytes)
+	for _, vc := range vars {
+		pr.setVar(vc.name)
+		if _, err := copyVar(ctx, vc.dst, vc.src, vc.t, vc.shape, opts.Transfer.pieceLen(), pr); err != nil {
+			return fmt.Errorf("variable %q: %v", vc.name, err)
+		}
+	}
+	pr.finish()
+	return nil
+}
+
+// checkCopy returns an error if a variable of the group src, whose path is
+// prefix, or of its groups, can't be copied. Only the variables in want are
+// checked, unless want is nil.
+func checkCopy(src Dataset, prefix string, want map[string]bool) error {
+	nvars, err := src.NVars()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < nvars; i++ {
+		v := src.VarN(i)
+		name, err := v.Name()
+		if err != nil {
+			return err
+		}
+		name = path.Join(prefix, name)
+		if want != nil && !want[name] {
+			continue
+		}
+		t, err := v.Type()
+		if err != nil {
+			return err
+		}
+		if !isNumber(t) {
+			return fmt.Errorf("variable %q: can't copy values of type %v", name, t)
+		}
+	}
+	groups, err := src.GroupNames()
+	if err != nil {
+		return err
+	}
+	for _, name := range groups {
+		g, err := src.Group(name)
+		if err != nil {
+			return err
+		}
+		if err := checkCopy(g, path.Join(prefix, name), want); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defineCopy defines the dimensions, variables and attributes of the group
+// src, whose path is prefix, in dst, and then does the same for its
+// groups. Variables are only defined if they're in want, unless want is
+// nil. They're appended to vars.
+func defineCopy(dst, src Dataset, prefix string, opts *CopyOptions, want map[string]bool, vars *[]varCopy) error {
+	dims, err := src.Dims()
+	if err != nil {
+		return err
+	}
+	unlimited, err := src.UnlimitedDims()
+	if err != nil {
+		return err
+	}
+	for _, d := range dims {
+		name, err := d.Name()
+		if err != nil {
+			return err
+		}
+		n, err := d.Len()
+		if err != nil {
+			return err
+		}
+		if containsDim(unlimited, d) {
+			n = 0
+		}
+		if _, err := dst.AddDim(name, n); err != nil {
+			return err
+		}
+	}
+	if err := copyAttrs(dst.globals(), src.globals()); err != nil {
+		return err
+	}
+
+	nvars, err := src.NVars()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < nvars; i++ {
+		vc := varCopy{src: src.VarN(i)}
+		name, err := vc.src.Name()
+		if err != nil {
+			return err
+		}
+		vc.name = path.Join(prefix, name)
+		if want != nil && !want[vc.name] {
+			continue
+		}
+		if vc.t, err = vc.src.Type(); err != nil {
+			return err
+		}
+		if vc.shape, err = vc.src.LenDims(); err != nil {
+			return err
+		}
+		sdims, err := vc.src.Dims()
+		if err != nil {
+			return err
+		}
+		ddims := make([]Dim, len(sdims))
+		dimNames := make([]string, len(sdims))
+		for j, d := range sdims {
+			if dimNames[j], err = d.Name(); err != nil {
+				return err
+			}
+			if ddims[j], err = dst.Dim(dimNames[j]); err != nil {
+				return err
+			}
+		}
+		if vc.dst, err = dst.AddVar(name, vc.t, ddims); err != nil {
+			return err
+		}
+		if err := copyStorage(vc.dst, vc.src, dimNames, containsAny(unlimited, sdims), opts); err != nil {
+			return fmt.Errorf("variable %q: %v", vc.name, err)
+		}
+		if err := copyAttrs(vc.dst, vc.src); err != nil {
+			return err
+		}
+		*vars = append(*vars, vc)
+	}
+
+	groups, err := src.GroupNames()
+	if err != nil {
+		return err
+	}
+	for _, name := range groups {
+		sg, err := src.Group(name)
+		if err != nil {
+			return err
+		}
+		dg, err := dst.AddGroup(name)
+		if err != nil {
+			return err
+		}
+		if err := defineCopy(dg, sg, path.Join(prefix, name), opts, want, vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containsAny returns, for each dimension in dims, whether it's in set.
+func containsAny(set, dims []Dim) []bool {
+	in := make([]bool, len(dims))
+	for i, d := range dims {
+		in[i] = containsDim(set, d)
+	}
+	return in
+}
+
+// copyStorage sets the compression and the chunking of dst, whose
+// dimensions are named dimNames, as asked by opts or as in src.
+// Unlimited tells which dimensions are unlimited.
+func copyStorage(dst, src Var, dimNames []string, unlimited []bool, opts *CopyOptions) error {
+	// Settings kept from the source are dropped if dst doesn't
+	// support them.
+	keep := func(err error) error {
+		if err == ENOTNC4 {
+			return nil
+		}
+		return err
+	}
+
+	chunked := false
+	if opts.Chunks != nil && len(dimNames) > 0 {
+		shape, err := src.LenDims()
+		if err != nil {
+			return err
+		}
+		sizes := make([]uint64, len(shape))
+		for i, name := range dimNames {
+			n, ok := opts.Chunks[name]
+			chunked = chunked || ok
+			switch {
+			case ok && n > 0 && (unlimited[i] || n < shape[i]):
+				sizes[i] = n
+			case unlimited[i] && !ok:
+				sizes[i] = 1
+			default:
+				sizes[i] = shape[i]
+			}
+			if sizes[i] == 0 {
+				sizes[i] = 1
+			}
+		}
+		if chunked {
+			if err := dst.SetChunking(false, sizes); err != nil {
+				return err
+			}
+		}
+	}
+	if !chunked && len(dimNames) > 0 {
+		contiguous, sizes, err := src.Chunking()
+		if err != nil {
+			return err
+		}
+		if !contiguous {
+			if err := keep(dst.SetChunking(false, sizes)); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch {
+	case opts.DeflateLevel > 0:
+		return dst.SetCompression(opts.Shuffle, true, opts.DeflateLevel)
+	case opts.DeflateLevel == 0:
+		shuffle, deflate, level, err := src.Compression()
+		if err != nil {
+			return err
+		}
+		if shuffle || deflate {
+			return keep(dst.SetCompression(shuffle, deflate, level))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+// +build cgo
+
+package netcdf_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+func TestCopyUnsupported(t *testing.T) {
+	src, err := netcdf.CreateFile(filepath.Join(t.TempDir(), "unsupported.nc"), netcdf.CLOBBER|netcdf.NETCDF4)
+	if err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	defer src.Close()
+	x, err := src.AddDim("x", 2)
+	if err != nil {
+		t.Fatalf("adding dimension failed: %v\n", err)
+	}
+	if _, err := src.AddVar("n", netcdf.INT, []netcdf.Dim{x}); err != nil {
+		t.Fatalf("adding variable failed: %v\n", err)
+	}
+	g, err := src.AddGroup("sub")
+	if err != nil {
+		t.Fatalf("adding group failed: %v\n", err)
+	}
+	if _, err := g.AddVar("name", netcdf.STRING, []netcdf.Dim{x}); err != nil {
+		t.Fatalf("adding variable failed: %v\n", err)
+	}
+	if err := src.EndDef(); err != nil {
+		t.Fatalf("leaving define mode failed: %v\n", err)
+	}
+
+	dst := netcdftest.New()
+	defer dst.Close()
+	err = netcdf.Copy(dst, src, nil)
+	if err == nil || !strings.Contains(err.Error(), `variable "sub/name"`) {
+		t.Errorf("Copy returned %v; expected an error about variable sub/name\n", err)
+	}
+	if n, err := dst.NDims(); err != nil || n != 0 {
+		t.Errorf("Copy added %d dimensions (%v); expected none\n", n, err)
+	}
+	if err := netcdf.Copy(netcdftest.New(), src, &netcdf.CopyOptions{Vars: []string{"n"}}); err != nil {
+		t.Errorf("Copy of variable n failed: %v\n", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+var copyFixture = netcdftest.Fixture{
+	Dims:  []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "x", Len: 3}},
+	Attrs: []netcdftest.Attr{{Name: "title", Value: "copy test"}},
+	Vars: []netcdftest.Var{
+		{Name: "time", Dims: []string{"time"}, Data: []float64{0, 1, 2, 3},
+			Attrs: []netcdftest.Attr{{Name: "units", Value: "days since 2020-01-01"}}},
+		{Name: "x", Dims: []string{"x"}, Data: []int16{10, 20, 30}},
+		{Name: "temp", Dims: []string{"time", "x"}, Data: []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}},
+		{Name: "scalar", Data: []int32{42}},
+	},
+}
+
+// buildCopySource returns a dataset holding copyFixture, and a group
+// named sub with a variable v of dimension x.
+func buildCopySource(t *testing.T) netcdf.Dataset {
+	src := netcdftest.MustBuild(t, copyFixture)
+	g, err := src.AddGroup("sub")
+	if err != nil {
+		t.Fatalf("AddGroup failed: %v\n", err)
+	}
+	d, err := src.Dim("x")
+	if err != nil {
+		t.Fatalf("Dim failed: %v\n", err)
+	}
+	v, err := g.AddVar("v", netcdf.INT, []netcdf.Dim{d})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := v.SetCompression(true, true, 4); err != nil {
+		t.Fatalf("SetCompression failed: %v\n", err)
+	}
+	if err := src.EndDef(); err != nil {
+		t.Fatalf("EndDef failed: %v\n", err)
+	}
+	if err := v.WriteInt32s([]int32{-1, -2, -3}); err != nil {
+		t.Fatalf("WriteInt32s failed: %v\n", err)
+	}
+	return src
+}
+
+func TestCopy(t *testing.T) {
+	src := buildCopySource(t)
+	defer src.Close()
+	dst := netcdftest.New()
+	defer dst.Close()
+	// Pieces of 2 values make records span several pieces.
+	opts := &netcdf.CopyOptions{Transfer: &netcdf.TransferOptions{PieceLen: 2}}
+	if err := netcdf.Copy(dst, src, opts); err != nil {
+		t.Fatalf("Copy failed: %v\n", err)
+	}
+	netcdftest.AssertEqual(t, dst, netcdftest.MustBuild(t, copyFixture))
+
+	g, err := dst.Group("sub")
+	if err != nil {
+		t.Fatalf("Group failed: %v\n", err)
+	}
+	v, err := g.Var("v")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	data, err := netcdf.GetInt32s(v)
+	if err != nil {
+		t.Fatalf("GetInt32s failed: %v\n", err)
+	}
+	if want := []int32{-1, -2, -3}; !reflect.DeepEqual(data, want) {
+		t.Errorf("values of group variable are %v; expected %v\n", data, want)
+	}
+	if shuffle, deflate, level, err := v.Compression(); err != nil || !shuffle || !deflate || level != 4 {
+		t.Errorf("Compression returned %v, %v, %v, %v; expected the source compression\n", shuffle, deflate, level, err)
+	}
+}
+
+func TestCopyOptions(t *testing.T) {
+	src := buildCopySource(t)
+	defer src.Close()
+	dst := netcdftest.New()
+	defer dst.Close()
+	opts := &netcdf.CopyOptions{
+		Vars:         []string{"temp", "sub/v"},
+		DeflateLevel: -1,
+		Chunks:       map[string]uint64{"time": 2},
+	}
+	if err := netcdf.Copy(dst, src, opts); err != nil {
+		t.Fatalf("Copy failed: %v\n", err)
+	}
+	if n, err := dst.NVars(); err != nil || n != 1 {
+		t.Errorf("NVars returned %v, %v; expected 1\n", n, err)
+	}
+	v, err := dst.Var("temp")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if contiguous, sizes, err := v.Chunking(); err != nil || contiguous || !reflect.DeepEqual(sizes, []uint64{2, 3}) {
+		t.Errorf("Chunking returned %v, %v, %v; expected chunks [2 3]\n", contiguous, sizes, err)
+	}
+	if n, err := v.Len(); err != nil || n != 12 {
+		t.Errorf("Len returned %v, %v; expected 12\n", n, err)
+	}
+	g, err := dst.Group("sub")
+	if err != nil {
+		t.Fatalf("Group failed: %v\n", err)
+	}
+	v, err = g.Var("v")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if _, deflate, _, err := v.Compression(); err != nil || deflate {
+		t.Errorf("Compression returned %v, %v; expected no compression\n", deflate, err)
+	}
+
+	dst2 := netcdftest.New()
+	defer dst2.Close()
+	if err := netcdf.Copy(dst2, src, &netcdf.CopyOptions{DeflateLevel: 5, Shuffle: true}); err != nil {
+		t.Fatalf("Copy failed: %v\n", err)
+	}
+	v, err = dst2.Var("x")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if shuffle, deflate, level, err := v.Compression(); err != nil || !shuffle || !deflate || level != 5 {
+		t.Errorf("Compression returned %v, %v, %v, %v; expected shuffle and level 5\n", shuffle, deflate, level, err)
+	}
+
+	for _, tc := range []struct {
+		opts *netcdf.CopyOptions
+		err  string
+	}{
+		{&netcdf.CopyOptions{Vars: []string{"v"}}, "variable \"v\" not found"},
+		{&netcdf.CopyOptions{DeflateLevel: 10}, "invalid deflate level 10"},
+	} {
+		dst := netcdftest.New()
+		err := netcdf.Copy(dst, src, tc.opts)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Copy(%+v) returned %v; expected error containing %q\n", tc.opts, err, tc.err)
+		}
+		dst.Close()
+	}
+}
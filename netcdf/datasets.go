@@ -4,85 +4,163 @@
 
 package netcdf
 
-// #cgo pkg-config: netcdf
-// #include <stdlib.h>
-// #include <netcdf.h>
-import "C"
+import "github.com/fhs/go-netcdf/netcdf/internal/driver"
 
-import (
-	"unsafe"
-)
-
-// Error represents an error returned by netCDF C library.
-type Error C.int
-
-func newError(n C.int) error {
-	if n == C.NC_NOERR {
-		return nil
-	}
-	return Error(n)
+// Dataset represents a netCDF dataset, or a group of a netCDF-4 dataset.
+// Copies of a Dataset refer to the same dataset.
+type Dataset struct {
+	h   *handle
+	grp driver.Dataset // driver of the group, or nil for the root group
 }
 
-// Error returns a string representation of Error e.
-func (e Error) Error() string {
-	return C.GoString(C.nc_strerror(C.int(e)))
+func init() {
+	driver.NewDataset = func(d driver.Dataset, path string) interface{} {
+		return newHandle(d, path)
+	}
 }
 
-// Dataset represents a netCDF dataset.
-type Dataset C.int
-
 // CreateFile creates a new netCDF dataset.
 // Mode is a bitwise-or of FileMode values.
 func CreateFile(path string, mode FileMode) (ds Dataset, err error) {
-	cpath := C.CString(path)
-	defer C.free(unsafe.Pointer(cpath))
-	var id C.int
-	err = newError(C.nc_create(cpath, C.int(mode), &id))
-	ds = Dataset(id)
+	d, err := createDriver(path, mode)
+	if err == nil {
+		ds = newHandle(d, path)
+	}
+	return
+}
+
+// CreateFileWithOptions is like CreateFile, but passes hints to the C
+// library about the file of a classic dataset, as nc__create does.
+// InitialSize is the size the file is given when it's created, and
+// bufferSize the size of the buffer used for I/O. A bufferSize of 0 lets
+// the library choose it. The hints are ignored for netCDF-4 datasets.
+func CreateFileWithOptions(path string, mode FileMode, initialSize, bufferSize uint64) (ds Dataset, err error) {
+	d, err := createDriverWithOptions(path, mode, initialSize, bufferSize)
+	if err == nil {
+		ds = newHandle(d, path)
+	}
 	return
 }
 
 // OpenFile opens an existing netCDF dataset file at path.
 // Mode is a bitwise-or of FileMode values.
 func OpenFile(path string, mode FileMode) (ds Dataset, err error) {
-	cpath := C.CString(path)
-	defer C.free(unsafe.Pointer(cpath))
-	var id C.int
-	err = newError(C.nc_open(cpath, C.int(mode), &id))
-	ds = Dataset(id)
+	d, err := openDriver(path, mode)
+	if err == nil {
+		ds = newHandle(d, path)
+	}
 	return
 }
 
-// Close closes an open netCDF dataset.
+// Close closes an open netCDF dataset. Closing a dataset that's
+// already closed does nothing.
 func (ds Dataset) Close() (err error) {
-	return newError(C.nc_close(C.int(ds)))
+	return ds.close(driver.Dataset.Close)
+}
+
+// CloseFunc closes ds like Close, but releases the dataset by calling f with
+// its netCDF ID instead of calling nc_close. It's meant for packages that
+// open datasets through other parts of the C library, such as package ncmem.
+// F is not called if ds is already closed.
+func (ds Dataset) CloseFunc(f func(id int) error) error {
+	return ds.close(func(d driver.Dataset) error {
+		return f(driverID(d))
+	})
 }
 
 // EndDef leaves define mode and enters data mode, so variable data
 // can be read or written. Calling this method is not required
 // for netCDF-4 files.
 func (ds Dataset) EndDef() (err error) {
-	return newError(C.nc_enddef(C.int(ds)))
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	return d.EndDef()
+}
+
+// EndDefWithOptions is like EndDef, but controls the layout of classic
+// datasets as nc__enddef of the C library does. HMinFree is the free
+// space left after the header, so attributes, dimensions and variables
+// can be added later without moving the data. VAlign is the alignment of
+// the start of the data of the fixed-size variables, which are followed
+// by vMinFree bytes of free space. RAlign is the alignment of the start
+// of the record variables. EndDef is the same as
+// EndDefWithOptions(0, 4, 0, 4). The options are ignored for netCDF-4
+// datasets.
+func (ds Dataset) EndDefWithOptions(hMinFree, vAlign, vMinFree, rAlign uint64) (err error) {
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	return d.EndDefWithOptions(hMinFree, vAlign, vMinFree, rAlign)
+}
+
+// Redef puts dataset ds back into define mode, so dimensions, variables
+// and attributes can be added to it. When a classic dataset leaves define
+// mode again, the data is moved if the header no longer fits before it,
+// which EndDefWithOptions can prevent by leaving free space. Calling this
+// method is not required for netCDF-4 files.
+func (ds Dataset) Redef() (err error) {
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	return d.Redef()
 }
 
 // NVars returns the number of variables defined for dataset f.
 func (ds Dataset) NVars() (n int, err error) {
-	var cn C.int
-	err = newError(C.nc_inq_nvars(C.int(ds), &cn))
-	n = int(cn)
-	return
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	return d.NVars()
 }
 
 // NAttrs returns the number of global attributes defined for dataset f.
 func (ds Dataset) NAttrs() (n int, err error) {
-	var cn C.int
-	err = newError(C.nc_inq_natts(C.int(ds), &cn))
-	n = int(cn)
-	return
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	return d.NAttrs(driver.Global)
+}
+
+// AddGroup adds a new group named name to dataset ds, and returns it.
+// Groups are only supported by netCDF-4 datasets. The group is part of
+// the same file as ds, so closing either closes both.
+func (ds Dataset) AddGroup(name string) (g Dataset, err error) {
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	grp, err := d.DefGrp(name)
+	if err != nil {
+		return
+	}
+	return Dataset{h: ds.h, grp: grp}, nil
+}
+
+// Group returns the group named name of dataset ds.
+func (ds Dataset) Group(name string) (g Dataset, err error) {
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	grp, err := d.Grp(name)
+	if err != nil {
+		return
+	}
+	return Dataset{h: ds.h, grp: grp}, nil
 }
 
-// Version returns a string identifying the version of the netCDF library,
-// and when it was built.
-func Version() string {
-	return C.GoString(C.nc_inq_libvers())
+// GroupNames returns the names of the groups of dataset ds, not including
+// their own groups.
+func (ds Dataset) GroupNames() (names []string, err error) {
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	return d.GrpNames()
 }
@@ -5,6 +5,7 @@
 package netcdf
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"reflect"
@@ -12,6 +13,14 @@ import (
 	"testing"
 )
 
+// skipWithoutC skips a test that needs the C library, which isn't
+// available when building without cgo.
+func skipWithoutC(tb testing.TB) {
+	if Version() == "" {
+		tb.Skip("the C library is not available without cgo")
+	}
+}
+
 type FileTest struct {
 	VarName  string
 	DimNames []string
@@ -164,6 +173,7 @@ func getFileTests() []FileTest {
 }
 
 func TestCreate(t *testing.T) {
+	skipWithoutC(t)
 	for _, ft := range getFileTests() {
 		f, err := ioutil.TempFile("", "netcdf_test")
 		if err != nil {
@@ -312,6 +322,7 @@ func TestError(t *testing.T) {
 }
 
 func TestEndDef(t *testing.T) {
+	skipWithoutC(t)
 	f, err := ioutil.TempFile("", "netcdf_test")
 	if err != nil {
 		t.Fatalf("creating temporary file failed: %v\n", err)
@@ -348,6 +359,7 @@ func TestEndDef(t *testing.T) {
 }
 
 func TestVersion(t *testing.T) {
+	skipWithoutC(t)
 	if ver := Version(); ver == "" {
 		t.Errorf("Bad Version %q\n", ver)
 	}
@@ -397,6 +409,39 @@ func TestValidUnravel(t *testing.T) {
 
 }
 
+func TestUnravelDoesntModifyShape(t *testing.T) {
+	shape := []uint64{4, 3, 2}
+	if _, err := UnravelIndex(17, shape); err != nil {
+		t.Fatalf("UnravelIndex failed: %v\n", err)
+	}
+	if want := []uint64{4, 3, 2}; !reflect.DeepEqual(shape, want) {
+		t.Errorf("UnravelIndex changed shape to %v\n", shape)
+	}
+}
+
+func TestRavel(t *testing.T) {
+	shape := []uint64{4, 3, 2}
+	for i := uint64(0); i < product(shape); i++ {
+		coord, err := UnravelIndex(i, shape)
+		if err != nil {
+			t.Fatalf("UnravelIndex(%v, %v) failed: %v\n", i, shape, err)
+		}
+		j, err := RavelIndex(coord, shape)
+		if err != nil || j != i {
+			t.Errorf("RavelIndex(%v, %v) is %v, %v; expected %v\n", coord, shape, j, err, i)
+		}
+	}
+	if _, err := UnravelIndex(24, shape); err == nil {
+		t.Errorf("UnravelIndex of index equal to the size succeeded\n")
+	}
+	if _, err := RavelIndex([]uint64{1, 3, 0}, shape); err == nil {
+		t.Errorf("RavelIndex of coordinates out of range succeeded\n")
+	}
+	if _, err := RavelIndex([]uint64{1, 2}, shape); err == nil {
+		t.Errorf("RavelIndex of too few coordinates succeeded\n")
+	}
+}
+
 func TestUnravelErrors(t *testing.T) {
 	type IdxTests struct {
 		idx      uint64
@@ -419,11 +464,12 @@ func TestUnravelErrors(t *testing.T) {
 	var tooBig = []IdxTests{
 		{241, []uint64{3, 5}, nil},
 		{221, []uint64{2, 3}, nil},
+		{15, []uint64{3, 5}, nil},
 	}
 
 	for _, test := range tooBig {
 		var _, err = UnravelIndex(test.idx, test.shape)
-		if !strings.Contains(err.Error(), "> size") {
+		if err == nil || !strings.Contains(err.Error(), ">= size") {
 			t.Errorf("Expected '0' error, got %v", err)
 		}
 	}
@@ -554,6 +600,7 @@ func testReadFileViaIdx(t *testing.T, filename string, ft *FileTest) {
 }
 
 func TestAt(t *testing.T) {
+	skipWithoutC(t)
 	for _, ft := range getFileTests() {
 		f, err := ioutil.TempFile("", "netcdf_test")
 		if err != nil {
@@ -692,6 +739,7 @@ func testReadFileViaSlice(t *testing.T, filename string, ft *FileTest) {
 }
 
 func TestSlice(t *testing.T) {
+	skipWithoutC(t)
 	for _, ft := range getFileTests() {
 		f, err := ioutil.TempFile("", "netcdf_test")
 		if err != nil {
@@ -830,6 +878,7 @@ func testReadFileViaStridedSlice(t *testing.T, filename string, ft *FileTest) {
 }
 
 func TestStridedSlice(t *testing.T) {
+	skipWithoutC(t)
 	for _, ft := range getFileTests() {
 		f, err := ioutil.TempFile("", "netcdf_test")
 		if err != nil {
@@ -844,6 +893,7 @@ func TestStridedSlice(t *testing.T) {
 }
 
 func TestCompression(t *testing.T) {
+	skipWithoutC(t)
 	f, err := ioutil.TempFile("", "netcdf_test")
 	if err != nil {
 		t.Fatalf("creating temporary file failed: %v\n", err)
@@ -903,3 +953,91 @@ func TestCompression(t *testing.T) {
 		}
 	}
 }
+
+// dataOffsets creates a classic file, leaving define mode with endDef,
+// with a variable holding known values. It then adds a global attribute
+// in define mode, and returns the offset of the values in the file
+// before and after that.
+func dataOffsets(t *testing.T, endDef func(ds Dataset) error) (before, after int) {
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	ds, err := CreateFileWithOptions(f.Name(), CLOBBER, 4096, 8192)
+	if err != nil {
+		t.Fatalf("CreateFileWithOptions failed: %v\n", err)
+	}
+	x, err := ds.AddDim("x", 3)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	v, err := ds.AddVar("gopher", INT, []Dim{x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := endDef(ds); err != nil {
+		t.Fatalf("leaving define mode failed: %v\n", err)
+	}
+	if err := v.WriteInt32s([]int32{0x0a0b0c0d, 0x01020304, 0x05060708}); err != nil {
+		t.Fatalf("WriteInt32s failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+	values := []byte{0xa, 0xb, 0xc, 0xd, 1, 2, 3, 4, 5, 6, 7, 8}
+	offset := func() int {
+		b, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			t.Fatalf("reading file failed: %v\n", err)
+		}
+		i := bytes.Index(b, values)
+		if i < 0 {
+			t.Fatalf("values not found in file\n")
+		}
+		return i
+	}
+	before = offset()
+
+	ds, err = OpenFile(f.Name(), WRITE)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v\n", err)
+	}
+	if err := ds.Redef(); err != nil {
+		t.Fatalf("Redef failed: %v\n", err)
+	}
+	if err := ds.Redef(); err != EINDEFINE {
+		t.Errorf("Redef in define mode returned %v; expected %v\n", err, EINDEFINE)
+	}
+	if err := ds.Attr("history").WriteBytes(bytes.Repeat([]byte("gopher "), 50)); err != nil {
+		t.Fatalf("WriteBytes failed: %v\n", err)
+	}
+	if err := ds.EndDef(); err != nil {
+		t.Fatalf("EndDef failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+	return before, offset()
+}
+
+func TestEndDefWithOptions(t *testing.T) {
+	skipWithoutC(t)
+	// Without free space after the header, the data is moved.
+	before, after := dataOffsets(t, Dataset.EndDef)
+	if after <= before {
+		t.Errorf("data moved from offset %d to %d; expected it to move further\n", before, after)
+	}
+
+	before, after = dataOffsets(t, func(ds Dataset) error {
+		return ds.EndDefWithOptions(1024, 512, 0, 4)
+	})
+	if before != after {
+		t.Errorf("data moved from offset %d to %d\n", before, after)
+	}
+	if before%512 != 0 || before < 1024 {
+		t.Errorf("data is at offset %d; expected it aligned to 512 after 1024 bytes of free space\n", before)
+	}
+}
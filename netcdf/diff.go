@@ -0,0 +1,600 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// DiffOptions controls Diff. A nil *DiffOptions compares values exactly.
+//
+// Two values a and b are equal if a == b, if |a-b| <= AbsTol, or if
+// |a-b| <= RelTol * max(|a|, |b|). Infinities are only equal to themselves.
+// NaN values are only equal to other NaN values, and only if NaNEqual is
+// set. Integer values are compared without converting them to float64, so
+// large INT64 and UINT64 values that a float64 can't tell apart still
+// differ when the tolerances are 0.
+type DiffOptions struct {
+	AbsTol   float64
+	RelTol   float64
+	NaNEqual bool
+
+	// Transfer controls how the data is read, in pieces of at most
+	// Transfer.PieceLen values from each dataset.
+	Transfer *TransferOptions
+}
+
+// equal reports whether a and b are equal under o.
+func (o *DiffOptions) equal(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return o.NaNEqual && math.IsNaN(a) && math.IsNaN(b)
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return false
+	}
+	d := math.Abs(a - b)
+	return d <= o.AbsTol || d <= o.RelTol*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// ChangeKind is the kind of a Change.
+type ChangeKind int
+
+const (
+	Added    ChangeKind = iota // only in the second dataset
+	Removed                    // only in the first dataset
+	Modified                   // in both datasets, but different
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a structural difference between two datasets.
+type Change struct {
+	Kind ChangeKind
+
+	// Object is the kind of object that changed: "dimension", "variable",
+	// "attribute" or "group".
+	Object string
+
+	// Name is the path of the object, such as "forecast/temp" for a
+	// variable of group forecast. Attributes are named by the path of
+	// their variable, a colon and their name, such as "temp:units", or
+	// ":title" for global attributes.
+	Name string
+
+	// A and B describe the object in the first and second dataset, or are
+	// empty if it's not there.
+	A, B string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("added %s %s: %s", c.Object, c.Name, c.B)
+	case Removed:
+		return fmt.Sprintf("removed %s %s: %s", c.Object, c.Name, c.A)
+	}
+	return fmt.Sprintf("modified %s %s: %s -> %s", c.Object, c.Name, c.A, c.B)
+}
+
+// DataDiff is the difference between the values of a variable in two
+// datasets.
+type DataDiff struct {
+	Var   string   // path of the variable
+	Count uint64   // number of values that differ
+	First []uint64 // index of the first value that differs
+
+	// MaxAbs and MaxRel are the maximum absolute and relative differences
+	// between values, not counting NaN values. The relative difference of
+	// a and b is |a-b| / max(|a|, |b|), which is infinite if a or b is.
+	MaxAbs, MaxRel float64
+}
+
+func (d DataDiff) String() string {
+	return fmt.Sprintf("variable %s: %d values differ, first at %v, max abs diff %g, max rel diff %g",
+		d.Var, d.Count, d.First, d.MaxAbs, d.MaxRel)
+}
+
+// DiffReport lists the differences between two datasets.
+type DiffReport struct {
+	Changes []Change
+	Data    []DataDiff // variables whose values differ
+
+	// Skipped lists the paths of variables whose values weren't compared,
+	// because they hold strings or values of user-defined types.
+	Skipped []string
+}
+
+// Equal reports whether r lists no differences. Skipped variables aren't
+// differences.
+func (r *DiffReport) Equal() bool {
+	return len(r.Changes) == 0 && len(r.Data) == 0
+}
+
+// String returns the differences, one per line.
+func (r *DiffReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Changes {
+		fmt.Fprintln(&b, c)
+	}
+	for _, d := range r.Data {
+		fmt.Fprintln(&b, d)
+	}
+	for _, name := range r.Skipped {
+		fmt.Fprintf(&b, "variable %s: values not compared\n", name)
+	}
+	return b.String()
+}
+
+// Diff compares datasets a and b, including their groups, and returns
+// their differences. Dimensions, variables and attributes are compared by
+// name. The values of variables that are in both datasets with the same
+// shape are compared as numbers, as described for DiffOptions, even if
+// their types differ. Variables of other types are listed in the Skipped
+// field of the report. Opts may be nil to compare values exactly.
+func Diff(a, b Dataset, opts *DiffOptions) (*DiffReport, error) {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	r := &DiffReport{}
+	if err := r.diffGroup(a, b, "", opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *DiffReport) add(kind ChangeKind, object, name, a, b string) {
+	r.Changes = append(r.Changes, Change{Kind: kind, Object: object, Name: name, A: a, B: b})
+}
+
+// diffNames calls f for each name in as or bs, with whether it's in each
+// of them, in the order of as followed by the names only in bs.
+func diffNames(as, bs []string, f func(name string, inA, inB bool) error) error {
+	inB := make(map[string]bool, len(bs))
+	for _, name := range bs {
+		inB[name] = true
+	}
+	inA := make(map[string]bool, len(as))
+	for _, name := range as {
+		inA[name] = true
+		if err := f(name, true, inB[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range bs {
+		if !inA[name] {
+			if err := f(name, false, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// diffGroup compares groups a and b, whose path is prefix.
+func (r *DiffReport) diffGroup(a, b Dataset, prefix string, opts *DiffOptions) error {
+	da, err := describeDims(a)
+	if err != nil {
+		return err
+	}
+	db, err := describeDims(b)
+	if err != nil {
+		return err
+	}
+	err = diffNames(da.names, db.names, func(name string, inA, inB bool) error {
+		p := path.Join(prefix, name)
+		switch {
+		case !inB:
+			r.add(Removed, "dimension", p, da.desc[name], "")
+		case !inA:
+			r.add(Added, "dimension", p, "", db.desc[name])
+		case da.desc[name] != db.desc[name]:
+			r.add(Modified, "dimension", p, da.desc[name], db.desc[name])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.diffAttrs(a.globals(), b.globals(), prefix+":"); err != nil {
+		return err
+	}
+
+	va, err := varNames(a)
+	if err != nil {
+		return err
+	}
+	vb, err := varNames(b)
+	if err != nil {
+		return err
+	}
+	err = diffNames(va, vb, func(name string, inA, inB bool) error {
+		p := path.Join(prefix, name)
+		var x, y Var
+		var sx, sy string
+		if inA {
+			if x, err = a.Var(name); err != nil {
+				return err
+			}
+			if sx, err = describeVar(x); err != nil {
+				return err
+			}
+		}
+		if inB {
+			if y, err = b.Var(name); err != nil {
+				return err
+			}
+			if sy, err = describeVar(y); err != nil {
+				return err
+			}
+		}
+		switch {
+		case !inB:
+			r.add(Removed, "variable", p, sx, "")
+			return nil
+		case !inA:
+			r.add(Added, "variable", p, "", sy)
+			return nil
+		case sx != sy:
+			r.add(Modified, "variable", p, sx, sy)
+		}
+		if err := r.diffAttrs(x, y, p+":"); err != nil {
+			return err
+		}
+		return r.diffData(x, y, p, opts)
+	})
+	if err != nil {
+		return err
+	}
+
+	ga, err := a.GroupNames()
+	if err != nil {
+		return err
+	}
+	gb, err := b.GroupNames()
+	if err != nil {
+		return err
+	}
+	return diffNames(ga, gb, func(name string, inA, inB bool) error {
+		p := path.Join(prefix, name)
+		switch {
+		case !inB:
+			r.add(Removed, "group", p, "group", "")
+			return nil
+		case !inA:
+			r.add(Added, "group", p, "", "group")
+			return nil
+		}
+		x, err := a.Group(name)
+		if err != nil {
+			return err
+		}
+		y, err := b.Group(name)
+		if err != nil {
+			return err
+		}
+		return r.diffGroup(x, y, p, opts)
+	})
+}
+
+type descs struct {
+	names []string
+	desc  map[string]string
+}
+
+// describeDims returns the names of the dimensions of ds, and their
+// descriptions.
+func describeDims(ds Dataset) (descs, error) {
+	dims, err := ds.Dims()
+	if err != nil {
+		return descs{}, err
+	}
+	unlimited, err := ds.UnlimitedDims()
+	if err != nil {
+		return descs{}, err
+	}
+	dd := descs{desc: make(map[string]string)}
+	for _, d := range dims {
+		name, err := d.Name()
+		if err != nil {
+			return descs{}, err
+		}
+		n, err := d.Len()
+		if err != nil {
+			return descs{}, err
+		}
+		dd.names = append(dd.names, name)
+		dd.desc[name] = fmt.Sprint(n)
+		if containsDim(unlimited, d) {
+			dd.desc[name] += " (unlimited)"
+		}
+	}
+	return dd, nil
+}
+
+func varNames(ds Dataset) ([]string, error) {
+	n, err := ds.NVars()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, n)
+	for i := range names {
+		if names[i], err = ds.VarN(i).Name(); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// describeVar returns the type and the dimensions of v, as in
+// "FLOAT(time=4, x=3)".
+func describeVar(v Var) (string, error) {
+	t, err := v.Type()
+	if err != nil {
+		return "", err
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return "", err
+	}
+	s := make([]string, len(dims))
+	for i, d := range dims {
+		name, err := d.Name()
+		if err != nil {
+			return "", err
+		}
+		n, err := d.Len()
+		if err != nil {
+			return "", err
+		}
+		s[i] = fmt.Sprintf("%s=%d", name, n)
+	}
+	return fmt.Sprintf("%v(%s)", t, strings.Join(s, ", ")), nil
+}
+
+// diffAttrs compares the attributes of variables a and b, whose names
+// start with prefix.
+func (r *DiffReport) diffAttrs(a, b Var, prefix string) error {
+	da, err := describeAttrs(a)
+	if err != nil {
+		return err
+	}
+	db, err := describeAttrs(b)
+	if err != nil {
+		return err
+	}
+	return diffNames(da.names, db.names, func(name string, inA, inB bool) error {
+		p := prefix + name
+		switch {
+		case !inB:
+			r.add(Removed, "attribute", p, da.desc[name], "")
+		case !inA:
+			r.add(Added, "attribute", p, "", db.desc[name])
+		case da.desc[name] != db.desc[name]:
+			r.add(Modified, "attribute", p, da.desc[name], db.desc[name])
+		}
+		return nil
+	})
+}
+
+// describeAttrs returns the names of the attributes of v, and their types
+// and values.
+func describeAttrs(v Var) (descs, error) {
+	n, err := v.NAttrs()
+	if err != nil {
+		return descs{}, err
+	}
+	dd := descs{desc: make(map[string]string)}
+	for i := 0; i < n; i++ {
+		a, err := v.AttrN(i)
+		if err != nil {
+			return descs{}, err
+		}
+		t, val, err := a.read()
+		if err != nil {
+			return descs{}, err
+		}
+		dd.names = append(dd.names, a.Name())
+		if t == CHAR {
+			dd.desc[a.Name()] = fmt.Sprintf("%q", val)
+		} else {
+			dd.desc[a.Name()] = fmt.Sprintf("%v %v", t, val)
+		}
+	}
+	return dd, nil
+}
+
+// diffData compares the values of variables a and b, whose path is name,
+// if they have the same shape.
+func (r *DiffReport) diffData(a, b Var, name string, opts *DiffOptions) error {
+	shape, err := a.LenDims()
+	if err != nil {
+		return err
+	}
+	if s, err := b.LenDims(); err != nil {
+		return err
+	} else if !reflect.DeepEqual(s, shape) {
+		return nil
+	}
+	ta, err := a.Type()
+	if err != nil {
+		return err
+	}
+	tb, err := b.Type()
+	if err != nil {
+		return err
+	}
+	if !isNumber(ta) || !isNumber(tb) {
+		r.Skipped = append(r.Skipped, name)
+		return nil
+	}
+	pieceLen := opts.Transfer.pieceLen()
+	if l := product(shape); l < pieceLen {
+		pieceLen = l
+	}
+	bufA, err := makeSlice(ta, pieceLen)
+	if err != nil {
+		return err
+	}
+	bufB, err := makeSlice(tb, pieceLen)
+	if err != nil {
+		return err
+	}
+
+	d := DataDiff{Var: name}
+	exact := !isFloat(ta) && !isFloat(tb)
+	var xa, xb []float64
+	var ia, ib []exactInt
+	s := slab{make([]uint64, len(shape)), shape}
+	err = s.pieces(pieceLen, func(off uint64, p slab) error {
+		if err := transferSlice(a, ta, bufA, false, 0, p); err != nil {
+			return err
+		}
+		if err := transferSlice(b, tb, bufB, false, 0, p); err != nil {
+			return err
+		}
+		n := int(product(p.count))
+		differ := func(i int) {
+			if d.Count == 0 {
+				d.First, _ = UnravelIndex(off+uint64(i), shape)
+			}
+			d.Count++
+		}
+		if exact {
+			ia = appendExactInts(ia[:0], reflect.ValueOf(bufA).Slice(0, n).Interface())
+			ib = appendExactInts(ib[:0], reflect.ValueOf(bufB).Slice(0, n).Interface())
+			for i := range ia {
+				x, y := ia[i], ib[i]
+				if x == y {
+					continue
+				}
+				// Distinct integers are at least 1 apart, so they
+				// differ unless a tolerance is set.
+				diff := x.dist(y)
+				rel := diff / math.Max(x.abs(), y.abs())
+				if diff > opts.AbsTol && rel > opts.RelTol {
+					differ(i)
+				}
+				d.MaxAbs = math.Max(d.MaxAbs, diff)
+				d.MaxRel = math.Max(d.MaxRel, rel)
+			}
+			return nil
+		}
+		xa = appendFloat64s(xa[:0], reflect.ValueOf(bufA).Slice(0, n).Interface())
+		xb = appendFloat64s(xb[:0], reflect.ValueOf(bufB).Slice(0, n).Interface())
+		for i := range xa {
+			x, y := xa[i], xb[i]
+			if !opts.equal(x, y) {
+				differ(i)
+			}
+			if math.IsNaN(x) || math.IsNaN(y) || x == y {
+				continue
+			}
+			diff, rel := math.Abs(x-y), math.Inf(1)
+			if !math.IsInf(diff, 0) {
+				rel = diff / math.Max(math.Abs(x), math.Abs(y))
+			}
+			d.MaxAbs = math.Max(d.MaxAbs, diff)
+			d.MaxRel = math.Max(d.MaxRel, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("variable %q: %v", name, err)
+	}
+	if d.Count > 0 {
+		r.Data = append(r.Data, d)
+	}
+	return nil
+}
+
+func isFloat(t Type) bool {
+	return t == FLOAT || t == DOUBLE
+}
+
+// isNumber reports whether values of type t are numbers, which includes
+// CHAR values as bytes.
+func isNumber(t Type) bool {
+	return t >= BYTE && t <= DOUBLE || t >= UBYTE && t <= UINT64
+}
+
+// exactInt is an integer of any type, stored as its sign and the bits of
+// its two's complement, so integers of different types can be compared.
+type exactInt struct {
+	neg  bool
+	bits uint64
+}
+
+// abs returns the absolute value of x.
+func (x exactInt) abs() float64 {
+	if x.neg {
+		return float64(-x.bits)
+	}
+	return float64(x.bits)
+}
+
+// dist returns |x-y|.
+func (x exactInt) dist(y exactInt) float64 {
+	if x.neg != y.neg {
+		return x.abs() + y.abs()
+	}
+	if x.bits < y.bits {
+		return float64(y.bits - x.bits)
+	}
+	return float64(x.bits - y.bits)
+}
+
+// appendExactInts appends the values of data, a slice of integers, to x.
+func appendExactInts(x []exactInt, data interface{}) []exactInt {
+	switch d := data.(type) {
+	case []uint64:
+		return appendUnsignedInts(x, d)
+	case []int64:
+		return appendSignedInts(x, d)
+	case []uint32:
+		return appendUnsignedInts(x, d)
+	case []int32:
+		return appendSignedInts(x, d)
+	case []uint16:
+		return appendUnsignedInts(x, d)
+	case []int16:
+		return appendSignedInts(x, d)
+	case []uint8:
+		return appendUnsignedInts(x, d)
+	case []int8:
+		return appendSignedInts(x, d)
+	}
+	panic(fmt.Sprintf("netcdf: unsupported data type %T", data))
+}
+
+func appendSignedInts[T int8 | int16 | int32 | int64](x []exactInt, data []T) []exactInt {
+	for _, val := range data {
+		x = append(x, exactInt{val < 0, uint64(val)})
+	}
+	return x
+}
+
+func appendUnsignedInts[T uint8 | uint16 | uint32 | uint64](x []exactInt, data []T) []exactInt {
+	for _, val := range data {
+		x = append(x, exactInt{false, uint64(val)})
+	}
+	return x
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+// +build cgo
+
+package netcdf_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+func TestDiffSkipped(t *testing.T) {
+	ds, err := netcdf.CreateFile(filepath.Join(t.TempDir(), "skipped.nc"), netcdf.CLOBBER|netcdf.NETCDF4)
+	if err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	defer ds.Close()
+	x, err := ds.AddDim("x", 2)
+	if err != nil {
+		t.Fatalf("adding dimension failed: %v\n", err)
+	}
+	if _, err := ds.AddVar("name", netcdf.STRING, []netcdf.Dim{x}); err != nil {
+		t.Fatalf("adding variable failed: %v\n", err)
+	}
+	if _, err := ds.AddVar("n", netcdf.INT, []netcdf.Dim{x}); err != nil {
+		t.Fatalf("adding variable failed: %v\n", err)
+	}
+	if err := ds.EndDef(); err != nil {
+		t.Fatalf("leaving define mode failed: %v\n", err)
+	}
+
+	r, err := netcdf.Diff(ds, ds, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v\n", err)
+	}
+	if !r.Equal() || !reflect.DeepEqual(r.Skipped, []string{"name"}) {
+		t.Errorf("Diff returned %v with skipped variables %v; expected only name skipped\n", r, r.Skipped)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+func diffFixture() netcdftest.Fixture {
+	return netcdftest.Fixture{
+		Dims:  []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "x", Len: 3}},
+		Attrs: []netcdftest.Attr{{Name: "title", Value: "model run"}},
+		Vars: []netcdftest.Var{
+			{Name: "time", Dims: []string{"time"}, Data: []float64{0, 1}},
+			{Name: "temp", Dims: []string{"time", "x"}, Data: []float64{1, 2, 3, 4, math.NaN(), 6},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "K"}}},
+			{Name: "count", Dims: []string{"x"}, Data: []int32{1, 2, 3}},
+		},
+	}
+}
+
+func TestDiffEqual(t *testing.T) {
+	a := netcdftest.MustBuild(t, diffFixture())
+	defer a.Close()
+	b := netcdftest.MustBuild(t, diffFixture())
+	defer b.Close()
+
+	r, err := netcdf.Diff(a, b, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v\n", err)
+	}
+	want := []netcdf.DataDiff{{Var: "temp", Count: 1, First: []uint64{1, 1}}}
+	if len(r.Changes) != 0 || !reflect.DeepEqual(r.Data, want) {
+		t.Errorf("Diff returned %v; expected NaN to differ\n", r)
+	}
+	r, err = netcdf.Diff(a, b, &netcdf.DiffOptions{NaNEqual: true})
+	if err != nil {
+		t.Fatalf("Diff failed: %v\n", err)
+	}
+	if !r.Equal() {
+		t.Errorf("Diff returned %v; expected no differences\n", r)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := netcdftest.MustBuild(t, diffFixture())
+	defer a.Close()
+	f := diffFixture()
+	f.Dims = append(f.Dims, netcdftest.Dim{Name: "y", Len: 2})
+	f.Attrs[0].Value = "new model run"
+	f.Vars[0].Data = []float64{0, 1, 2}
+	f.Vars[1].Dims = []string{"time", "x"}
+	f.Vars[1].Data = []float64{1, 2, 3, 4, math.NaN(), 6, 7, 8, 9}
+	f.Vars[1].Attrs = nil
+	f.Vars[2].Data = []float32{1, 2.001, 3.1}
+	f.Vars = append(f.Vars, netcdftest.Var{Name: "extra", Dims: []string{"y"}, Data: []int8{1, 2}})
+	b := netcdftest.MustBuild(t, f)
+	defer b.Close()
+
+	r, err := netcdf.Diff(a, b, &netcdf.DiffOptions{AbsTol: 0.01, NaNEqual: true})
+	if err != nil {
+		t.Fatalf("Diff failed: %v\n", err)
+	}
+	changes := []netcdf.Change{
+		{Kind: netcdf.Modified, Object: "dimension", Name: "time", A: "2 (unlimited)", B: "3 (unlimited)"},
+		{Kind: netcdf.Added, Object: "dimension", Name: "y", B: "2"},
+		{Kind: netcdf.Modified, Object: "attribute", Name: ":title", A: `"model run"`, B: `"new model run"`},
+		{Kind: netcdf.Modified, Object: "variable", Name: "time", A: "DOUBLE(time=2)", B: "DOUBLE(time=3)"},
+		{Kind: netcdf.Modified, Object: "variable", Name: "temp", A: "DOUBLE(time=2, x=3)", B: "DOUBLE(time=3, x=3)"},
+		{Kind: netcdf.Removed, Object: "attribute", Name: "temp:units", A: `"K"`},
+		{Kind: netcdf.Modified, Object: "variable", Name: "count", A: "INT(x=3)", B: "FLOAT(x=3)"},
+		{Kind: netcdf.Added, Object: "variable", Name: "extra", B: "BYTE(y=2)"},
+	}
+	if !reflect.DeepEqual(r.Changes, changes) {
+		t.Errorf("Diff returned changes\n%v\nexpected\n%v\n", r.Changes, changes)
+	}
+	if len(r.Data) != 1 {
+		t.Fatalf("Diff returned data differences %v; expected 1\n", r.Data)
+	}
+	d := r.Data[0]
+	if d.Var != "count" || d.Count != 1 || !reflect.DeepEqual(d.First, []uint64{2}) ||
+		math.Abs(d.MaxAbs-0.1) > 1e-6 || math.Abs(d.MaxRel-0.1/3.1) > 1e-6 {
+		t.Errorf("Diff returned %v for variable count\n", d)
+	}
+}
+
+func TestDiffTolerance(t *testing.T) {
+	build := func(x ...float64) netcdf.Dataset {
+		return netcdftest.MustBuild(t, netcdftest.Fixture{
+			Dims: []netcdftest.Dim{{Name: "x", Len: uint64(len(x))}},
+			Vars: []netcdftest.Var{{Name: "v", Dims: []string{"x"}, Data: x}},
+		})
+	}
+	inf := math.Inf(1)
+	a := build(100, 0, inf, 1, math.NaN())
+	defer a.Close()
+	b := build(101, 0.5, inf, inf, 1)
+	defer b.Close()
+	for _, tc := range []struct {
+		opts  netcdf.DiffOptions
+		count uint64
+		first []uint64
+	}{
+		{netcdf.DiffOptions{}, 4, []uint64{0}},
+		{netcdf.DiffOptions{AbsTol: 1}, 2, []uint64{3}},
+		{netcdf.DiffOptions{RelTol: 0.01}, 3, []uint64{1}},
+		{netcdf.DiffOptions{AbsTol: 0.5, RelTol: 0.01, NaNEqual: true}, 2, []uint64{3}},
+	} {
+		r, err := netcdf.Diff(a, b, &tc.opts)
+		if err != nil {
+			t.Fatalf("Diff failed: %v\n", err)
+		}
+		if len(r.Data) != 1 || r.Data[0].Count != tc.count || !reflect.DeepEqual(r.Data[0].First, tc.first) {
+			t.Errorf("Diff with options %+v returned %v; expected %d differences from %v\n", tc.opts, r.Data, tc.count, tc.first)
+			continue
+		}
+		if d := r.Data[0]; d.MaxAbs != inf || d.MaxRel != inf {
+			t.Errorf("Diff returned max differences %v and %v; expected infinity\n", d.MaxAbs, d.MaxRel)
+		}
+	}
+}
+
+func TestDiffExactInts(t *testing.T) {
+	build := func(i int64, u uint64) netcdf.Dataset {
+		return netcdftest.MustBuild(t, netcdftest.Fixture{
+			Dims: []netcdftest.Dim{{Name: "x", Len: 2}},
+			Vars: []netcdftest.Var{
+				{Name: "i", Dims: []string{"x"}, Data: []int64{-1 << 60, i}},
+				{Name: "u", Dims: []string{"x"}, Data: []uint64{math.MaxUint64, u}},
+			},
+		})
+	}
+	a := build(1<<60, 1<<60)
+	defer a.Close()
+	b := build(1<<60+1, 1<<60+1)
+	defer b.Close()
+
+	// The values differ by 1, which float64 can't tell apart.
+	r, err := netcdf.Diff(a, b, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v\n", err)
+	}
+	want := []netcdf.DataDiff{
+		{Var: "i", Count: 1, First: []uint64{1}, MaxAbs: 1, MaxRel: 1.0 / (1 << 60)},
+		{Var: "u", Count: 1, First: []uint64{1}, MaxAbs: 1, MaxRel: 1.0 / (1 << 60)},
+	}
+	if !reflect.DeepEqual(r.Data, want) {
+		t.Errorf("Diff returned %v; expected %v\n", r.Data, want)
+	}
+	for _, tc := range []struct {
+		opts  netcdf.DiffOptions
+		count uint64
+	}{
+		{netcdf.DiffOptions{AbsTol: 0.5}, 1},
+		{netcdf.DiffOptions{AbsTol: 1}, 0},
+		{netcdf.DiffOptions{RelTol: 0.5 / (1 << 60)}, 1},
+		{netcdf.DiffOptions{RelTol: 1e-15}, 0},
+	} {
+		r, err := netcdf.Diff(a, b, &tc.opts)
+		if err != nil {
+			t.Fatalf("Diff failed: %v\n", err)
+		}
+		for _, d := range r.Data {
+			if d.Count != tc.count {
+				t.Errorf("Diff with options %+v returned %v; expected %d differences\n", tc.opts, d, tc.count)
+			}
+		}
+		if tc.count == 0 && !r.Equal() {
+			t.Errorf("Diff with options %+v returned %v; expected no differences\n", tc.opts, r)
+		}
+	}
+	r, err = netcdf.Diff(a, a, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v\n", err)
+	}
+	if !r.Equal() {
+		t.Errorf("Diff returned %v; expected no differences\n", r)
+	}
+}
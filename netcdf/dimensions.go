@@ -4,59 +4,104 @@
 
 package netcdf
 
-// #include <stdlib.h>
-// #include <netcdf.h>
-import "C"
-
-import (
-	"unsafe"
-)
-
 // Dim represents a dimension.
 type Dim struct {
 	ds Dataset
-	id C.int
+	id int
 }
 
 // Name returns the name of dimension d.
 func (d Dim) Name() (name string, err error) {
-	buf := C.CString(string(make([]byte, C.NC_MAX_NAME+1)))
-	defer C.free(unsafe.Pointer(buf))
-	err = newError(C.nc_inq_dimname(C.int(d.ds), d.id, buf))
-	name = C.GoString(buf)
-	return
+	drv, err := d.ds.driver()
+	if err != nil {
+		return
+	}
+	return drv.DimName(d.id)
 }
 
 // Len returns the length of dimension d.
 func (d Dim) Len() (n uint64, err error) {
-	var len C.size_t
-	err = newError(C.nc_inq_dimlen(C.int(d.ds), d.id, &len))
-	n = uint64(len)
-	return
+	drv, err := d.ds.driver()
+	if err != nil {
+		return
+	}
+	return drv.DimLen(d.id)
 }
 
 // AddDim adds a new dimension named name of length len.
 // The new dimension d is returned.
 func (ds Dataset) AddDim(name string, len uint64) (d Dim, err error) {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	var dimid C.int
-	err = newError(C.nc_def_dim(C.int(ds), cname, C.size_t(len), &dimid))
-	d = Dim{ds, dimid}
+	drv, err := ds.driver()
+	if err != nil {
+		return
+	}
+	id, err := drv.DefDim(name, len)
+	d = Dim{ds, id}
 	return
 }
 
 // Dim returns the Dim for the dimension named name.
 func (ds Dataset) Dim(name string) (d Dim, err error) {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	var id C.int
-	err = newError(C.nc_inq_dimid(C.int(ds), cname, &id))
+	drv, err := ds.driver()
+	if err != nil {
+		return
+	}
+	id, err := drv.DimID(name)
 	d = Dim{ds, id}
 	return
 }
 
+// NDims returns the number of dimensions defined for dataset ds.
+func (ds Dataset) NDims() (n int, err error) {
+	drv, err := ds.driver()
+	if err != nil {
+		return
+	}
+	return drv.NDims()
+}
+
+// DimN returns the dimension in dataset ds with ID id.
+func (ds Dataset) DimN(id int) Dim {
+	return Dim{ds, id}
+}
+
+// Dims returns the dimensions defined in dataset ds. For groups, it
+// doesn't include the dimensions of their parents, which they can use too.
+func (ds Dataset) Dims() (dims []Dim, err error) {
+	drv, err := ds.driver()
+	if err != nil {
+		return
+	}
+	ids, err := drv.DimIDs()
+	if err != nil || len(ids) == 0 {
+		return
+	}
+	dims = make([]Dim, len(ids))
+	for i, id := range ids {
+		dims[i] = Dim{ds, id}
+	}
+	return
+}
+
+// UnlimitedDims returns the unlimited dimensions of dataset ds.
+// Unlimited dimensions are created by AddDim with length 0.
+func (ds Dataset) UnlimitedDims() (dims []Dim, err error) {
+	drv, err := ds.driver()
+	if err != nil {
+		return
+	}
+	ids, err := drv.UnlimitedDims()
+	if err != nil || len(ids) == 0 {
+		return
+	}
+	dims = make([]Dim, len(ids))
+	for i, id := range ids {
+		dims[i] = Dim{ds, id}
+	}
+	return
+}
+
 // ID returns the id of the dimension.
 func (dim Dim) ID() int {
-	return int(dim.id)
+	return dim.id
 }
@@ -0,0 +1,42 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+// Error represents an error returned by netCDF C library.
+type Error int32
+
+// Error returns a string representation of Error e.
+func (e Error) Error() string {
+	return strerror(e)
+}
+
+// Errors returned by the C library, and by other drivers. The values are
+// those of the C library.
+const (
+	EBADID       Error = -33  // not a netCDF ID
+	EEXIST       Error = -35  // file exists and NOCLOBBER was given
+	EINVAL       Error = -36  // invalid argument
+	EPERM        Error = -37  // write to read only
+	ENOTINDEFINE Error = -38  // operation not allowed in data mode
+	EINDEFINE    Error = -39  // operation not allowed in define mode
+	EINVALCOORDS Error = -40  // index exceeds dimension bound
+	ENAMEINUSE   Error = -42  // string match to name in use
+	ENOTATT      Error = -43  // attribute not found
+	EBADTYPE     Error = -45  // not a valid data type
+	EBADDIM      Error = -46  // invalid dimension ID or name
+	EUNLIMPOS    Error = -47  // unlimited dimension in the wrong index
+	ENOTVAR      Error = -49  // variable not found
+	ENOTNC       Error = -51  // not a netCDF file
+	EUNLIMIT     Error = -54  // unlimited dimension already in use
+	ECHAR        Error = -56  // attempt to convert between text and numbers
+	EEDGE        Error = -57  // start+count exceeds dimension bound
+	ESTRIDE      Error = -58  // illegal stride
+	EBADNAME     Error = -59  // name contains illegal characters
+	ERANGE       Error = -60  // numeric conversion not representable
+	EVARSIZE     Error = -62  // variable sizes violate format constraints
+	EDIMSIZE     Error = -63  // invalid dimension size
+	ENOTNC4      Error = -111 // netCDF-4 operation on a netCDF-3 file
+	ENOGRP       Error = -125 // group not found
+)
@@ -0,0 +1,130 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/fhs/go-netcdf/netcdf/internal/lock"
+)
+
+// LibraryFeatures describes the netCDF C library linked into the program,
+// as returned by Features. Without cgo, it's the zero value.
+type LibraryFeatures struct {
+	// Version is the version of the library, such as "4.9.2", and Major,
+	// Minor and Patch are its numbers.
+	Version             string
+	Major, Minor, Patch int
+
+	NetCDF4  bool // netCDF-4 files can be created, through HDF5
+	CDF5     bool // CDF-5 (64-bit data) files can be created
+	DAP2     bool // OPeNDAP servers can be read with DAP2
+	DAP4     bool // OPeNDAP servers can be read with DAP4
+	NCZarr   bool // NCZarr stores in local directories work, as reported by HasNCZarr
+	Szip     bool // szip compression can be written
+	Zstd     bool // zstandard compression is available
+	Parallel bool // parallel I/O through MPI is available
+
+	// ThreadSafe reports whether this package calls into the library
+	// without its lock, as it does when built with the netcdf_threadsafe
+	// tag. The library itself doesn't report whether it's thread-safe.
+	ThreadSafe bool
+}
+
+// AtLeast reports whether the version of the library is at least
+// major.minor.patch.
+func (f LibraryFeatures) AtLeast(major, minor, patch int) bool {
+	if f.Major != major {
+		return f.Major > major
+	}
+	if f.Minor != minor {
+		return f.Minor > minor
+	}
+	return f.Patch >= patch
+}
+
+// cdf5Mode is NC_64BIT_DATA, the mode for creating CDF-5 files.
+const cdf5Mode = FileMode(0x20)
+
+var features struct {
+	once sync.Once
+	f    LibraryFeatures
+}
+
+// Features returns the features of the netCDF C library. They come from
+// the version string of the library, the macros of netcdf_meta.h it was
+// built with, and for the file formats, from creating small files in a
+// temporary directory. This is done by the first call only; later calls
+// return the same result.
+func Features() LibraryFeatures {
+	features.once.Do(func() {
+		features.f = probeFeatures(Version(), libMeta())
+	})
+	return features.f
+}
+
+// probeFeatures returns the features of the library with version string
+// version, and the macros meta of netcdf_meta.h.
+func probeFeatures(version string, meta map[string]int) LibraryFeatures {
+	var f LibraryFeatures
+	if version == "" {
+		return f
+	}
+	f.Version, f.Major, f.Minor, f.Patch = parseVersion(version)
+	f.ThreadSafe = !lock.Enabled
+
+	// has reports whether the macro name is true. Unknown macros are
+	// false, unless probe is given and reports true.
+	has := func(name string, probe func() bool) bool {
+		n, ok := meta[name]
+		if ok && n >= 0 {
+			return n != 0 && (probe == nil || probe())
+		}
+		return probe != nil && probe()
+	}
+	f.NetCDF4 = has("NC_HAS_NC4", func() bool { return canCreate(NETCDF4) })
+	f.CDF5 = has("NC_HAS_CDF5", func() bool { return canCreate(cdf5Mode) })
+	f.DAP2 = has("NC_HAS_DAP2", nil) || has("NC_HAS_DAP", nil)
+	f.DAP4 = has("NC_HAS_DAP4", nil)
+	f.NCZarr = has("NC_HAS_NCZARR", HasNCZarr)
+	f.Szip = has("NC_HAS_SZIP_WRITE", nil)
+	f.Zstd = has("NC_HAS_ZSTD", nil)
+	f.Parallel = has("NC_HAS_PARALLEL", nil)
+	return f
+}
+
+var versionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// parseVersion returns the version number at the start of version string
+// s, such as "4.9.2 of Mar 14 2023 12:00:00 $", and its parts.
+func parseVersion(s string) (version string, major, minor, patch int) {
+	m := versionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return "", 0, 0, 0
+	}
+	major, _ = strconv.Atoi(m[1])
+	minor, _ = strconv.Atoi(m[2])
+	patch, _ = strconv.Atoi(m[3])
+	return m[0], major, minor, patch
+}
+
+// canCreate reports whether a file can be created with the given mode.
+func canCreate(mode FileMode) bool {
+	dir, err := ioutil.TempDir("", "netcdf_features")
+	if err != nil {
+		return false
+	}
+	defer os.RemoveAll(dir)
+	ds, err := CreateFile(filepath.Join(dir, "probe.nc"), CLOBBER|mode)
+	if err != nil {
+		return false
+	}
+	return ds.Close() == nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	for _, tc := range []struct {
+		s                   string
+		version             string
+		major, minor, patch int
+	}{
+		{"4.9.2 of Mar 14 2023 12:00:00 $", "4.9.2", 4, 9, 2},
+		{"4.7.3 of Jan 23 2020 04:18:16 $", "4.7.3", 4, 7, 3},
+		{"4.10", "4.10", 4, 10, 0},
+		{"", "", 0, 0, 0},
+		{"unknown", "", 0, 0, 0},
+	} {
+		version, major, minor, patch := parseVersion(tc.s)
+		if version != tc.version || major != tc.major || minor != tc.minor || patch != tc.patch {
+			t.Errorf("parseVersion(%q) returned %q, %d, %d, %d; want %q, %d, %d, %d\n",
+				tc.s, version, major, minor, patch, tc.version, tc.major, tc.minor, tc.patch)
+		}
+	}
+}
+
+func TestProbeFeatures(t *testing.T) {
+	if got := probeFeatures("", nil); got != (LibraryFeatures{}) {
+		t.Errorf("features without a library are %+v\n", got)
+	}
+
+	// Macros set to 0 aren't probed, and macros missing from older
+	// versions are false unless probed.
+	meta := map[string]int{
+		"NC_HAS_NC4":        0,
+		"NC_HAS_CDF5":       0,
+		"NC_HAS_DAP":        1,
+		"NC_HAS_DAP2":       -1,
+		"NC_HAS_DAP4":       1,
+		"NC_HAS_NCZARR":     0,
+		"NC_HAS_SZIP_WRITE": 1,
+		"NC_HAS_PARALLEL":   0,
+	}
+	got := probeFeatures("4.6.1 of Jun 1 2018 $", meta)
+	want := LibraryFeatures{
+		Version:    "4.6.1",
+		Major:      4,
+		Minor:      6,
+		Patch:      1,
+		DAP2:       true,
+		DAP4:       true,
+		Szip:       true,
+		ThreadSafe: got.ThreadSafe,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("features are %+v; want %+v\n", got, want)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	f := Features()
+	if Version() == "" {
+		if f != (LibraryFeatures{}) {
+			t.Errorf("features without the C library are %+v\n", f)
+		}
+		return
+	}
+	if f.Version == "" || !f.AtLeast(3, 0, 0) {
+		t.Errorf("features have version %q\n", f.Version)
+	}
+	if f.NetCDF4 != canCreate(NETCDF4) {
+		t.Errorf("NetCDF4 is %v, but creating a netCDF-4 file doesn't agree\n", f.NetCDF4)
+	}
+	if f != Features() {
+		t.Errorf("Features changed between calls\n")
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	f := LibraryFeatures{Major: 4, Minor: 8, Patch: 1}
+	for _, tc := range []struct {
+		major, minor, patch int
+		want                bool
+	}{
+		{4, 8, 1, true},
+		{4, 8, 0, true},
+		{4, 7, 9, true},
+		{3, 9, 9, true},
+		{4, 8, 2, false},
+		{4, 9, 0, false},
+		{5, 0, 0, false},
+	} {
+		if got := f.AtLeast(tc.major, tc.minor, tc.patch); got != tc.want {
+			t.Errorf("AtLeast(%d, %d, %d) is %v; want %v\n", tc.major, tc.minor, tc.patch, got, tc.want)
+		}
+	}
+}
@@ -37,17 +37,6 @@ var TheFile = File{
 	Idents: []string{
 		"float64",
 		"DOUBLE",
-		"C.double",
-		"C.nc_get_att_double",
-		"C.nc_get_var_double",
-		"C.nc_get_var1_double",
-		"C.nc_get_vara_double",
-		"C.nc_get_vars_double",
-		"C.nc_put_att_double",
-		"C.nc_put_var_double",
-		"C.nc_put_var1_double",
-		"C.nc_put_vara_double",
-		"C.nc_put_vars_double",
 	},
 	DocIdents: []string{
 		"testReadFloat64s",
@@ -58,6 +47,7 @@ var TheFile = File{
 		"testReadFloat64Slice",
 		"testWriteFloat64StridedSlice",
 		"testReadFloat64StridedSlice",
+		"testParallelReadFloat64Slice",
 		"Float64sReader",
 		"GetFloat64s",
 		"ReadFloat64s",
@@ -69,7 +59,7 @@ var TheFile = File{
 		"ReadFloat64StridedSlice",
 		"WriteFloat64StridedSlice",
 	},
-	Keys: []string{"float64", "Float64s", "DOUBLE", "Float64", "C.double", "_double"},
+	Keys: []string{"float64", "Float64s", "DOUBLE", "Float64"},
 }
 
 // OutFiles are the files that needs to be generated from TheFile.
@@ -82,43 +72,43 @@ var TheFile = File{
 var OutFiles = []File{
 	{
 		Name: "nc_uint64.go",
-		Keys: []string{"uint64", "Uint64s", "UINT64", "Uint64", "C.ulonglong", "_ulonglong"},
+		Keys: []string{"uint64", "Uint64s", "UINT64", "Uint64"},
 	},
 	{
 		Name: "nc_int64.go",
-		Keys: []string{"int64", "Int64s", "INT64", "Int64", "C.longlong", "_longlong"},
+		Keys: []string{"int64", "Int64s", "INT64", "Int64"},
 	},
 	{
 		Name: "nc_uint.go",
-		Keys: []string{"uint32", "Uint32s", "UINT", "Uint32", "C.uint", "_uint"},
+		Keys: []string{"uint32", "Uint32s", "UINT", "Uint32"},
 	},
 	{
 		Name: "nc_int.go",
-		Keys: []string{"int32", "Int32s", "INT", "Int32", "C.int", "_int"},
+		Keys: []string{"int32", "Int32s", "INT", "Int32"},
 	},
 	{
 		Name: "nc_float.go",
-		Keys: []string{"float32", "Float32s", "FLOAT", "Float32", "C.float", "_float"},
+		Keys: []string{"float32", "Float32s", "FLOAT", "Float32"},
 	},
 	{
 		Name: "nc_ushort.go",
-		Keys: []string{"uint16", "Uint16s", "USHORT", "Uint16", "C.ushort", "_ushort"},
+		Keys: []string{"uint16", "Uint16s", "USHORT", "Uint16"},
 	},
 	{
 		Name: "nc_short.go",
-		Keys: []string{"int16", "Int16s", "SHORT", "Int16", "C.short", "_short"},
+		Keys: []string{"int16", "Int16s", "SHORT", "Int16"},
 	},
 	{
 		Name: "nc_ubyte.go",
-		Keys: []string{"uint8", "Uint8s", "UBYTE", "Uint8", "C.uchar", "_uchar"},
+		Keys: []string{"uint8", "Uint8s", "UBYTE", "Uint8"},
 	},
 	{
 		Name: "nc_byte.go",
-		Keys: []string{"int8", "Int8s", "BYTE", "Int8", "C.schar", "_schar"},
+		Keys: []string{"int8", "Int8s", "BYTE", "Int8"},
 	},
 	{
 		Name: "nc_char.go",
-		Keys: []string{"byte", "Bytes", "CHAR", "Bytes", "C.char", "_text"},
+		Keys: []string{"byte", "Bytes", "CHAR", "Bytes"},
 	},
 }
 
@@ -174,22 +164,6 @@ func (f *File) Visit(n ast.Node) ast.Visitor {
 		return nil
 	}
 
-	// nc_put_att_text takes 5 arguments unlike nc_put_att_double which takes 6 arguments
-	if f.Name == "nc_char.go" {
-		if call, ok := n.(*ast.CallExpr); ok {
-			if fun, ok := call.Fun.(*ast.SelectorExpr); ok {
-				if X, ok := fun.X.(*ast.Ident); ok && X.Name == "C" && fun.Sel.Name == "nc_put_att_double" {
-					if len(call.Args) != 6 {
-						log.Fatalf("C.nc_put_att_double call has wrong number of arguments")
-					}
-					call.Args[3] = call.Args[4]
-					call.Args[4] = call.Args[5]
-					call.Args = call.Args[:5]
-				}
-			}
-		}
-	}
-
 	if id, ok := n.(*ast.Ident); ok {
 		renameIdent(id, TheFile.Idents, f.Idents)
 		renameIdent(id, TheFile.DocIdents, f.DocIdents)
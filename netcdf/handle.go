@@ -0,0 +1,119 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// ErrClosed is returned when using a dataset, or its variables,
+// dimensions and attributes, after the dataset has been closed.
+var ErrClosed = errors.New("netcdf: use of closed dataset")
+
+// handle is the state shared by all copies of a Dataset.
+type handle struct {
+	drv    driver.Dataset
+	path   string // path used to open or create the dataset
+	closed int32  // accessed atomically; non-zero once closed
+	stack  []byte // stack trace of the opener, if leak detection is on
+}
+
+// Leak describes a dataset that was garbage collected while still open.
+type Leak struct {
+	ID    int    // netCDF ID of the dataset, or -1 if not opened by the C library
+	Path  string // path used to open or create the dataset
+	Stack []byte // stack trace of the goroutine that opened the dataset
+}
+
+var leaks struct {
+	sync.Mutex
+	report func(Leak)
+}
+
+// DetectLeaks turns on leak detection if report is not nil, and turns it
+// off otherwise. It's meant for debugging, since it makes opening
+// datasets slower.
+//
+// While leak detection is on, the stack trace of the goroutine opening or
+// creating a dataset is recorded. If the dataset is later garbage collected
+// without having been closed, report is called with the recorded stack
+// trace, and the dataset is closed. Both are done in a new goroutine, not
+// the finalizer goroutine of the runtime, so a slow report doesn't hold up
+// other finalizers.
+func DetectLeaks(report func(Leak)) {
+	leaks.Lock()
+	leaks.report = report
+	leaks.Unlock()
+}
+
+// newHandle returns a Dataset for an open dataset whose driver is d.
+func newHandle(d driver.Dataset, path string) Dataset {
+	h := &handle{drv: d, path: path}
+	leaks.Lock()
+	report := leaks.report
+	leaks.Unlock()
+	if report != nil {
+		buf := make([]byte, 4096)
+		for {
+			n := runtime.Stack(buf, false)
+			if n < len(buf) {
+				h.stack = buf[:n]
+				break
+			}
+			buf = make([]byte, 2*len(buf))
+		}
+		runtime.SetFinalizer(h, func(h *handle) {
+			if h.markClosed() {
+				// Finalizers run one at a time, so the report and
+				// the closing are left to another goroutine.
+				go func() {
+					report(Leak{ID: driverID(h.drv), Path: h.path, Stack: h.stack})
+					h.drv.Close()
+				}()
+			}
+		})
+	}
+	return Dataset{h: h}
+}
+
+// markClosed marks h as closed, and reports whether it was open.
+func (h *handle) markClosed() bool {
+	return atomic.CompareAndSwapInt32(&h.closed, 0, 1)
+}
+
+// close marks the dataset closed and releases it by calling f with its
+// driver, unless it's already closed.
+func (ds Dataset) close(f func(driver.Dataset) error) error {
+	if ds.h == nil || !ds.h.markClosed() {
+		return nil
+	}
+	runtime.SetFinalizer(ds.h, nil)
+	return f(ds.h.drv)
+}
+
+// driver returns the driver of ds, or ErrClosed if it's closed.
+func (ds Dataset) driver() (driver.Dataset, error) {
+	if ds.h == nil || atomic.LoadInt32(&ds.h.closed) != 0 {
+		return nil, ErrClosed
+	}
+	if ds.grp != nil {
+		return ds.grp, nil
+	}
+	return ds.h.drv, nil
+}
+
+// driverID returns the netCDF ID of the dataset of driver d,
+// or -1 if it's not opened through the C library.
+func driverID(d driver.Dataset) int {
+	if d, ok := d.(interface{ ID() int }); ok {
+		return d.ID()
+	}
+	return -1
+}
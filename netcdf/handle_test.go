@@ -0,0 +1,143 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestClose(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	ft := FileTest{
+		VarName:  "gopher",
+		DimNames: []string{"x"},
+		DimLens:  []uint64{4},
+		DataType: INT,
+		Attr:     map[string]interface{}{"units": "furlongs"},
+	}
+	createFile(t, f.Name(), &ft)
+
+	ds, err := OpenFile(f.Name(), NOWRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	v, err := ds.Var("gopher")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		t.Fatalf("Dims failed: %v\n", err)
+	}
+	a := v.Attr("units")
+
+	// A copy of ds refers to the same dataset.
+	cp := ds
+	if err := cp.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Errorf("second Close returned %v; expected nil\n", err)
+	}
+	var zero Dataset
+	if err := zero.Close(); err != nil {
+		t.Errorf("Close of zero Dataset returned %v; expected nil\n", err)
+	}
+
+	if _, err := ds.Var("gopher"); err != ErrClosed {
+		t.Errorf("Var after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+	if _, err := ds.Dim("x"); err != ErrClosed {
+		t.Errorf("Dim after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+	if _, err := v.Type(); err != ErrClosed {
+		t.Errorf("Var.Type after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+	if err := v.ReadInt32s(make([]int32, 4)); err != ErrClosed {
+		t.Errorf("ReadInt32s after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+	if _, err := dims[0].Len(); err != ErrClosed {
+		t.Errorf("Dim.Len after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+	if _, err := a.Len(); err != ErrClosed {
+		t.Errorf("Attr.Len after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+	if err := a.ReadBytes(make([]byte, 8)); err != ErrClosed {
+		t.Errorf("Attr.ReadBytes after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+	if err := ds.EndDef(); err != ErrClosed {
+		t.Errorf("EndDef after Close returned %v; expected %v\n", err, ErrClosed)
+	}
+}
+
+func TestDetectLeaks(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	ft := FileTest{VarName: "gopher", DataType: INT}
+	createFile(t, f.Name(), &ft)
+
+	leaked := make(chan Leak, 2)
+	DetectLeaks(func(l Leak) { leaked <- l })
+	defer DetectLeaks(nil)
+
+	// A dataset that's closed isn't reported.
+	ds, err := OpenFile(f.Name(), NOWRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+	func() {
+		if _, err := OpenFile(f.Name(), NOWRITE); err != nil {
+			t.Fatalf("Open failed: %v\n", err)
+		}
+	}()
+
+	timeout := time.After(10 * time.Second)
+	for {
+		runtime.GC()
+		select {
+		case l := <-leaked:
+			if l.Path != f.Name() {
+				t.Errorf("leaked dataset path is %q; expected %q\n", l.Path, f.Name())
+			}
+			if !bytes.Contains(l.Stack, []byte("TestDetectLeaks")) {
+				t.Errorf("leak stack trace doesn't contain the opener:\n%s\n", l.Stack)
+			}
+			select {
+			case l := <-leaked:
+				t.Errorf("closed dataset %v reported as leaked\n", l)
+			case <-time.After(100 * time.Millisecond):
+			}
+			return
+		case <-timeout:
+			t.Fatalf("leaked dataset was not reported\n")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
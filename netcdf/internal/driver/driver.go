@@ -0,0 +1,104 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package driver defines the interface between package netcdf and the
+// storage behind its datasets. The netCDF C library is one driver; others
+// can keep datasets elsewhere, such as in memory for unit tests.
+package driver
+
+// Global is the variable ID standing for the global attributes.
+const Global = -1
+
+// NewDataset returns a netcdf.Dataset whose storage is d, instead of the C
+// library, for the packages of this module that keep datasets elsewhere,
+// such as in memory for unit tests. Path is only used to describe the
+// dataset, and may be empty. It's set by package netcdf, which imports
+// this package, so the result is returned as an interface{}.
+var NewDataset func(d Dataset, path string) interface{}
+
+// CreateFile and OpenFile, if not nil, create and open dataset files for
+// package netcdf when it's built without cgo, so the C library isn't
+// available. Mode is a bitwise-or of netcdf.FileMode values. They're set by
+// package cdf, which can't be imported by package netcdf since it imports
+// it.
+var (
+	CreateFile func(path string, mode int) (Dataset, error)
+	OpenFile   func(path string, mode int) (Dataset, error)
+)
+
+// Dataset is an open dataset. Its methods correspond to functions of the
+// netCDF C library, and use the same IDs and type codes. Errors should be
+// netcdf.Error values, with the same codes as the C library, so callers
+// can't tell drivers apart.
+//
+// Values are passed as Go slices whose element type corresponds to the
+// type argument t: []int8 for BYTE, []byte for CHAR and UBYTE, []int16 for
+// SHORT, and so on. They may need conversion to or from the type of the
+// variable or attribute, as done by the C library. Package netcdf checks
+// that slices are long enough before calling the driver.
+//
+// Package netcdf serializes the calls into the driver of the C library.
+// Other drivers must be safe for concurrent use.
+type Dataset interface {
+	Close() error
+	EndDef() error
+	// EndDefWithOptions is like EndDef, but controls the layout of
+	// classic files as nc__enddef does. Drivers whose storage has no
+	// such layout ignore the options.
+	EndDefWithOptions(hMinFree, vAlign, vMinFree, rAlign uint64) error
+	Redef() error
+
+	NDims() (int, error)
+	NVars() (int, error)
+	NAttrs(varid int) (int, error)
+	UnlimitedDims() ([]int, error)
+
+	DefDim(name string, len uint64) (dimid int, err error)
+	DimID(name string) (int, error)
+	DimName(dimid int) (string, error)
+	DimLen(dimid int) (uint64, error)
+
+	DefVar(name string, t int, dimids []int) (varid int, err error)
+	VarID(name string) (int, error)
+	VarName(varid int) (string, error)
+	VarType(varid int) (int, error)
+	VarDims(varid int) ([]int, error)
+	SetDeflate(varid int, shuffle, deflate bool, level int) error
+	Deflate(varid int) (shuffle, deflate bool, level int, err error)
+	SetChunking(varid int, contiguous bool, sizes []uint64) error
+	Chunking(varid int) (contiguous bool, sizes []uint64, err error)
+
+	AttrName(varid, n int) (string, error)
+	AttrType(varid int, name string) (int, error)
+	AttrLen(varid int, name string) (uint64, error)
+	// GetAttr reads the value of an attribute into val.
+	GetAttr(varid int, name string, t int, val interface{}) error
+	// PutAttr creates or replaces an attribute of type t whose value
+	// is val.
+	PutAttr(varid int, name string, t int, val interface{}) error
+
+	// GetVar reads all the values of a variable into data.
+	GetVar(varid, t int, data interface{}) error
+	// PutVar writes data as all the values of a variable.
+	PutVar(varid, t int, data interface{}) error
+	// GetVars reads the values of a variable selected by start, count and
+	// stride into data. A nil stride means a stride of 1 along each
+	// dimension. A nil count means the single value at index start.
+	GetVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error
+	// PutVars writes data to the values of a variable selected by start,
+	// count and stride, as for GetVars.
+	PutVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error
+
+	// DefGrp adds a group, and returns it as a Dataset sharing the
+	// file of the parent. Package netcdf only calls Close on the root
+	// group.
+	DefGrp(name string) (Dataset, error)
+	// Grp returns the group with the given name.
+	Grp(name string) (Dataset, error)
+	// GrpNames returns the names of the child groups.
+	GrpNames() ([]string, error)
+	// DimIDs returns the IDs of the dimensions defined in the dataset,
+	// not including those of its parent groups.
+	DimIDs() ([]int, error)
+}
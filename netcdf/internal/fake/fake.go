@@ -0,0 +1,871 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package fake implements an in-memory driver for package netcdf, meant
+// for unit tests that shouldn't depend on files or on the C library.
+//
+// A fake dataset behaves like a netCDF-4 dataset of the C library: it can
+// have groups, any dimension can be unlimited, definitions can be added at
+// any time, and values are converted between the type of the data and the
+// type of the variable or attribute. Values that aren't written read as
+// the fill value of their variable. Out of range conversions aren't
+// reported.
+package fake
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// goTypes maps the netCDF types supported by fake datasets to Go types.
+var goTypes = map[netcdf.Type]reflect.Type{
+	netcdf.BYTE:   reflect.TypeOf(int8(0)),
+	netcdf.CHAR:   reflect.TypeOf(byte(0)),
+	netcdf.SHORT:  reflect.TypeOf(int16(0)),
+	netcdf.INT:    reflect.TypeOf(int32(0)),
+	netcdf.FLOAT:  reflect.TypeOf(float32(0)),
+	netcdf.DOUBLE: reflect.TypeOf(float64(0)),
+	netcdf.UBYTE:  reflect.TypeOf(uint8(0)),
+	netcdf.USHORT: reflect.TypeOf(uint16(0)),
+	netcdf.UINT:   reflect.TypeOf(uint32(0)),
+	netcdf.INT64:  reflect.TypeOf(int64(0)),
+	netcdf.UINT64: reflect.TypeOf(uint64(0)),
+}
+
+// fillValues are the default fill values of the C library.
+var fillValues = map[netcdf.Type]interface{}{
+	netcdf.BYTE:   int8(-127),
+	netcdf.CHAR:   byte(0),
+	netcdf.SHORT:  int16(-32767),
+	netcdf.INT:    int32(-2147483647),
+	netcdf.FLOAT:  float32(9.9692099683868690e+36),
+	netcdf.DOUBLE: float64(9.9692099683868690e+36),
+	netcdf.UBYTE:  uint8(255),
+	netcdf.USHORT: uint16(65535),
+	netcdf.UINT:   uint32(4294967295),
+	netcdf.INT64:  int64(-9223372036854775806),
+	netcdf.UINT64: uint64(18446744073709551614),
+}
+
+// Dataset is an in-memory dataset, or a group of one. It implements
+// driver.Dataset.
+type Dataset struct {
+	*file
+	parent *Dataset // nil for the root group
+	name   string
+	dimids []int // dimensions defined in this group
+	vars   []*variable
+	attrs  []attr // global attributes
+	groups []*Dataset
+}
+
+// file is the state shared by all the groups of a dataset.
+type file struct {
+	mu     sync.Mutex
+	define bool
+	closed bool
+	dims   []dim // dimensions of all the groups, indexed by ID
+	root   *Dataset
+}
+
+type dim struct {
+	name      string
+	len       uint64
+	unlimited bool
+}
+
+type variable struct {
+	name    string
+	typ     netcdf.Type
+	dims    []int
+	attrs   []attr
+	data    reflect.Value // slice holding the values in row-major order
+	shuffle bool
+	deflate bool
+	level   int
+	chunks  []uint64 // nil if contiguous
+	written bool     // some values have been written
+}
+
+type attr struct {
+	name string
+	typ  netcdf.Type
+	val  reflect.Value // slice of values
+}
+
+var _ driver.Dataset = (*Dataset)(nil)
+
+// New returns a new empty dataset, in define mode.
+func New() *Dataset {
+	f := &file{define: true}
+	f.root = &Dataset{file: f}
+	return f.root
+}
+
+// Create returns a netcdf.Dataset for a new empty fake dataset, in define
+// mode. Path is only used to describe the dataset.
+func Create(path string) netcdf.Dataset {
+	return driver.NewDataset(New(), path).(netcdf.Dataset)
+}
+
+// lock locks d, and returns an error if it's closed.
+func (d *Dataset) lock() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return netcdf.EBADID
+	}
+	return nil
+}
+
+func (d *Dataset) Close() error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	d.closed = true
+	return nil
+}
+
+func (d *Dataset) EndDef() error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	if !d.define {
+		return netcdf.ENOTINDEFINE
+	}
+	d.define = false
+	return nil
+}
+
+// EndDefWithOptions is the same as EndDef, since fake datasets have no
+// layout.
+func (d *Dataset) EndDefWithOptions(hMinFree, vAlign, vMinFree, rAlign uint64) error {
+	return d.EndDef()
+}
+
+func (d *Dataset) Redef() error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	if d.define {
+		return netcdf.EINDEFINE
+	}
+	d.define = true
+	return nil
+}
+
+func (d *Dataset) NDims() (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	return len(d.dimids), nil
+}
+
+func (d *Dataset) NVars() (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	return len(d.vars), nil
+}
+
+func (d *Dataset) NAttrs(varid int) (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	attrs, err := d.attrList(varid)
+	if err != nil {
+		return 0, err
+	}
+	return len(*attrs), nil
+}
+
+func (d *Dataset) UnlimitedDims() ([]int, error) {
+	if err := d.lock(); err != nil {
+		return nil, err
+	}
+	defer d.mu.Unlock()
+	var ids []int
+	for _, id := range d.dimids {
+		if d.dims[id].unlimited {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// checkName returns an error if name can't be used for a new object,
+// given the names already in use.
+func checkName(name string, used func(name string) bool) error {
+	if name == "" || name[0] == '/' {
+		return netcdf.EBADNAME
+	}
+	if used(name) {
+		return netcdf.ENAMEINUSE
+	}
+	return nil
+}
+
+func (d *Dataset) DefDim(name string, n uint64) (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	used := func(name string) bool {
+		for _, id := range d.dimids {
+			if d.dims[id].name == name {
+				return true
+			}
+		}
+		return false
+	}
+	if err := checkName(name, used); err != nil {
+		return 0, err
+	}
+	d.define = true
+	d.dims = append(d.dims, dim{name: name, len: n, unlimited: n == 0})
+	id := len(d.dims) - 1
+	d.dimids = append(d.dimids, id)
+	return id, nil
+}
+
+// dimID returns the ID of the dimension named name, defined in this group
+// or its ancestors, or -1 if there's none.
+func (d *Dataset) dimID(name string) int {
+	for g := d; g != nil; g = g.parent {
+		for _, id := range g.dimids {
+			if d.dims[id].name == name {
+				return id
+			}
+		}
+	}
+	return -1
+}
+
+func (d *Dataset) DimID(name string) (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	if id := d.dimID(name); id >= 0 {
+		return id, nil
+	}
+	return 0, netcdf.EBADDIM
+}
+
+// dim returns the dimension with ID id.
+func (d *Dataset) dim(id int) (*dim, error) {
+	if id < 0 || id >= len(d.dims) {
+		return nil, netcdf.EBADDIM
+	}
+	return &d.dims[id], nil
+}
+
+func (d *Dataset) DimName(dimid int) (string, error) {
+	if err := d.lock(); err != nil {
+		return "", err
+	}
+	defer d.mu.Unlock()
+	dm, err := d.dim(dimid)
+	if err != nil {
+		return "", err
+	}
+	return dm.name, nil
+}
+
+func (d *Dataset) DimLen(dimid int) (uint64, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	dm, err := d.dim(dimid)
+	if err != nil {
+		return 0, err
+	}
+	return dm.len, nil
+}
+
+func (d *Dataset) DefVar(name string, t int, dimids []int) (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	used := func(name string) bool { return d.varID(name) >= 0 || d.group(name) != nil }
+	if err := checkName(name, used); err != nil {
+		return 0, err
+	}
+	typ := netcdf.Type(t)
+	if _, ok := goTypes[typ]; !ok {
+		return 0, netcdf.EBADTYPE
+	}
+	for _, id := range dimids {
+		if _, err := d.dim(id); err != nil {
+			return 0, err
+		}
+	}
+	v := &variable{
+		name: name,
+		typ:  typ,
+		dims: append([]int(nil), dimids...),
+	}
+	v.data = reflect.MakeSlice(reflect.SliceOf(goTypes[typ]), 0, 0)
+	v.resize(nil, d.shape(v))
+	d.define = true
+	d.vars = append(d.vars, v)
+	return len(d.vars) - 1, nil
+}
+
+func (d *Dataset) varID(name string) int {
+	for i, v := range d.vars {
+		if v.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *Dataset) VarID(name string) (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	if id := d.varID(name); id >= 0 {
+		return id, nil
+	}
+	return 0, netcdf.ENOTVAR
+}
+
+// variable returns the variable with ID id.
+func (d *Dataset) variable(id int) (*variable, error) {
+	if id < 0 || id >= len(d.vars) {
+		return nil, netcdf.ENOTVAR
+	}
+	return d.vars[id], nil
+}
+
+func (d *Dataset) VarName(varid int) (string, error) {
+	if err := d.lock(); err != nil {
+		return "", err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return "", err
+	}
+	return v.name, nil
+}
+
+func (d *Dataset) VarType(varid int) (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return 0, err
+	}
+	return int(v.typ), nil
+}
+
+func (d *Dataset) VarDims(varid int) ([]int, error) {
+	if err := d.lock(); err != nil {
+		return nil, err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return nil, err
+	}
+	return append([]int(nil), v.dims...), nil
+}
+
+func (d *Dataset) SetDeflate(varid int, shuffle, deflate bool, level int) error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return err
+	}
+	if deflate && (level < 0 || level > 9) {
+		return netcdf.EINVAL
+	}
+	v.shuffle, v.deflate, v.level = shuffle, deflate, level
+	return nil
+}
+
+func (d *Dataset) Deflate(varid int) (shuffle, deflate bool, level int, err error) {
+	if err := d.lock(); err != nil {
+		return false, false, 0, err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return false, false, 0, err
+	}
+	return v.shuffle, v.deflate, v.level, nil
+}
+
+func (d *Dataset) SetChunking(varid int, contiguous bool, sizes []uint64) error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return err
+	}
+	if contiguous {
+		v.chunks = nil
+		return nil
+	}
+	if len(sizes) != len(v.dims) {
+		return netcdf.EINVAL
+	}
+	for _, s := range sizes {
+		if s == 0 {
+			return netcdf.EINVAL
+		}
+	}
+	v.chunks = append([]uint64(nil), sizes...)
+	return nil
+}
+
+func (d *Dataset) Chunking(varid int) (contiguous bool, sizes []uint64, err error) {
+	if err := d.lock(); err != nil {
+		return false, nil, err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return false, nil, err
+	}
+	if v.chunks == nil {
+		return true, nil, nil
+	}
+	return false, append([]uint64(nil), v.chunks...), nil
+}
+
+// attrList returns the attributes of variable varid, which may be
+// driver.Global.
+func (d *Dataset) attrList(varid int) (*[]attr, error) {
+	if varid == driver.Global {
+		return &d.attrs, nil
+	}
+	v, err := d.variable(varid)
+	if err != nil {
+		return nil, err
+	}
+	return &v.attrs, nil
+}
+
+// attr returns the attribute named name of variable varid.
+func (d *Dataset) attr(varid int, name string) (*attr, error) {
+	attrs, err := d.attrList(varid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *attrs {
+		if (*attrs)[i].name == name {
+			return &(*attrs)[i], nil
+		}
+	}
+	return nil, netcdf.ENOTATT
+}
+
+func (d *Dataset) AttrName(varid, n int) (string, error) {
+	if err := d.lock(); err != nil {
+		return "", err
+	}
+	defer d.mu.Unlock()
+	attrs, err := d.attrList(varid)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 || n >= len(*attrs) {
+		return "", netcdf.ENOTATT
+	}
+	return (*attrs)[n].name, nil
+}
+
+func (d *Dataset) AttrType(varid int, name string) (int, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	a, err := d.attr(varid, name)
+	if err != nil {
+		return 0, err
+	}
+	return int(a.typ), nil
+}
+
+func (d *Dataset) AttrLen(varid int, name string) (uint64, error) {
+	if err := d.lock(); err != nil {
+		return 0, err
+	}
+	defer d.mu.Unlock()
+	a, err := d.attr(varid, name)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(a.val.Len()), nil
+}
+
+func (d *Dataset) GetAttr(varid int, name string, t int, val interface{}) error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	a, err := d.attr(varid, name)
+	if err != nil {
+		return err
+	}
+	dst := reflect.ValueOf(val)
+	if err := checkConvert(netcdf.Type(t), a.typ); err != nil {
+		return err
+	}
+	for i := 0; i < a.val.Len(); i++ {
+		convert(dst.Index(i), a.val.Index(i))
+	}
+	return nil
+}
+
+func (d *Dataset) PutAttr(varid int, name string, t int, val interface{}) error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	attrs, err := d.attrList(varid)
+	if err != nil {
+		return err
+	}
+	typ := netcdf.Type(t)
+	gt, ok := goTypes[typ]
+	src := reflect.ValueOf(val)
+	if !ok || src.Type().Elem() != gt {
+		return netcdf.EBADTYPE
+	}
+	a := attr{name: name, typ: typ, val: reflect.MakeSlice(src.Type(), src.Len(), src.Len())}
+	reflect.Copy(a.val, src)
+	if varid != driver.Global && name == "_FillValue" {
+		// Values not written yet use the new fill value.
+		defer func() {
+			if v := d.vars[varid]; !v.written {
+				v.resize(nil, d.shape(v))
+			}
+		}()
+	}
+	for i := range *attrs {
+		if (*attrs)[i].name == name {
+			(*attrs)[i] = a
+			return nil
+		}
+	}
+	if err := checkName(name, func(string) bool { return false }); err != nil {
+		return err
+	}
+	*attrs = append(*attrs, a)
+	return nil
+}
+
+func (d *Dataset) GetVar(varid, t int, data interface{}) error {
+	return d.transfer(varid, t, nil, nil, nil, data, false)
+}
+
+func (d *Dataset) PutVar(varid, t int, data interface{}) error {
+	return d.transfer(varid, t, nil, nil, nil, data, true)
+}
+
+func (d *Dataset) GetVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	if count == nil {
+		count = ones(len(start))
+	}
+	return d.transfer(varid, t, start, count, stride, data, false)
+}
+
+func (d *Dataset) PutVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	if count == nil {
+		count = ones(len(start))
+	}
+	return d.transfer(varid, t, start, count, stride, data, true)
+}
+
+func ones(n int) []uint64 {
+	s := make([]uint64, n)
+	for i := range s {
+		s[i] = 1
+	}
+	return s
+}
+
+// shape returns the current shape of variable v.
+func (d *Dataset) shape(v *variable) []uint64 {
+	shape := make([]uint64, len(v.dims))
+	for i, id := range v.dims {
+		shape[i] = d.dims[id].len
+	}
+	return shape
+}
+
+// transfer reads (or writes, if write is true) the values of variable varid
+// selected by start, count and stride from data, which holds values of type
+// t. A nil count selects all the values.
+func (d *Dataset) transfer(varid, t int, start, count []uint64, stride []int64, data interface{}, write bool) error {
+	if err := d.lock(); err != nil {
+		return err
+	}
+	defer d.mu.Unlock()
+	v, err := d.variable(varid)
+	if err != nil {
+		return err
+	}
+	if err := checkConvert(netcdf.Type(t), v.typ); err != nil {
+		return err
+	}
+	shape := d.shape(v)
+	if count == nil {
+		start, count = make([]uint64, len(shape)), shape
+	}
+	if stride == nil {
+		stride = make([]int64, len(shape))
+		for i := range stride {
+			stride[i] = 1
+		}
+	}
+	if len(start) != len(shape) || len(count) != len(shape) || len(stride) != len(shape) {
+		return netcdf.EINVALCOORDS
+	}
+	for i, n := range shape {
+		if stride[i] < 1 {
+			return netcdf.ESTRIDE
+		}
+		if write && d.dims[v.dims[i]].unlimited {
+			continue
+		}
+		if start[i] > n || start[i] == n && count[i] > 0 {
+			return netcdf.EINVALCOORDS
+		}
+		if count[i] > 0 && start[i]+(count[i]-1)*uint64(stride[i]) >= n {
+			return netcdf.EEDGE
+		}
+	}
+	if write {
+		d.grow(v, start, count, stride)
+		shape = d.shape(v)
+		v.written = true
+	}
+
+	buf := reflect.ValueOf(data)
+	n := 0
+	each(shape, start, count, stride, func(i int) {
+		if write {
+			convert(v.data.Index(i), buf.Index(n))
+		} else {
+			convert(buf.Index(n), v.data.Index(i))
+		}
+		n++
+	})
+	return nil
+}
+
+// grow extends the unlimited dimensions of v so the values selected by
+// start, count and stride exist.
+func (d *Dataset) grow(v *variable, start, count []uint64, stride []int64) {
+	for i, id := range v.dims {
+		dm := &d.dims[id]
+		if !dm.unlimited || count[i] == 0 {
+			continue
+		}
+		if end := start[i] + (count[i]-1)*uint64(stride[i]) + 1; end > dm.len {
+			var vars []*variable
+			var old [][]uint64
+			d.root.each(func(g *Dataset) {
+				for _, w := range g.vars {
+					vars = append(vars, w)
+					old = append(old, d.shape(w))
+				}
+			})
+			dm.len = end
+			for j, w := range vars {
+				if shape := d.shape(w); !equal(shape, old[j]) {
+					w.resize(old[j], shape)
+				}
+			}
+		}
+	}
+}
+
+// each calls f with d and each of its descendant groups.
+func (d *Dataset) each(f func(g *Dataset)) {
+	f(d)
+	for _, g := range d.groups {
+		g.each(f)
+	}
+}
+
+func (d *Dataset) DefGrp(name string) (driver.Dataset, error) {
+	if err := d.lock(); err != nil {
+		return nil, err
+	}
+	defer d.mu.Unlock()
+	used := func(name string) bool { return d.varID(name) >= 0 || d.group(name) != nil }
+	if err := checkName(name, used); err != nil {
+		return nil, err
+	}
+	d.define = true
+	g := &Dataset{file: d.file, parent: d, name: name}
+	d.groups = append(d.groups, g)
+	return g, nil
+}
+
+func (d *Dataset) GrpNames() ([]string, error) {
+	if err := d.lock(); err != nil {
+		return nil, err
+	}
+	defer d.mu.Unlock()
+	var names []string
+	for _, g := range d.groups {
+		names = append(names, g.name)
+	}
+	return names, nil
+}
+
+func (d *Dataset) DimIDs() ([]int, error) {
+	if err := d.lock(); err != nil {
+		return nil, err
+	}
+	defer d.mu.Unlock()
+	return append([]int(nil), d.dimids...), nil
+}
+
+// group returns the child group named name, or nil if there's none.
+func (d *Dataset) group(name string) *Dataset {
+	for _, g := range d.groups {
+		if g.name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+func (d *Dataset) Grp(name string) (driver.Dataset, error) {
+	if err := d.lock(); err != nil {
+		return nil, err
+	}
+	defer d.mu.Unlock()
+	if g := d.group(name); g != nil {
+		return g, nil
+	}
+	return nil, netcdf.ENOGRP
+}
+
+// fill returns the fill value of v.
+func (v *variable) fill() reflect.Value {
+	for _, a := range v.attrs {
+		if a.name == "_FillValue" && a.typ == v.typ && a.val.Len() == 1 {
+			return a.val.Index(0)
+		}
+	}
+	return reflect.ValueOf(fillValues[v.typ])
+}
+
+// resize changes the shape of the data of v from old to shape, keeping the
+// values at the same indices and filling in the new ones. A nil old shape
+// means that v doesn't have data yet.
+func (v *variable) resize(old, shape []uint64) {
+	n := int(product(shape))
+	data := reflect.MakeSlice(v.data.Type(), n, n)
+	fill := v.fill()
+	for i := 0; i < n; i++ {
+		data.Index(i).Set(fill)
+	}
+	if old != nil {
+		i := 0
+		each(shape, make([]uint64, len(old)), old, nil, func(j int) {
+			data.Index(j).Set(v.data.Index(i))
+			i++
+		})
+	}
+	v.data = data
+}
+
+// each calls f with the row-major index in an array of the given shape of
+// each value selected by start, count and stride, in order. A nil stride
+// means a stride of 1 along each dimension.
+func each(shape, start, count []uint64, stride []int64, f func(i int)) {
+	if product(count) == 0 {
+		return
+	}
+	idx := make([]uint64, len(shape))
+	for {
+		i := uint64(0)
+		for k, n := range shape {
+			s := uint64(1)
+			if stride != nil {
+				s = uint64(stride[k])
+			}
+			i = i*n + start[k] + idx[k]*s
+		}
+		f(int(i))
+
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < count[k] {
+				break
+			}
+			idx[k] = 0
+		}
+		if k < 0 {
+			return
+		}
+	}
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func product(nums []uint64) uint64 {
+	prod := uint64(1)
+	for _, n := range nums {
+		prod *= n
+	}
+	return prod
+}
+
+// checkConvert returns an error if values of type from can't be converted
+// to values of type to, or the reverse.
+func checkConvert(from, to netcdf.Type) error {
+	if _, ok := goTypes[from]; !ok {
+		return netcdf.EBADTYPE
+	}
+	if (from == netcdf.CHAR) != (to == netcdf.CHAR) {
+		return netcdf.ECHAR
+	}
+	return nil
+}
+
+// convert sets dst to src, converted to the type of dst.
+func convert(dst, src reflect.Value) {
+	dst.Set(src.Convert(dst.Type()))
+}
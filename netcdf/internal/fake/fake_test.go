@@ -0,0 +1,312 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package fake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+// create returns a dataset with a fixed and a record variable.
+func create(t *testing.T) netcdf.Dataset {
+	ds := Create("fake.nc")
+	time, err := ds.AddDim("time", 0)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	x, err := ds.AddDim("x", 3)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	if err := ds.Attr("title").WriteBytes([]byte("gopher test")); err != nil {
+		t.Fatalf("WriteBytes failed: %v\n", err)
+	}
+	fixed, err := ds.AddVar("fixed", netcdf.DOUBLE, []netcdf.Dim{x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := fixed.Attr("valid_range").WriteFloat64s([]float64{0, 10}); err != nil {
+		t.Fatalf("WriteFloat64s failed: %v\n", err)
+	}
+	rec, err := ds.AddVar("rec", netcdf.INT, []netcdf.Dim{time, x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := rec.Attr("_FillValue").WriteInt32s([]int32{-1}); err != nil {
+		t.Fatalf("WriteInt32s failed: %v\n", err)
+	}
+	if _, err := ds.AddVar("other", netcdf.SHORT, []netcdf.Dim{time}); err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := ds.EndDef(); err != nil {
+		t.Fatalf("EndDef failed: %v\n", err)
+	}
+	if err := fixed.WriteFloat64s([]float64{1.5, 2.5, 3.5}); err != nil {
+		t.Fatalf("WriteFloat64s failed: %v\n", err)
+	}
+	// Records are added by writing past the end of the variable.
+	for i, val := range []int32{1, 2, 3, 4} {
+		idx := []uint64{uint64(i / 2), uint64(i%2 + 1)}
+		if err := rec.WriteInt32At(idx, val); err != nil {
+			t.Fatalf("WriteInt32At failed: %v\n", err)
+		}
+	}
+	return ds
+}
+
+func TestDataset(t *testing.T) {
+	ds := create(t)
+	defer ds.Close()
+
+	if n, err := ds.NDims(); err != nil || n != 2 {
+		t.Errorf("NDims is %v, %v; expected 2\n", n, err)
+	}
+	if n, err := ds.NVars(); err != nil || n != 3 {
+		t.Errorf("NVars is %v, %v; expected 3\n", n, err)
+	}
+	dims, err := ds.UnlimitedDims()
+	if err != nil || len(dims) != 1 || dims[0].ID() != 0 {
+		t.Fatalf("UnlimitedDims is %v, %v; expected dimension 0\n", dims, err)
+	}
+	if n, err := dims[0].Len(); err != nil || n != 2 {
+		t.Errorf("length of unlimited dimension is %v, %v; expected 2\n", n, err)
+	}
+	title, err := netcdf.GetBytes(ds.Attr("title"))
+	if err != nil || string(title) != "gopher test" {
+		t.Errorf("title is %q, %v\n", title, err)
+	}
+
+	v, err := ds.Var("fixed")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	doubles, err := netcdf.GetFloat64s(v)
+	if err != nil || !reflect.DeepEqual(doubles, []float64{1.5, 2.5, 3.5}) {
+		t.Errorf("fixed is %v, %v\n", doubles, err)
+	}
+	a, err := v.AttrN(0)
+	if err != nil || a.Name() != "valid_range" {
+		t.Errorf("AttrN(0) is %q, %v; expected valid_range\n", a.Name(), err)
+	}
+	if typ, err := a.Type(); err != nil || typ != netcdf.DOUBLE {
+		t.Errorf("attribute type is %v, %v; expected DOUBLE\n", typ, err)
+	}
+	if n, err := v.NAttrs(); err != nil || n != 1 {
+		t.Errorf("NAttrs is %v, %v; expected 1\n", n, err)
+	}
+
+	v, err = ds.Var("rec")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	ints, err := netcdf.GetInt32s(v)
+	if want := []int32{-1, 1, 2, -1, 3, 4}; err != nil || !reflect.DeepEqual(ints, want) {
+		t.Errorf("rec is %v, %v; expected %v\n", ints, err, want)
+	}
+	ints = make([]int32, 2)
+	if err := v.ReadInt32StridedSlice(ints, []uint64{0, 1}, []uint64{2, 1}, []int64{1, 2}); err != nil {
+		t.Errorf("ReadInt32StridedSlice failed: %v\n", err)
+	} else if want := []int32{1, 3}; !reflect.DeepEqual(ints, want) {
+		t.Errorf("strided slice is %v; expected %v\n", ints, want)
+	}
+	// The C library converts values to the type of the data.
+	if val, err := v.ReadFloat64At([]uint64{1, 2}); err != nil || val != 4 {
+		t.Errorf("ReadFloat64At is %v, %v; expected 4\n", val, err)
+	}
+
+	// Records written to one variable are filled in for the others.
+	v, err = ds.Var("other")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	shorts, err := netcdf.GetInt16s(v)
+	if want := []int16{-32767, -32767}; err != nil || !reflect.DeepEqual(shorts, want) {
+		t.Errorf("other is %v, %v; expected %v\n", shorts, err, want)
+	}
+	if err := v.WriteInt16At([]uint64{3}, 7); err != nil {
+		t.Fatalf("WriteInt16At failed: %v\n", err)
+	}
+	if shape, err := v.LenDims(); err != nil || !reflect.DeepEqual(shape, []uint64{4}) {
+		t.Errorf("LenDims is %v, %v; expected [4]\n", shape, err)
+	}
+	v, _ = ds.Var("rec")
+	if val, err := v.ReadInt32At([]uint64{3, 2}); err != nil || val != -1 {
+		t.Errorf("ReadInt32At is %v, %v; expected -1\n", val, err)
+	}
+
+	if err := v.SetCompression(true, true, 5); err != nil {
+		t.Errorf("SetCompression failed: %v\n", err)
+	}
+	if shuffle, deflate, level, err := v.Compression(); err != nil || !shuffle || !deflate || level != 5 {
+		t.Errorf("Compression is %v, %v, %v, %v\n", shuffle, deflate, level, err)
+	}
+	if err := v.SetChunking(false, []uint64{1, 3}); err != nil {
+		t.Errorf("SetChunking failed: %v\n", err)
+	}
+	if contiguous, sizes, err := v.Chunking(); err != nil || contiguous || !reflect.DeepEqual(sizes, []uint64{1, 3}) {
+		t.Errorf("Chunking is %v, %v, %v\n", contiguous, sizes, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ds := create(t)
+	if _, err := ds.Var("nonexistent"); err != netcdf.ENOTVAR {
+		t.Errorf("Var returned %v; expected %v\n", err, netcdf.ENOTVAR)
+	}
+	if _, err := ds.Dim("nonexistent"); err != netcdf.EBADDIM {
+		t.Errorf("Dim returned %v; expected %v\n", err, netcdf.EBADDIM)
+	}
+	if _, err := ds.Attr("nonexistent").Len(); err != netcdf.ENOTATT {
+		t.Errorf("Attr.Len returned %v; expected %v\n", err, netcdf.ENOTATT)
+	}
+	if _, err := ds.AddDim("x", 4); err != netcdf.ENAMEINUSE {
+		t.Errorf("AddDim returned %v; expected %v\n", err, netcdf.ENAMEINUSE)
+	}
+	if _, err := ds.AddVar("bad", netcdf.STRING, nil); err != netcdf.EBADTYPE {
+		t.Errorf("AddVar of STRING variable returned %v; expected %v\n", err, netcdf.EBADTYPE)
+	}
+	if err := ds.EndDef(); err != netcdf.ENOTINDEFINE {
+		t.Errorf("EndDef in data mode returned %v; expected %v\n", err, netcdf.ENOTINDEFINE)
+	}
+	if err := ds.Redef(); err != nil {
+		t.Errorf("Redef failed: %v\n", err)
+	}
+	if err := ds.Redef(); err != netcdf.EINDEFINE {
+		t.Errorf("Redef in define mode returned %v; expected %v\n", err, netcdf.EINDEFINE)
+	}
+	if err := ds.EndDefWithOptions(1024, 4, 0, 4); err != nil {
+		t.Errorf("EndDefWithOptions failed: %v\n", err)
+	}
+	if _, err := ds.VarN(0).ReadFloat64At([]uint64{3}); err != netcdf.EINVALCOORDS {
+		t.Errorf("ReadFloat64At returned %v; expected %v\n", err, netcdf.EINVALCOORDS)
+	}
+	if err := ds.VarN(0).WriteFloat64StridedSlice(make([]float64, 2), []uint64{1}, []uint64{2}, []int64{2}); err == nil {
+		t.Errorf("writing past the end of a fixed dimension succeeded\n")
+	}
+	if _, err := ds.Attr("title").Len(); err != nil {
+		t.Errorf("Attr.Len failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Errorf("Close failed: %v\n", err)
+	}
+	if _, err := ds.Var("fixed"); err != netcdf.ErrClosed {
+		t.Errorf("Var after Close returned %v; expected %v\n", err, netcdf.ErrClosed)
+	}
+}
+
+func TestCopyDataset(t *testing.T) {
+	src := Create("src.nc")
+	defer src.Close()
+	x, err := src.AddDim("x", 3)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	v, err := src.AddVar("v", netcdf.FLOAT, []netcdf.Dim{x})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := v.Attr("units").WriteBytes([]byte("K")); err != nil {
+		t.Fatalf("WriteBytes failed: %v\n", err)
+	}
+	if err := src.Attr("version").WriteInt16s([]int16{1, 2}); err != nil {
+		t.Fatalf("WriteInt16s failed: %v\n", err)
+	}
+	if err := v.WriteFloat32s([]float32{1, 2, 3}); err != nil {
+		t.Fatalf("WriteFloat32s failed: %v\n", err)
+	}
+
+	dst := Create("dst.nc")
+	defer dst.Close()
+	if err := netcdf.CopyDatasetCtx(context.Background(), dst, src, nil); err != nil {
+		t.Fatalf("CopyDatasetCtx failed: %v\n", err)
+	}
+	v, err = dst.Var("v")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	floats, err := netcdf.GetFloat32s(v)
+	if err != nil || !reflect.DeepEqual(floats, []float32{1, 2, 3}) {
+		t.Errorf("v is %v, %v; expected [1 2 3]\n", floats, err)
+	}
+	units, err := netcdf.GetBytes(v.Attr("units"))
+	if err != nil || string(units) != "K" {
+		t.Errorf("units is %q, %v\n", units, err)
+	}
+	version, err := netcdf.GetInt16s(dst.Attr("version"))
+	if err != nil || !reflect.DeepEqual(version, []int16{1, 2}) {
+		t.Errorf("version is %v, %v; expected [1 2]\n", version, err)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	ds := create(t)
+	defer ds.Close()
+	g, err := ds.AddGroup("sub")
+	if err != nil {
+		t.Fatalf("AddGroup failed: %v\n", err)
+	}
+	if _, err := ds.AddGroup("sub"); err != netcdf.ENAMEINUSE {
+		t.Errorf("AddGroup of existing group returned %v; expected %v\n", err, netcdf.ENAMEINUSE)
+	}
+	if _, err := ds.Group("nonexistent"); err != netcdf.ENOGRP {
+		t.Errorf("Group returned %v; expected %v\n", err, netcdf.ENOGRP)
+	}
+
+	// Dimensions of the parent are visible from the group.
+	time, err := g.Dim("time")
+	if err != nil {
+		t.Fatalf("Dim failed: %v\n", err)
+	}
+	y, err := g.AddDim("y", 2)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	if n, err := g.NDims(); err != nil || n != 1 {
+		t.Errorf("NDims of group is %v, %v; expected 1\n", n, err)
+	}
+	if _, err := ds.Dim("y"); err != netcdf.EBADDIM {
+		t.Errorf("Dim of the parent returned %v; expected %v\n", err, netcdf.EBADDIM)
+	}
+	v, err := g.AddVar("rec", netcdf.DOUBLE, []netcdf.Dim{time, y})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if n, err := g.NVars(); err != nil || n != 1 {
+		t.Errorf("NVars of group is %v, %v; expected 1\n", n, err)
+	}
+
+	// Records added in the group are added in the parent.
+	if err := v.WriteFloat64At([]uint64{2, 1}, 5); err != nil {
+		t.Fatalf("WriteFloat64At failed: %v\n", err)
+	}
+	pv, err := ds.Var("other")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if shape, err := pv.LenDims(); err != nil || !reflect.DeepEqual(shape, []uint64{3}) {
+		t.Errorf("LenDims in parent is %v, %v; expected [3]\n", shape, err)
+	}
+
+	h, err := ds.Group("sub")
+	if err != nil {
+		t.Fatalf("Group failed: %v\n", err)
+	}
+	v, err = h.Var("rec")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if val, err := v.ReadFloat64At([]uint64{2, 1}); err != nil || val != 5 {
+		t.Errorf("ReadFloat64At is %v, %v; expected 5\n", val, err)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("Close failed: %v\n", err)
+	}
+	if _, err := ds.NVars(); err != netcdf.ErrClosed {
+		t.Errorf("NVars after closing a group returned %v; expected %v\n", err, netcdf.ErrClosed)
+	}
+}
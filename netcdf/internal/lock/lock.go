@@ -0,0 +1,31 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build !netcdf_threadsafe
+// +build !netcdf_threadsafe
+
+// Package lock serializes calls into the netCDF C library.
+//
+// The netCDF C library is not thread-safe unless it was built with
+// thread-safety enabled, so every call into it must be made while holding
+// the lock. Building with the netcdf_threadsafe tag turns Lock and Unlock
+// into no-ops for use with such a library.
+package lock
+
+import "sync"
+
+// Enabled reports whether calls into the C library are serialized.
+const Enabled = true
+
+var mu sync.Mutex
+
+// Lock acquires the library lock.
+func Lock() {
+	mu.Lock()
+}
+
+// Unlock releases the library lock.
+func Unlock() {
+	mu.Unlock()
+}
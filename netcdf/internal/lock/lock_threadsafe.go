@@ -0,0 +1,17 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build netcdf_threadsafe
+// +build netcdf_threadsafe
+
+package lock
+
+// Enabled reports whether calls into the C library are serialized.
+const Enabled = false
+
+// Lock does nothing since the C library is thread-safe.
+func Lock() {}
+
+// Unlock does nothing since the C library is thread-safe.
+func Unlock() {}
@@ -0,0 +1,736 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The JSON representation of a dataset, written by ExportJSON and read by
+// ImportJSON, is an object holding the definitions of the root group,
+// followed by the values of the variables:
+//
+//	{
+//	  "dimensions": [{"name": "time", "length": 2, "unlimited": true}, {"name": "x", "length": 3}],
+//	  "attributes": [{"name": "title", "type": "char", "value": "example"}],
+//	  "variables": [
+//	    {"name": "time", "type": "double", "dimensions": ["time"],
+//	     "attributes": [{"name": "units", "type": "char", "value": "days since 2000-01-01"}]},
+//	    {"name": "temp", "type": "float", "dimensions": ["time", "x"],
+//	     "attributes": [{"name": "valid_range", "type": "float", "value": [-100, 100]}]}
+//	  ],
+//	  "groups": [{"name": "forecast", "dimensions": [], "attributes": [], "variables": [...]}],
+//	  "data": {
+//	    "time": [0, 1],
+//	    "temp": [1.5, 2, "NaN", 4, 5, "-Infinity"],
+//	    "forecast/temp": [...]
+//	  }
+//	}
+//
+// Groups have the same fields as the root group, and a name. Types are the
+// lower case names of CDL: byte, char, short, int, float, double, ubyte,
+// ushort, uint, int64 and uint64. The length of unlimited dimensions is
+// their current length.
+//
+// Data is keyed by the path of the variables, and comes after all the
+// definitions so that both export and import can stream it. The values of
+// each variable are a flat array in row-major order. Values are encoded
+// as JSON numbers, except:
+//
+//   - NaN and infinite float and double values are the strings "NaN",
+//     "Infinity" and "-Infinity".
+//   - int64 and uint64 values are decimal strings, as they may not be
+//     representable by the float64 numbers of JavaScript.
+//   - Values of char variables are strings, one for each row along the
+//     last dimension, holding as many characters as the length of that
+//     dimension, including trailing NUL characters. Values of char
+//     attributes are a single string. Each byte is encoded as the Unicode
+//     code point of the same value (ISO 8859-1), so any bytes round-trip.
+//
+// Attribute values are arrays, even for a single value, except for char
+// attributes.
+
+// JSONOptions controls ExportJSON and ImportJSON. A nil *JSONOptions uses
+// the defaults.
+type JSONOptions struct {
+	// HeaderOnly leaves out the data of the variables.
+	HeaderOnly bool
+
+	// Transfer controls how the data is read or written, in pieces of at
+	// most Transfer.PieceLen values.
+	Transfer *TransferOptions
+}
+
+type jsonDim struct {
+	Name      string `json:"name"`
+	Length    uint64 `json:"length"`
+	Unlimited bool   `json:"unlimited,omitempty"`
+}
+
+type jsonAttr struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonVar struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Dimensions []string   `json:"dimensions"`
+	Attributes []jsonAttr `json:"attributes,omitempty"`
+}
+
+type jsonGroup struct {
+	Name       string      `json:"name,omitempty"`
+	Dimensions []jsonDim   `json:"dimensions"`
+	Attributes []jsonAttr  `json:"attributes"`
+	Variables  []jsonVar   `json:"variables"`
+	Groups     []jsonGroup `json:"groups,omitempty"`
+}
+
+var jsonTypes = map[Type]string{
+	BYTE:   "byte",
+	CHAR:   "char",
+	SHORT:  "short",
+	INT:    "int",
+	FLOAT:  "float",
+	DOUBLE: "double",
+	UBYTE:  "ubyte",
+	USHORT: "ushort",
+	UINT:   "uint",
+	INT64:  "int64",
+	UINT64: "uint64",
+}
+
+func jsonType(name string) (Type, error) {
+	for t, s := range jsonTypes {
+		if s == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported type %q", name)
+}
+
+// ExportJSON writes the JSON representation of dataset ds to w, as
+// described above. The data of each variable is read and written in
+// pieces, so it's never loaded whole. Opts may be nil to export the
+// header and the data.
+func ExportJSON(ds Dataset, w io.Writer, opts *JSONOptions) error {
+	if opts == nil {
+		opts = &JSONOptions{}
+	}
+	var vars []pathVar
+	g, err := exportGroup(ds, "", &vars)
+	if err != nil {
+		return err
+	}
+	header, err := marshalJSON(g)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if opts.HeaderOnly {
+		bw.Write(header)
+		bw.WriteString("\n")
+		return bw.Flush()
+	}
+	// Add the data after the definitions.
+	bw.Write(header[:len(header)-1])
+	bw.WriteString(`,"data":{`)
+	for i, pv := range vars {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n")
+		name, _ := marshalJSON(pv.path)
+		bw.Write(name)
+		bw.WriteString(":[")
+		if err := exportData(bw, pv.v, opts); err != nil {
+			return fmt.Errorf("variable %q: %v", pv.path, err)
+		}
+		bw.WriteString("]")
+	}
+	bw.WriteString("}}\n")
+	return bw.Flush()
+}
+
+// pathVar is a variable with its path.
+type pathVar struct {
+	path string
+	v    Var
+}
+
+// exportGroup returns the definitions of group ds, whose path is prefix,
+// and appends its variables to vars.
+func exportGroup(ds Dataset, prefix string, vars *[]pathVar) (jsonGroup, error) {
+	g := jsonGroup{Dimensions: []jsonDim{}, Variables: []jsonVar{}}
+	if prefix != "" {
+		g.Name = path.Base(prefix)
+	}
+	dims, err := ds.Dims()
+	if err != nil {
+		return g, err
+	}
+	unlimited, err := ds.UnlimitedDims()
+	if err != nil {
+		return g, err
+	}
+	for _, d := range dims {
+		jd := jsonDim{Unlimited: containsDim(unlimited, d)}
+		if jd.Name, err = d.Name(); err != nil {
+			return g, err
+		}
+		if jd.Length, err = d.Len(); err != nil {
+			return g, err
+		}
+		g.Dimensions = append(g.Dimensions, jd)
+	}
+	if g.Attributes, err = exportAttrs(ds.globals()); err != nil {
+		return g, err
+	}
+	if g.Attributes == nil {
+		g.Attributes = []jsonAttr{}
+	}
+
+	n, err := ds.NVars()
+	if err != nil {
+		return g, err
+	}
+	for i := 0; i < n; i++ {
+		v := ds.VarN(i)
+		var jv jsonVar
+		if jv.Name, err = v.Name(); err != nil {
+			return g, err
+		}
+		t, err := v.Type()
+		if err != nil {
+			return g, err
+		}
+		if jv.Type = jsonTypes[t]; jv.Type == "" {
+			return g, fmt.Errorf("variable %q has unsupported type %v", jv.Name, t)
+		}
+		vdims, err := v.Dims()
+		if err != nil {
+			return g, err
+		}
+		jv.Dimensions = make([]string, len(vdims))
+		for k, d := range vdims {
+			if jv.Dimensions[k], err = d.Name(); err != nil {
+				return g, err
+			}
+		}
+		if jv.Attributes, err = exportAttrs(v); err != nil {
+			return g, err
+		}
+		g.Variables = append(g.Variables, jv)
+		*vars = append(*vars, pathVar{path.Join(prefix, jv.Name), v})
+	}
+
+	groups, err := ds.GroupNames()
+	if err != nil {
+		return g, err
+	}
+	for _, name := range groups {
+		sub, err := ds.Group(name)
+		if err != nil {
+			return g, err
+		}
+		sg, err := exportGroup(sub, path.Join(prefix, name), vars)
+		if err != nil {
+			return g, err
+		}
+		g.Groups = append(g.Groups, sg)
+	}
+	return g, nil
+}
+
+func exportAttrs(v Var) ([]jsonAttr, error) {
+	n, err := v.NAttrs()
+	if err != nil {
+		return nil, err
+	}
+	var attrs []jsonAttr
+	for i := 0; i < n; i++ {
+		a, err := v.AttrN(i)
+		if err != nil {
+			return nil, err
+		}
+		t, val, err := a.read()
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %v", a.Name(), err)
+		}
+		ja := jsonAttr{Name: a.Name(), Type: jsonTypes[t]}
+		if t == CHAR {
+			ja.Value = appendJSONString(nil, val.([]byte))
+		} else {
+			ja.Value = append(appendJSONValues(append(ja.Value, '['), val), ']')
+		}
+		attrs = append(attrs, ja)
+	}
+	return attrs, nil
+}
+
+// exportData writes the values of v to w, separated by commas.
+func exportData(w *bufio.Writer, v Var, opts *JSONOptions) error {
+	t, err := v.Type()
+	if err != nil {
+		return err
+	}
+	shape, err := v.LenDims()
+	if err != nil {
+		return err
+	}
+	pieceLen := opts.Transfer.pieceLen()
+	rowLen := uint64(1)
+	if t == CHAR && len(shape) > 0 {
+		// Pieces hold whole rows of characters.
+		rowLen = shape[len(shape)-1]
+		if rowLen == 0 {
+			return nil
+		}
+		pieceLen = (pieceLen/rowLen + 1) * rowLen
+	}
+	if l := product(shape); l < pieceLen {
+		pieceLen = l
+	}
+	buf, err := makeSlice(t, pieceLen)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	s := slab{make([]uint64, len(shape)), shape}
+	return s.pieces(pieceLen, func(off uint64, p slab) error {
+		if err := transferSlice(v, t, buf, false, 0, p); err != nil {
+			return err
+		}
+		n := product(p.count)
+		b = b[:0]
+		if off > 0 {
+			b = append(b, ',')
+		}
+		if t == CHAR {
+			chars := buf.([]byte)[:n]
+			for i := uint64(0); i < n; i += rowLen {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = appendJSONString(b, chars[i:i+rowLen])
+			}
+		} else {
+			b = appendJSONValues(b, reflect.ValueOf(buf).Slice(0, int(n)).Interface())
+		}
+		_, err := w.Write(b)
+		return err
+	})
+}
+
+// appendJSONString appends chars as a JSON string, encoding each byte as
+// the code point of the same value.
+func appendJSONString(b []byte, chars []byte) []byte {
+	runes := make([]rune, len(chars))
+	for i, c := range chars {
+		runes[i] = rune(c)
+	}
+	s, _ := marshalJSON(string(runes))
+	return append(b, s...)
+}
+
+// marshalJSON is like json.Marshal, but doesn't escape HTML characters.
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// appendJSONValues appends the values of data, a slice of one of the Go
+// types supported by this package, separated by commas.
+func appendJSONValues(b []byte, data interface{}) []byte {
+	rv := reflect.ValueOf(data)
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		switch x := rv.Index(i); x.Kind() {
+		case reflect.Int64:
+			b = append(strconv.AppendInt(append(b, '"'), x.Int(), 10), '"')
+		case reflect.Uint64:
+			b = append(strconv.AppendUint(append(b, '"'), x.Uint(), 10), '"')
+		case reflect.Int8, reflect.Int16, reflect.Int32:
+			b = strconv.AppendInt(b, x.Int(), 10)
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			b = strconv.AppendUint(b, x.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			f := x.Float()
+			switch {
+			case math.IsNaN(f):
+				b = append(b, `"NaN"`...)
+			case math.IsInf(f, 1):
+				b = append(b, `"Infinity"`...)
+			case math.IsInf(f, -1):
+				b = append(b, `"-Infinity"`...)
+			default:
+				b = strconv.AppendFloat(b, f, 'g', -1, x.Type().Bits())
+			}
+		}
+	}
+	return b
+}
+
+// ImportJSON creates a netCDF-4 file at path holding the dataset whose
+// JSON representation, described above, is read from r. The data is
+// written in pieces as it's read, so it's never loaded whole.
+func ImportJSON(r io.Reader, path string) error {
+	ds, err := CreateFile(path, CLOBBER|NETCDF4)
+	if err != nil {
+		return err
+	}
+	if err := ImportJSONInto(ds, r, nil); err != nil {
+		ds.Close()
+		return err
+	}
+	return ds.Close()
+}
+
+// ImportJSONInto is like ImportJSON, but defines the dataset in ds, which
+// must be a newly created dataset in define mode, of any format. On
+// return, ds is in data mode. Opts may be nil for the defaults; with
+// HeaderOnly, the data is skipped.
+func ImportJSONInto(ds Dataset, r io.Reader, opts *JSONOptions) error {
+	if opts == nil {
+		opts = &JSONOptions{}
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	var g jsonGroup
+	vars := make(map[string]importVar)
+	defined := false
+	define := func() error {
+		if err := importGroup(ds, g, "", nil, vars); err != nil {
+			return err
+		}
+		defined = true
+		return ds.EndDef()
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		switch key {
+		case "dimensions":
+			err = dec.Decode(&g.Dimensions)
+		case "attributes":
+			err = dec.Decode(&g.Attributes)
+		case "variables":
+			err = dec.Decode(&g.Variables)
+		case "groups":
+			err = dec.Decode(&g.Groups)
+		case "data":
+			if err := define(); err != nil {
+				return err
+			}
+			err = importData(dec, vars, opts)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return fmt.Errorf("%q: %v", key, err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if !defined {
+		return define()
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != d {
+		return fmt.Errorf("found %v; expected %v", tok, d)
+	}
+	return nil
+}
+
+// importVar is a variable defined by ImportJSON, with its shape given by
+// the lengths of the dimensions in the JSON representation.
+type importVar struct {
+	v     Var
+	t     Type
+	shape []uint64
+}
+
+// importGroup defines group g, whose path is prefix, in ds. Lengths holds
+// the lengths of the dimensions of the parent groups.
+func importGroup(ds Dataset, g jsonGroup, prefix string, lengths map[string]uint64, vars map[string]importVar) error {
+	scope := make(map[string]uint64)
+	for name, n := range lengths {
+		scope[name] = n
+	}
+	for _, d := range g.Dimensions {
+		n := d.Length
+		if d.Unlimited {
+			n = 0
+		}
+		if _, err := ds.AddDim(d.Name, n); err != nil {
+			return fmt.Errorf("dimension %q: %v", path.Join(prefix, d.Name), err)
+		}
+		scope[d.Name] = d.Length
+	}
+	if err := importAttrs(ds.globals(), g.Attributes); err != nil {
+		return err
+	}
+	for _, jv := range g.Variables {
+		p := path.Join(prefix, jv.Name)
+		t, err := jsonType(jv.Type)
+		if err != nil {
+			return fmt.Errorf("variable %q: %v", p, err)
+		}
+		iv := importVar{t: t, shape: make([]uint64, len(jv.Dimensions))}
+		dims := make([]Dim, len(jv.Dimensions))
+		for i, name := range jv.Dimensions {
+			if dims[i], err = ds.Dim(name); err != nil {
+				return fmt.Errorf("variable %q: dimension %q: %v", p, name, err)
+			}
+			iv.shape[i] = scope[name]
+		}
+		if iv.v, err = ds.AddVar(jv.Name, t, dims); err != nil {
+			return fmt.Errorf("variable %q: %v", p, err)
+		}
+		if err := importAttrs(iv.v, jv.Attributes); err != nil {
+			return fmt.Errorf("variable %q: %v", p, err)
+		}
+		vars[p] = iv
+	}
+	for _, sg := range g.Groups {
+		sub, err := ds.AddGroup(sg.Name)
+		if err != nil {
+			return fmt.Errorf("group %q: %v", path.Join(prefix, sg.Name), err)
+		}
+		if err := importGroup(sub, sg, path.Join(prefix, sg.Name), scope, vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importAttrs(v Var, attrs []jsonAttr) error {
+	for _, ja := range attrs {
+		t, err := jsonType(ja.Type)
+		if err != nil {
+			return fmt.Errorf("attribute %q: %v", ja.Name, err)
+		}
+		dec := json.NewDecoder(strings.NewReader(string(ja.Value)))
+		dec.UseNumber()
+		var val interface{}
+		if t == CHAR {
+			var s string
+			if err := dec.Decode(&s); err != nil {
+				return fmt.Errorf("attribute %q: %v", ja.Name, err)
+			}
+			val, err = jsonChars(s, -1)
+		} else {
+			var values []interface{}
+			if err := dec.Decode(&values); err != nil {
+				return fmt.Errorf("attribute %q: %v", ja.Name, err)
+			}
+			val, err = makeSlice(t, uint64(len(values)))
+			for i := 0; err == nil && i < len(values); i++ {
+				err = setJSONValue(val, i, values[i])
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("attribute %q: %v", ja.Name, err)
+		}
+		if err := v.Attr(ja.Name).write(t, val); err != nil {
+			return fmt.Errorf("attribute %q: %v", ja.Name, err)
+		}
+	}
+	return nil
+}
+
+// jsonChars decodes s, whose characters are bytes, into a byte slice of
+// length n, or of any length if n is negative.
+func jsonChars(s string, n int) ([]byte, error) {
+	var b []byte
+	for _, r := range s {
+		if r > 0xff {
+			return nil, fmt.Errorf("character %q of string %q isn't a byte", r, s)
+		}
+		b = append(b, byte(r))
+	}
+	if n >= 0 && len(b) != n {
+		return nil, fmt.Errorf("string %q has length %d; expected %d", s, len(b), n)
+	}
+	return b, nil
+}
+
+// setJSONValue sets element i of data, a slice of one of the Go types
+// supported by this package, to the JSON value val, a json.Number or a
+// string.
+func setJSONValue(data interface{}, i int, val interface{}) error {
+	var s string
+	switch x := val.(type) {
+	case json.Number:
+		s = string(x)
+	case string:
+		s = x
+	default:
+		return fmt.Errorf("invalid value %v", val)
+	}
+	e := reflect.ValueOf(data).Index(i)
+	switch e.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, e.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %v value %q", e.Type(), s)
+		}
+		e.SetInt(n)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, e.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %v value %q", e.Type(), s)
+		}
+		e.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch s {
+		case "NaN":
+			f = math.NaN()
+		case "Infinity":
+			f = math.Inf(1)
+		case "-Infinity":
+			f = math.Inf(-1)
+		default:
+			if _, ok := val.(string); ok {
+				return fmt.Errorf("invalid %v value %q", e.Type(), s)
+			}
+			var err error
+			if f, err = strconv.ParseFloat(s, e.Type().Bits()); err != nil {
+				return fmt.Errorf("invalid %v value %q", e.Type(), s)
+			}
+		}
+		e.SetFloat(f)
+	}
+	return nil
+}
+
+// importData reads the data object and writes the values of the
+// variables in vars.
+func importData(dec *json.Decoder, vars map[string]importVar, opts *JSONOptions) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+		iv, ok := vars[name]
+		if !ok {
+			return fmt.Errorf("data for unknown variable %q", name)
+		}
+		if opts.HeaderOnly {
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		} else {
+			err = importValues(dec, iv, opts)
+		}
+		if err != nil {
+			return fmt.Errorf("variable %q: %v", name, err)
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// importValues reads the array of values of variable iv, and writes them
+// in pieces.
+func importValues(dec *json.Decoder, iv importVar, opts *JSONOptions) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	shape := iv.shape
+	pieceLen := opts.Transfer.pieceLen()
+	rowLen := uint64(1)
+	if iv.t == CHAR && len(shape) > 0 {
+		rowLen = shape[len(shape)-1]
+		if rowLen == 0 {
+			return expectEnd(dec, 0)
+		}
+		pieceLen = (pieceLen/rowLen + 1) * rowLen
+	}
+	if l := product(shape); l < pieceLen {
+		pieceLen = l
+	}
+	buf, err := makeSlice(iv.t, pieceLen)
+	if err != nil {
+		return err
+	}
+	s := slab{make([]uint64, len(shape)), shape}
+	err = s.pieces(pieceLen, func(off uint64, p slab) error {
+		n := product(p.count)
+		for i := uint64(0); i < n; i += rowLen {
+			if !dec.More() {
+				return fmt.Errorf("%d values; expected %d", off+i, product(shape))
+			}
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if iv.t != CHAR {
+				if err := setJSONValue(buf, int(i), tok); err != nil {
+					return err
+				}
+				continue
+			}
+			str, ok := tok.(string)
+			if !ok {
+				return fmt.Errorf("invalid char value %v", tok)
+			}
+			chars, err := jsonChars(str, int(rowLen))
+			if err != nil {
+				return err
+			}
+			copy(buf.([]byte)[i:], chars)
+		}
+		return putSlice(iv.v, iv.t, buf, p)
+	})
+	if err != nil {
+		return err
+	}
+	return expectEnd(dec, product(shape))
+}
+
+// expectEnd reads the end of an array of n values.
+func expectEnd(dec *json.Decoder, n uint64) error {
+	if dec.More() {
+		return fmt.Errorf("more than %d values", n)
+	}
+	return expectDelim(dec, ']')
+}
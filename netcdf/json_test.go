@@ -0,0 +1,197 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+var jsonFixture = netcdftest.Fixture{
+	Dims: []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "x", Len: 3}, {Name: "len", Len: 4}},
+	Attrs: []netcdftest.Attr{
+		{Name: "title", Value: "caf\xe9 <json>"},
+		{Name: "big", Value: []int64{math.MaxInt64, -1}},
+	},
+	Vars: []netcdftest.Var{
+		{Name: "temp", Dims: []string{"time", "x"},
+			Data: []float32{1.5, 2, float32(math.NaN()), 4, float32(math.Inf(1)), float32(math.Inf(-1))},
+			Attrs: []netcdftest.Attr{
+				{Name: "units", Value: "K"},
+				{Name: "valid_range", Value: []float32{-100, 100}},
+			}},
+		{Name: "id", Dims: []string{"x"}, Data: []uint64{math.MaxUint64, 0, 7}},
+		{Name: "name", Type: netcdf.CHAR, Dims: []string{"x", "len"}, Data: []byte("ab\x00\x00abcd\xff\x00\x01z")},
+		{Name: "scalar", Data: []int8{-3}},
+		{Name: "empty", Type: netcdf.DOUBLE, Dims: []string{"time"}},
+	},
+}
+
+const jsonHeader = `{"dimensions":[{"name":"time","length":2,"unlimited":true},{"name":"x","length":3},{"name":"len","length":4}],` +
+	`"attributes":[{"name":"title","type":"char","value":"café <json>"},{"name":"big","type":"int64","value":["9223372036854775807","-1"]}],` +
+	`"variables":[{"name":"temp","type":"float","dimensions":["time","x"],"attributes":[{"name":"units","type":"char","value":"K"},{"name":"valid_range","type":"float","value":[-100,100]}]},` +
+	`{"name":"id","type":"uint64","dimensions":["x"]},` +
+	`{"name":"name","type":"char","dimensions":["x","len"]},` +
+	`{"name":"scalar","type":"byte","dimensions":[]},` +
+	`{"name":"empty","type":"double","dimensions":["time"]}]`
+
+func TestExportJSON(t *testing.T) {
+	ds := netcdftest.MustBuild(t, jsonFixture)
+	defer ds.Close()
+
+	var buf bytes.Buffer
+	if err := netcdf.ExportJSON(ds, &buf, &netcdf.JSONOptions{HeaderOnly: true}); err != nil {
+		t.Fatalf("ExportJSON failed: %v\n", err)
+	}
+	if got, want := buf.String(), jsonHeader+"}\n"; got != want {
+		t.Errorf("header is\n%s\nwant\n%s\n", got, want)
+	}
+
+	buf.Reset()
+	opts := &netcdf.JSONOptions{Transfer: &netcdf.TransferOptions{PieceLen: 2}}
+	if err := netcdf.ExportJSON(ds, &buf, opts); err != nil {
+		t.Fatalf("ExportJSON failed: %v\n", err)
+	}
+	want := jsonHeader + `,"data":{` +
+		"\n" + `"temp":[1.5,2,"NaN",4,"Infinity","-Infinity"],` +
+		"\n" + `"id":["18446744073709551615","0","7"],` +
+		"\n" + `"name":["ab\u0000\u0000","abcd","ÿ\u0000\u0001z"],` +
+		"\n" + `"scalar":[-3],` +
+		"\n" + `"empty":[9.969209968386869e+36,9.969209968386869e+36]}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("JSON is\n%s\nwant\n%s\n", got, want)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("JSON isn't valid\n")
+	}
+
+	// Import what was exported.
+	dst := netcdftest.New()
+	defer dst.Close()
+	if err := netcdf.ImportJSONInto(dst, &buf, opts); err != nil {
+		t.Fatalf("ImportJSONInto failed: %v\n", err)
+	}
+	netcdftest.AssertEqual(t, dst, ds)
+}
+
+func TestExportJSONGroups(t *testing.T) {
+	src := buildCopySource(t)
+	defer src.Close()
+	var buf bytes.Buffer
+	if err := netcdf.ExportJSON(src, &buf, nil); err != nil {
+		t.Fatalf("ExportJSON failed: %v\n", err)
+	}
+	for _, s := range []string{
+		`"groups":[{"name":"sub","dimensions":[],"attributes":[],"variables":[{"name":"v","type":"int","dimensions":["x"]}]}]`,
+		`"sub/v":[-1,-2,-3]`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("JSON %s doesn't contain %s\n", buf.String(), s)
+		}
+	}
+
+	dst := netcdftest.New()
+	defer dst.Close()
+	if err := netcdf.ImportJSONInto(dst, &buf, nil); err != nil {
+		t.Fatalf("ImportJSONInto failed: %v\n", err)
+	}
+	g, err := dst.Group("sub")
+	if err != nil {
+		t.Fatalf("Group failed: %v\n", err)
+	}
+	v, err := g.Var("v")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	data, err := netcdf.GetInt32s(v)
+	if err != nil {
+		t.Fatalf("GetInt32s failed: %v\n", err)
+	}
+	if len(data) != 3 || data[0] != -1 || data[2] != -3 {
+		t.Errorf("sub/v is %v; want [-1 -2 -3]\n", data)
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	// Data without the header of the dimensions that come later, and
+	// values given as numbers for int64.
+	in := `{"dimensions":[{"name":"x","length":2}],"attributes":[],` +
+		`"variables":[{"name":"v","type":"int64","dimensions":["x"]},{"name":"c","type":"char","dimensions":[]}],` +
+		`"data":{"v":[1,"-2"],"c":["q"]}}`
+	ds := netcdftest.New()
+	defer ds.Close()
+	if err := netcdf.ImportJSONInto(ds, strings.NewReader(in), nil); err != nil {
+		t.Fatalf("ImportJSONInto failed: %v\n", err)
+	}
+	netcdftest.AssertEqual(t, ds, netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "x", Len: 2}},
+		Vars: []netcdftest.Var{
+			{Name: "v", Dims: []string{"x"}, Data: []int64{1, -2}},
+			{Name: "c", Data: "q"},
+		},
+	}))
+}
+
+func TestImportJSONErrors(t *testing.T) {
+	header := `{"dimensions":[{"name":"x","length":2}],"attributes":[],` +
+		`"variables":[{"name":"v","type":"short","dimensions":["x"]},{"name":"c","type":"char","dimensions":["x"]}],`
+	for _, tc := range []struct {
+		in, err string
+	}{
+		{`[]`, "found [; expected {"},
+		{header + `"data":{"v":[1]}}`, `"data": variable "v": 1 values; expected 2`},
+		{header + `"data":{"v":[1,2,3]}}`, `"data": variable "v": more than 2 values`},
+		{header + `"data":{"v":[1,70000]}}`, `"data": variable "v": invalid int16 value "70000"`},
+		{header + `"data":{"v":[1,"NaN"]}}`, `"data": variable "v": invalid int16 value "NaN"`},
+		{header + `"data":{"c":["abc"]}}`, `"data": variable "c": string "abc" has length 3; expected 2`},
+		{header + `"data":{"c":["a€"]}}`, `"data": variable "c": character '€' of string "a€" isn't a byte`},
+		{header + `"data":{"w":[]}}`, `"data": data for unknown variable "w"`},
+		{`{"variables":[{"name":"v","type":"string","dimensions":[]}]}`, `variable "v": unsupported type "string"`},
+		{`{"attributes":[{"name":"a","type":"float","value":["Inf"]}]}`, `attribute "a": invalid float32 value "Inf"`},
+	} {
+		ds := netcdftest.New()
+		err := netcdf.ImportJSONInto(ds, strings.NewReader(tc.in), nil)
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("ImportJSONInto(%s) returned error %v; want %s\n", tc.in, err, tc.err)
+		}
+		ds.Close()
+	}
+}
+
+func TestJSONEmptyChar(t *testing.T) {
+	ds := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "n", Len: 0}},
+		Vars: []netcdftest.Var{{Name: "c", Type: netcdf.CHAR, Dims: []string{"n"}}},
+	})
+	defer ds.Close()
+	var buf bytes.Buffer
+	if err := netcdf.ExportJSON(ds, &buf, nil); err != nil {
+		t.Fatalf("ExportJSON failed: %v\n", err)
+	}
+	if !strings.Contains(buf.String(), `"c":[]`) {
+		t.Errorf("JSON %s doesn't contain \"c\":[]\n", buf.String())
+	}
+	data := buf.String()
+
+	dst := netcdftest.New()
+	defer dst.Close()
+	if err := netcdf.ImportJSONInto(dst, strings.NewReader(data), nil); err != nil {
+		t.Fatalf("ImportJSONInto failed: %v\n", err)
+	}
+	netcdftest.AssertEqual(t, dst, ds)
+
+	bad := netcdftest.New()
+	defer bad.Close()
+	err := netcdf.ImportJSONInto(bad, strings.NewReader(strings.Replace(data, `"c":[]`, `"c":["a"]`, 1)), nil)
+	if err == nil {
+		t.Errorf("ImportJSONInto of values for an empty char variable succeeded\n")
+	}
+}
@@ -0,0 +1,467 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CoordVar returns the coordinate variable of dimension dim of dataset ds:
+// the 1-D variable named like dim whose dimension is dim, following the CF
+// conventions.
+func (ds Dataset) CoordVar(dim string) (Var, error) {
+	v, err := ds.Var(dim)
+	if err == ENOTVAR {
+		return Var{}, fmt.Errorf("dimension %q has no coordinate variable", dim)
+	}
+	if err != nil {
+		return Var{}, err
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return Var{}, err
+	}
+	if len(dims) != 1 {
+		return Var{}, fmt.Errorf("variable %q has %d dimensions; a coordinate variable has 1", dim, len(dims))
+	}
+	if name, err := dims[0].Name(); err != nil {
+		return Var{}, err
+	} else if name != dim {
+		return Var{}, fmt.Errorf("variable %q has dimension %q; a coordinate variable has its own name", dim, name)
+	}
+	return v, nil
+}
+
+type labelOp int
+
+const (
+	labelExact labelOp = iota
+	labelNearest
+	labelRange
+)
+
+// Label selects indices along a dimension of a variable by the values of
+// the coordinate variable of the dimension, as returned by CoordVar.
+// Labels are created by Exact, Nearest and Between, and their variants
+// taking times, which are converted to values by the TimeUnits of the
+// coordinate variable.
+//
+// Longitudes are compared modulo 360 degrees, so -120 selects 240 in a
+// coordinate going from 0 to 360. Coordinate variables are longitudes if
+// their units are degrees_east or their standard_name is longitude.
+type Label struct {
+	Dim    string
+	op     labelOp
+	lo, hi float64
+	t0, t1 time.Time
+	isTime bool
+}
+
+// Exact selects the index whose coordinate is x, and drops the dimension
+// from the shape of the selection. Values of FLOAT coordinates are
+// compared as float32, so that Exact("lat", 45.2) works for them too.
+func Exact(dim string, x float64) Label {
+	return Label{Dim: dim, op: labelExact, lo: x}
+}
+
+// Nearest selects the index whose coordinate is nearest to x, and drops
+// the dimension from the shape of the selection.
+func Nearest(dim string, x float64) Label {
+	return Label{Dim: dim, op: labelNearest, lo: x}
+}
+
+// Between selects the indices whose coordinates are in the closed interval
+// [lo, hi], in the order of the coordinate variable. For longitudes, the
+// interval goes east from lo to hi, so that Between("lon", 350, 10)
+// selects the 20 degrees around the prime meridian. For other coordinates,
+// lo and hi can be given in any order.
+func Between(dim string, lo, hi float64) Label {
+	return Label{Dim: dim, op: labelRange, lo: lo, hi: hi}
+}
+
+// ExactTime is like Exact for times.
+func ExactTime(dim string, t time.Time) Label {
+	return Label{Dim: dim, op: labelExact, t0: t, isTime: true}
+}
+
+// NearestTime is like Nearest for times.
+func NearestTime(dim string, t time.Time) Label {
+	return Label{Dim: dim, op: labelNearest, t0: t, isTime: true}
+}
+
+// BetweenTimes is like Between for times.
+func BetweenTimes(dim string, t0, t1 time.Time) Label {
+	return Label{Dim: dim, op: labelRange, t0: t0, t1: t1, isTime: true}
+}
+
+// run is a range of count consecutive indices from start.
+type run struct {
+	start, count uint64
+}
+
+// SelectLabels returns the selections of the values of variable v given by
+// labels, which select indices along different dimensions of v. The other
+// dimensions are selected entirely. The strides of the selections are 1,
+// so their Start and Count can be given to the slice readers such as
+// ReadFloat64Slice.
+//
+// There's usually a single selection. A range of longitudes that crosses
+// the end of the coordinate variable, such as Between("lon", -10, 10) for
+// longitudes from 0 to 360, selects two runs of indices. In that case,
+// there's a selection for each of them, in the order the values must be
+// concatenated along that dimension. ReadArrayLabels does that. If no
+// values are selected, such as by a Between that matches no coordinate,
+// there are no selections.
+func (v Var) SelectLabels(labels ...Label) ([]Selection, error) {
+	runs, drop, err := v.labelRuns(labels)
+	if err != nil {
+		return nil, err
+	}
+	var sels []Selection
+	eachPiece(runs, func(start, count, _ []uint64) {
+		if product(count) == 0 {
+			return
+		}
+		s := Selection{
+			Start:  start,
+			Count:  count,
+			Stride: make([]int64, len(start)),
+		}
+		for i := range s.Stride {
+			s.Stride[i] = 1
+			if !drop[i] {
+				s.Shape = append(s.Shape, count[i])
+			}
+		}
+		sels = append(sels, s)
+	})
+	return sels, nil
+}
+
+// ReadArrayLabels reads the values of variable v selected by labels, as
+// described for SelectLabels, into a new array. Dimensions selected by
+// Exact or Nearest are dropped from the shape of the array.
+func ReadArrayLabels[T Number](v Var, labels ...Label) (*Array[T], error) {
+	runs, drop, err := v.labelRuns(labels)
+	if err != nil {
+		return nil, err
+	}
+	shape := make([]int, len(runs))
+	var kept []int
+	for i, rs := range runs {
+		for _, r := range rs {
+			shape[i] += int(r.count)
+		}
+		if !drop[i] {
+			kept = append(kept, shape[i])
+		}
+	}
+	a := NewArray[T](shape...)
+	eachPiece(runs, func(start, count, offset []uint64) {
+		if err != nil || product(count) == 0 {
+			return
+		}
+		var p *Array[T]
+		if p, err = ReadArraySlice[T](v, start, count); err != nil {
+			return
+		}
+		ranges := make([]Range, len(offset))
+		for i := range ranges {
+			ranges[i] = Range{Start: int(offset[i]), Stop: int(offset[i] + count[i])}
+		}
+		dst := a.View(ranges...)
+		p.Each(func(idx []int, val T) {
+			dst.Set(val, idx...)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a.Reshape(kept...)
+}
+
+// eachPiece calls f with the start and the count of each combination of
+// runs along the dimensions, and the offset of the piece in the
+// concatenation of the runs.
+func eachPiece(runs [][]run, f func(start, count, offset []uint64)) {
+	n := len(runs)
+	k := make([]int, n)
+	for {
+		start := make([]uint64, n)
+		count := make([]uint64, n)
+		offset := make([]uint64, n)
+		for i, rs := range runs {
+			start[i], count[i] = rs[k[i]].start, rs[k[i]].count
+			for _, r := range rs[:k[i]] {
+				offset[i] += r.count
+			}
+		}
+		f(start, count, offset)
+		i := n - 1
+		for ; i >= 0; i-- {
+			if k[i]++; k[i] < len(runs[i]) {
+				break
+			}
+			k[i] = 0
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
+
+// labelRuns returns the runs of indices selected by labels along each
+// dimension of v, and whether each dimension is dropped from the shape.
+func (v Var) labelRuns(labels []Label) (runs [][]run, drop []bool, err error) {
+	dims, err := v.Dims()
+	if err != nil {
+		return nil, nil, err
+	}
+	runs = make([][]run, len(dims))
+	drop = make([]bool, len(dims))
+	names := make([]string, len(dims))
+	for i, d := range dims {
+		if names[i], err = d.Name(); err != nil {
+			return nil, nil, err
+		}
+		n, err := d.Len()
+		if err != nil {
+			return nil, nil, err
+		}
+		runs[i] = []run{{0, n}}
+	}
+	seen := make(map[string]bool)
+	for _, l := range labels {
+		i := indexOf(names, l.Dim)
+		if i < 0 {
+			return nil, nil, fmt.Errorf("variable has no dimension %q", l.Dim)
+		}
+		if seen[l.Dim] {
+			return nil, nil, fmt.Errorf("more than one label for dimension %q", l.Dim)
+		}
+		seen[l.Dim] = true
+		if runs[i], err = l.runs(v.ds); err != nil {
+			return nil, nil, fmt.Errorf("dimension %q: %v", l.Dim, err)
+		}
+		drop[i] = l.op != labelRange
+	}
+	return runs, drop, nil
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// coord is the data of a coordinate variable.
+type coord struct {
+	x          []float64
+	t          Type
+	lon        bool
+	increasing bool
+}
+
+// readCoord reads the coordinate variable of dimension dim of ds.
+func readCoord(ds Dataset, dim string) (*coord, error) {
+	v, err := ds.CoordVar(dim)
+	if err != nil {
+		return nil, err
+	}
+	c := &coord{increasing: true}
+	if c.t, err = v.Type(); err != nil {
+		return nil, err
+	}
+	if c.x, err = readFloat64s(v); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(c.x); i++ {
+		if i == 1 {
+			c.increasing = c.x[1] > c.x[0]
+		}
+		if c.x[i] == c.x[i-1] || (c.x[i] > c.x[i-1]) != c.increasing {
+			return nil, fmt.Errorf("coordinate variable isn't strictly monotonic")
+		}
+	}
+	units, err := GetBytes(v.Attr("units"))
+	if err != nil && err != ENOTATT {
+		return nil, err
+	}
+	name, err := GetBytes(v.Attr("standard_name"))
+	if err != nil && err != ENOTATT {
+		return nil, err
+	}
+	switch strings.TrimRight(string(units), "\x00") {
+	case "degrees_east", "degree_east", "degrees_E", "degree_E", "degreesE", "degreeE":
+		c.lon = true
+	}
+	c.lon = c.lon || strings.TrimRight(string(name), "\x00") == "longitude"
+	return c, nil
+}
+
+// readFloat64s reads the entire numeric variable v, converted to float64.
+func readFloat64s(v Var) ([]float64, error) {
+	t, err := v.Type()
+	if err != nil {
+		return nil, err
+	}
+	if t == CHAR {
+		return nil, fmt.Errorf("unsupported variable type %v", t)
+	}
+	n, err := v.Len()
+	if err != nil {
+		return nil, err
+	}
+	data, err := makeSlice(t, n)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		if _, err := v.ReadCtx(context.Background(), data, nil); err != nil {
+			return nil, err
+		}
+	}
+	return appendFloat64s(nil, data), nil
+}
+
+// appendFloat64s appends the values of data, a slice of one of the Go
+// types supported by this package, converted to float64, to x.
+func appendFloat64s(x []float64, data interface{}) []float64 {
+	switch d := data.(type) {
+	case []uint64:
+		return appendFloat64sOf(x, d)
+	case []int64:
+		return appendFloat64sOf(x, d)
+	case []float64:
+		return append(x, d...)
+	case []uint32:
+		return appendFloat64sOf(x, d)
+	case []int32:
+		return appendFloat64sOf(x, d)
+	case []float32:
+		return appendFloat64sOf(x, d)
+	case []uint16:
+		return appendFloat64sOf(x, d)
+	case []int16:
+		return appendFloat64sOf(x, d)
+	case []uint8:
+		return appendFloat64sOf(x, d)
+	case []int8:
+		return appendFloat64sOf(x, d)
+	}
+	panic(fmt.Sprintf("netcdf: unsupported data type %T", data))
+}
+
+func appendFloat64sOf[T Number](x []float64, data []T) []float64 {
+	for _, val := range data {
+		x = append(x, float64(val))
+	}
+	return x
+}
+
+// mod360 returns x modulo 360, in [0, 360).
+func mod360(x float64) float64 {
+	m := math.Mod(x, 360)
+	if m < 0 {
+		m += 360
+	}
+	return m
+}
+
+// runs returns the runs of indices selected by l in the coordinate
+// variable of its dimension in ds.
+func (l Label) runs(ds Dataset) ([]run, error) {
+	c, err := readCoord(ds, l.Dim)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := l.lo, l.hi
+	if l.isTime {
+		v, err := ds.CoordVar(l.Dim)
+		if err != nil {
+			return nil, err
+		}
+		u, err := v.TimeUnits()
+		if err != nil {
+			return nil, err
+		}
+		lo, hi = u.Value(l.t0), u.Value(l.t1)
+	}
+	switch l.op {
+	case labelExact:
+		if c.t == FLOAT {
+			lo = float64(float32(lo))
+		}
+		for i, x := range c.x {
+			if x == lo || (c.lon && mod360(x-lo) == 0) {
+				return []run{{uint64(i), 1}}, nil
+			}
+		}
+		return nil, fmt.Errorf("no coordinate equal to %v", lo)
+
+	case labelNearest:
+		best, dist := -1, math.Inf(1)
+		for i, x := range c.x {
+			d := math.Abs(x - lo)
+			if c.lon {
+				d = mod360(d)
+				d = math.Min(d, 360-d)
+			}
+			if d < dist {
+				best, dist = i, d
+			}
+		}
+		if best < 0 {
+			return nil, fmt.Errorf("coordinate variable is empty")
+		}
+		return []run{{uint64(best), 1}}, nil
+	}
+
+	// Range selection.
+	in := func(x float64) bool { return lo <= x && x <= hi }
+	if c.lon {
+		width := mod360(hi - lo)
+		if hi-lo >= 360 {
+			width = 360
+		}
+		in = func(x float64) bool { return mod360(x-lo) <= width }
+	} else if lo > hi {
+		lo, hi = hi, lo
+	}
+	var runs []run
+	for i, x := range c.x {
+		if !in(x) {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].start+runs[n-1].count == uint64(i) {
+			runs[n-1].count++
+		} else {
+			runs = append(runs, run{uint64(i), 1})
+		}
+	}
+	if len(runs) == 0 {
+		return []run{{0, 0}}, nil
+	}
+	if c.lon {
+		// Order the runs going east from lo for increasing coordinates,
+		// and west from hi for decreasing ones.
+		key := func(r run) float64 {
+			if c.increasing {
+				return mod360(c.x[r.start] - lo)
+			}
+			return mod360(hi - c.x[r.start])
+		}
+		sort.Slice(runs, func(i, j int) bool { return key(runs[i]) < key(runs[j]) })
+	}
+	return runs, nil
+}
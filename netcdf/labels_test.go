@@ -0,0 +1,177 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+// createLabels returns a dataset with a variable temp of dimensions time,
+// lat and lon, whose value at (i, j, k) is 100*i + 10*j + k. Latitudes are
+// decreasing and longitudes go from 0 to 315 degrees.
+func createLabels(t *testing.T) (netcdf.Dataset, netcdf.Var) {
+	var data []int32
+	for i := int32(0); i < 4; i++ {
+		for j := int32(0); j < 5; j++ {
+			for k := int32(0); k < 8; k++ {
+				data = append(data, 100*i+10*j+k)
+			}
+		}
+	}
+	ds := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "time", Len: 4}, {Name: "lat", Len: 5}, {Name: "lon", Len: 8}},
+		Vars: []netcdftest.Var{
+			{Name: "time", Dims: []string{"time"}, Data: []float64{0, 15, 31, 45},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "days since 2020-01-01"}}},
+			{Name: "lat", Dims: []string{"lat"}, Data: []float32{50, 45.2, 30, 20, 10},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "degrees_north"}}},
+			{Name: "lon", Dims: []string{"lon"}, Data: []float64{0, 45, 90, 135, 180, 225, 270, 315},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "degrees_east"}}},
+			{Name: "temp", Dims: []string{"time", "lat", "lon"}, Data: data},
+		},
+	})
+	v, err := ds.Var("temp")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	return ds, v
+}
+
+func day(d int) time.Time {
+	return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d)
+}
+
+func TestSelectLabels(t *testing.T) {
+	ds, v := createLabels(t)
+	defer ds.Close()
+	sel := func(start, count, shape []uint64) netcdf.Selection {
+		return netcdf.Selection{Start: start, Count: count, Stride: []int64{1, 1, 1}, Shape: shape}
+	}
+	for _, tc := range []struct {
+		name   string
+		labels []netcdf.Label
+		want   []netcdf.Selection
+	}{
+		{"none", nil, []netcdf.Selection{sel([]uint64{0, 0, 0}, []uint64{4, 5, 8}, []uint64{4, 5, 8})}},
+		{"exact", []netcdf.Label{netcdf.Exact("lat", 45.2), netcdf.Exact("lon", -135)},
+			[]netcdf.Selection{sel([]uint64{0, 1, 5}, []uint64{4, 1, 1}, []uint64{4})}},
+		{"nearest", []netcdf.Label{netcdf.Nearest("lat", 26), netcdf.Nearest("lon", 350)},
+			[]netcdf.Selection{sel([]uint64{0, 2, 0}, []uint64{4, 1, 1}, []uint64{4})}},
+		{"decreasing", []netcdf.Label{netcdf.Between("lat", 15, 46)},
+			[]netcdf.Selection{sel([]uint64{0, 1, 0}, []uint64{4, 3, 8}, []uint64{4, 3, 8})}},
+		{"reversed", []netcdf.Label{netcdf.Between("lat", 46, 15)},
+			[]netcdf.Selection{sel([]uint64{0, 1, 0}, []uint64{4, 3, 8}, []uint64{4, 3, 8})}},
+		{"times", []netcdf.Label{netcdf.BetweenTimes("time", day(10), day(31)), netcdf.ExactTime("time", day(15))}, nil},
+		{"time range", []netcdf.Label{netcdf.BetweenTimes("time", day(10), day(31))},
+			[]netcdf.Selection{sel([]uint64{1, 0, 0}, []uint64{2, 5, 8}, []uint64{2, 5, 8})}},
+		{"nearest time", []netcdf.Label{netcdf.NearestTime("time", day(40))},
+			[]netcdf.Selection{sel([]uint64{3, 0, 0}, []uint64{1, 5, 8}, []uint64{5, 8})}},
+		{"lon range", []netcdf.Label{netcdf.Between("lon", -100, -40)},
+			[]netcdf.Selection{sel([]uint64{0, 0, 6}, []uint64{4, 5, 2}, []uint64{4, 5, 2})}},
+		{"lon wrap", []netcdf.Label{netcdf.Between("lon", 300, 50)},
+			[]netcdf.Selection{
+				sel([]uint64{0, 0, 7}, []uint64{4, 5, 1}, []uint64{4, 5, 1}),
+				sel([]uint64{0, 0, 0}, []uint64{4, 5, 2}, []uint64{4, 5, 2}),
+			}},
+		{"lon all", []netcdf.Label{netcdf.Between("lon", -180, 180)},
+			[]netcdf.Selection{sel([]uint64{0, 0, 0}, []uint64{4, 5, 8}, []uint64{4, 5, 8})}},
+	} {
+		sels, err := v.SelectLabels(tc.labels...)
+		if tc.want == nil {
+			if err == nil {
+				t.Errorf("%s: SelectLabels succeeded\n", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: SelectLabels failed: %v\n", tc.name, err)
+		} else if !reflect.DeepEqual(sels, tc.want) {
+			t.Errorf("%s: SelectLabels returned %+v; expected %+v\n", tc.name, sels, tc.want)
+		}
+	}
+
+	// A range that matches no coordinate selects nothing.
+	if sels, err := v.SelectLabels(netcdf.Between("lat", 60, 70)); err != nil || len(sels) != 0 {
+		t.Errorf("SelectLabels of an empty range returned %+v, %v; expected no selections\n", sels, err)
+	}
+	if a, err := netcdf.ReadArrayLabels[float64](v, netcdf.Between("lat", 60, 70)); err != nil || a.Len() != 0 {
+		t.Errorf("ReadArrayLabels of an empty range returned %v, %v; expected an empty array\n", a, err)
+	}
+
+	for _, tc := range []struct {
+		label netcdf.Label
+		err   string
+	}{
+		{netcdf.Exact("lat", 44), "no coordinate equal to 44"},
+		{netcdf.Exact("depth", 0), "variable has no dimension \"depth\""},
+		{netcdf.NearestTime("lat", day(0)), "dimension \"lat\""},
+	} {
+		_, err := v.SelectLabels(tc.label)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("SelectLabels(%+v) returned %v; expected error containing %q\n", tc.label, err, tc.err)
+		}
+	}
+}
+
+func TestReadArrayLabels(t *testing.T) {
+	ds, v := createLabels(t)
+	defer ds.Close()
+
+	a, err := netcdf.ReadArrayLabels[int32](v, netcdf.NearestTime("time", day(14)), netcdf.Between("lon", 270, 45))
+	if err != nil {
+		t.Fatalf("ReadArrayLabels failed: %v\n", err)
+	}
+	if want := []int{5, 4}; !reflect.DeepEqual(a.Shape(), want) {
+		t.Fatalf("shape is %v; expected %v\n", a.Shape(), want)
+	}
+	for j := 0; j < 5; j++ {
+		for k, lon := range []int32{6, 7, 0, 1} {
+			if got, want := a.At(j, k), 100+10*int32(j)+lon; got != want {
+				t.Errorf("value at (%d, %d) is %v; expected %v\n", j, k, got, want)
+			}
+		}
+	}
+
+	a, err = netcdf.ReadArrayLabels[int32](v, netcdf.Between("lat", 100, 60))
+	if err != nil {
+		t.Fatalf("ReadArrayLabels failed: %v\n", err)
+	}
+	if want := []int{4, 0, 8}; !reflect.DeepEqual(a.Shape(), want) {
+		t.Errorf("shape is %v; expected %v\n", a.Shape(), want)
+	}
+}
+
+func TestCoordVar(t *testing.T) {
+	ds := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "x", Len: 2}, {Name: "y", Len: 2}},
+		Vars: []netcdftest.Var{
+			{Name: "x", Dims: []string{"y"}, Data: []float64{0, 1}},
+			{Name: "y", Dims: []string{"y"}, Data: []float64{1, 1}},
+		},
+	})
+	defer ds.Close()
+	if _, err := ds.CoordVar("x"); err == nil {
+		t.Errorf("CoordVar of variable with another dimension succeeded\n")
+	}
+	if _, err := ds.CoordVar("z"); err == nil {
+		t.Errorf("CoordVar of missing variable succeeded\n")
+	}
+	if _, err := ds.CoordVar("y"); err != nil {
+		t.Errorf("CoordVar failed: %v\n", err)
+	}
+	v, err := ds.Var("y")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if _, err := v.SelectLabels(netcdf.Exact("y", 1)); err == nil || !strings.Contains(err.Error(), "monotonic") {
+		t.Errorf("SelectLabels for non-monotonic coordinate returned %v\n", err)
+	}
+}
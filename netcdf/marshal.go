@@ -0,0 +1,554 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Marshal writes the exported fields of the struct v, or of the struct v
+// points to, to dataset ds, which must not already define them.
+//
+// Each field is stored according to its type:
+//
+//   - A slice or array of numbers, or nested slices or arrays of numbers
+//     (e.g. [][]float32), is stored as a variable with one dimension for
+//     each level of nesting. Nested slices can't be ragged.
+//   - A number or a string is stored as an attribute of ds.
+//   - A struct, or a pointer to a struct, is stored as a group of ds,
+//     whose contents are given by the fields of the struct. Nil pointers
+//     are skipped. Structs without exported fields, such as time.Time,
+//     are reported as errors.
+//
+// Go numbers are stored as the netCDF type of the same size (e.g. int16 as
+// SHORT), with int and uint as INT64 and UINT64. Strings are stored as CHAR.
+// Fields of other types, such as bools and maps, are reported as errors.
+//
+// The field's tag, under the "nc" key, has the form
+//
+//	nc:"name,option,option..."
+//
+// Name is the name of the variable, attribute or group, and defaults to the
+// field name. A field whose tag is "-" is skipped. The options are:
+//
+//   - dims=a|b|c gives the names of the dimensions of a variable. Dimensions
+//     that don't exist yet in ds (or its parent groups) are added, and those
+//     that do exist must have the length of the data. The default for a
+//     one-dimensional variable is a dimension with the name of the variable,
+//     as for coordinate variables. Since a dimension of length 0 would be
+//     unlimited, variables can't be empty.
+//   - attr stores a slice of numbers as an attribute instead of a variable.
+//   - key=value adds an attribute named key whose value is the string value
+//     to a variable (e.g. units=K).
+//
+// For example:
+//
+//	type Product struct {
+//		Title string      `nc:"title"`
+//		Lat   []float64   `nc:"lat,units=degrees_north"`
+//		Lon   []float64   `nc:"lon,units=degrees_east"`
+//		Temp  [][]float32 `nc:"temp,dims=lat|lon,units=K"`
+//	}
+//
+// Marshal leaves define mode once the dimensions, variables and attributes
+// are defined, and then writes the data of the variables. Ds may also be
+// in data mode already, as netCDF-4 datasets can be when definitions are
+// added, so the ENOTINDEFINE error of EndDef is ignored. Classic datasets
+// in data mode make Marshal fail when it adds the first definition.
+func Marshal(ds Dataset, v interface{}) error {
+	rv, err := structValue(v, "Marshal")
+	if err != nil {
+		return err
+	}
+	var vars []pendingVar
+	if err := marshalStruct(ds, rv, &vars); err != nil {
+		return err
+	}
+	// Ds may be a netCDF-4 dataset in data mode, as described above.
+	if err := ds.EndDef(); err != nil && err != ENOTINDEFINE {
+		return err
+	}
+	for _, p := range vars {
+		if _, err := p.v.WriteCtx(context.Background(), p.data, nil); err != nil {
+			return fmt.Errorf("netcdf: writing variable %q: %v", p.name, err)
+		}
+	}
+	return nil
+}
+
+// Unmarshal reads the variables, attributes and groups of dataset ds into
+// the fields of the struct v points to. Fields are matched as described for
+// Marshal, and must exist in ds. Slices are allocated with the shape of
+// their variable, while arrays must already have that shape. Values are
+// converted to the type of the field.
+func Unmarshal(ds Dataset, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("netcdf: Unmarshal of non-pointer %T", v)
+	}
+	rv, err := structValue(v, "Unmarshal")
+	if err != nil {
+		return err
+	}
+	return unmarshalStruct(ds, rv)
+}
+
+// structValue returns the struct v, or the struct v points to.
+func structValue(v interface{}, fn string) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, fmt.Errorf("netcdf: %s of non-struct %T", fn, v)
+	}
+	return rv, nil
+}
+
+// pendingVar is a variable defined by Marshal, whose data is written
+// after leaving define mode.
+type pendingVar struct {
+	name string
+	v    Var
+	data interface{} // slice of the Go type of the variable's type
+}
+
+// fieldKind tells how a struct field is stored.
+type fieldKind int
+
+const (
+	varField fieldKind = iota
+	attrField
+	groupField
+)
+
+// field describes how a struct field is stored.
+type field struct {
+	index int
+	name  string
+	kind  fieldKind
+	depth int          // levels of nesting of a variable
+	elem  reflect.Type // type of the numbers of a variable or attribute
+	dims  []string     // dimension names of a variable
+	attrs [][2]string  // names and values of attributes of a variable
+}
+
+// fieldError returns an error about field f of struct type t.
+func fieldError(t reflect.Type, f reflect.StructField, format string, args ...interface{}) error {
+	return fmt.Errorf("netcdf: field %s.%s: %s", t.Name(), f.Name, fmt.Sprintf(format, args...))
+}
+
+// fields returns the exported fields of struct type t, and how they're
+// stored.
+func fields(t reflect.Type) ([]field, error) {
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		tag := sf.Tag.Get("nc")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		f := field{index: i, name: opts[0]}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		asAttr := false
+		for _, opt := range opts[1:] {
+			kv := strings.SplitN(opt, "=", 2)
+			switch {
+			case opt == "attr":
+				asAttr = true
+			case len(kv) != 2 || kv[0] == "":
+				return nil, fieldError(t, sf, "invalid tag option %q", opt)
+			case kv[0] == "dims":
+				f.dims = strings.Split(kv[1], "|")
+			default:
+				f.attrs = append(f.attrs, [2]string{kv[0], kv[1]})
+			}
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			ft = ft.Elem()
+			f.depth++
+		}
+		switch {
+		case ft.Kind() == reflect.Struct && f.depth == 0,
+			ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct && f.depth == 0:
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			// Structs such as time.Time would be stored as empty groups.
+			if !hasStoredField(ft) {
+				return nil, fieldError(t, sf, "struct type %v has no fields to store", sf.Type)
+			}
+			f.kind = groupField
+		case ft.Kind() == reflect.String && f.depth == 0:
+			f.kind, f.elem = attrField, ft
+		case numberType(ft) == 0:
+			return nil, fieldError(t, sf, "unsupported type %v", sf.Type)
+		case f.depth == 0 || asAttr && f.depth == 1:
+			f.kind, f.elem = attrField, ft
+		case asAttr:
+			return nil, fieldError(t, sf, "attribute of nested type %v", sf.Type)
+		default:
+			f.kind, f.elem = varField, ft
+			if f.dims == nil && f.depth == 1 {
+				f.dims = []string{f.name}
+			}
+			if len(f.dims) != f.depth {
+				return nil, fieldError(t, sf, "%d dimensions for type %v", len(f.dims), sf.Type)
+			}
+		}
+		if f.kind != varField && (f.dims != nil || f.attrs != nil) {
+			return nil, fieldError(t, sf, "dimensions or attributes given for a field that's not a variable")
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// hasStoredField reports whether struct type t has an exported field that
+// isn't skipped.
+func hasStoredField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath == "" && sf.Tag.Get("nc") != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// numberType returns the netCDF type used to store numbers of Go type t,
+// or 0 if t isn't a number.
+func numberType(t reflect.Type) Type {
+	switch t.Kind() {
+	case reflect.Int8:
+		return BYTE
+	case reflect.Int16:
+		return SHORT
+	case reflect.Int32:
+		return INT
+	case reflect.Int64, reflect.Int:
+		return INT64
+	case reflect.Uint8:
+		return UBYTE
+	case reflect.Uint16:
+		return USHORT
+	case reflect.Uint32:
+		return UINT
+	case reflect.Uint64, reflect.Uint:
+		return UINT64
+	case reflect.Float32:
+		return FLOAT
+	case reflect.Float64:
+		return DOUBLE
+	}
+	return 0
+}
+
+func marshalStruct(ds Dataset, rv reflect.Value, vars *[]pendingVar) error {
+	fs, err := fields(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fs {
+		fv := rv.Field(f.index)
+		switch f.kind {
+		case groupField:
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			g, err := ds.AddGroup(f.name)
+			if err != nil {
+				return fmt.Errorf("netcdf: adding group %q: %v", f.name, err)
+			}
+			if err := marshalStruct(g, fv, vars); err != nil {
+				return err
+			}
+		case attrField:
+			t, val := attrValue(fv, f.elem)
+			if err := ds.Attr(f.name).write(t, val); err != nil {
+				return fmt.Errorf("netcdf: writing attribute %q: %v", f.name, err)
+			}
+		case varField:
+			p, err := defineVar(ds, fv, f)
+			if err != nil {
+				return fmt.Errorf("netcdf: adding variable %q: %v", f.name, err)
+			}
+			*vars = append(*vars, p)
+		}
+	}
+	return nil
+}
+
+// attrValue returns the type and the value of an attribute stored in fv,
+// which holds numbers of type elem, or a string.
+func attrValue(fv reflect.Value, elem reflect.Type) (Type, interface{}) {
+	if fv.Kind() == reflect.String {
+		return CHAR, []byte(fv.String())
+	}
+	t := numberType(elem)
+	if fv.Kind() != reflect.Slice && fv.Kind() != reflect.Array {
+		s := reflect.New(reflect.ArrayOf(1, fv.Type())).Elem()
+		s.Index(0).Set(fv)
+		fv = s
+	}
+	return t, flatten(fv, 1, t)
+}
+
+// defineVar adds a variable for the data in fv, along with its dimensions
+// and attributes.
+func defineVar(ds Dataset, fv reflect.Value, f field) (pendingVar, error) {
+	shape, err := nestedShape(fv, f.depth)
+	if err != nil {
+		return pendingVar{}, err
+	}
+	if product(shape) == 0 {
+		return pendingVar{}, fmt.Errorf("field %q is empty", f.name)
+	}
+	dims := make([]Dim, len(f.dims))
+	for i, name := range f.dims {
+		d, err := ds.Dim(name)
+		if err == EBADDIM {
+			d, err = ds.AddDim(name, shape[i])
+		} else if err == nil {
+			var n uint64
+			if n, err = d.Len(); err == nil && n != shape[i] {
+				err = fmt.Errorf("dimension %q has length %d, but the data has length %d", name, n, shape[i])
+			}
+		}
+		if err != nil {
+			return pendingVar{}, err
+		}
+		dims[i] = d
+	}
+	t := numberType(f.elem)
+	v, err := ds.AddVar(f.name, t, dims)
+	if err != nil {
+		return pendingVar{}, err
+	}
+	for _, a := range f.attrs {
+		if err := v.Attr(a[0]).WriteBytes([]byte(a[1])); err != nil {
+			return pendingVar{}, err
+		}
+	}
+	return pendingVar{f.name, v, flatten(fv, f.depth, t)}, nil
+}
+
+// nestedShape returns the shape of the depth levels of slices or arrays
+// in v, which must not be ragged.
+func nestedShape(v reflect.Value, depth int) ([]uint64, error) {
+	shape := make([]uint64, depth)
+	var walk func(v reflect.Value, k int) error
+	walk = func(v reflect.Value, k int) error {
+		if k == depth {
+			return nil
+		}
+		n := uint64(v.Len())
+		if n != shape[k] {
+			return fmt.Errorf("ragged data: lengths %d and %d along dimension %d", shape[k], n, k)
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walk(v.Index(i), k+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// The lengths along each dimension are those of the first elements.
+	w := v
+	for k := 0; k < depth; k++ {
+		shape[k] = uint64(w.Len())
+		if w.Len() == 0 {
+			break
+		}
+		w = w.Index(0)
+	}
+	return shape, walk(v, 0)
+}
+
+// flatten returns the numbers in the depth levels of slices or arrays in v,
+// in row-major order, in a slice of the Go type corresponding to t.
+func flatten(v reflect.Value, depth int, t Type) interface{} {
+	s, _ := makeSlice(t, 0)
+	out := reflect.ValueOf(s)
+	et := out.Type().Elem()
+	var walk func(v reflect.Value, k int)
+	walk = func(v reflect.Value, k int) {
+		if k == depth {
+			out = reflect.Append(out, v.Convert(et))
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), k+1)
+		}
+	}
+	walk(v, 0)
+	return out.Interface()
+}
+
+// unflatten sets the depth levels of slices or arrays in v to the values
+// in flat, which are in row-major order for the given shape. Slices are
+// allocated, while arrays must have the right length.
+func unflatten(v reflect.Value, depth int, shape []uint64, flat reflect.Value) error {
+	i := 0
+	var walk func(v reflect.Value, k int) error
+	walk = func(v reflect.Value, k int) error {
+		if k == depth {
+			v.Set(flat.Index(i).Convert(v.Type()))
+			i++
+			return nil
+		}
+		n := int(shape[k])
+		switch v.Kind() {
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(v.Type(), n, n))
+		case reflect.Array:
+			if v.Len() != n {
+				return fmt.Errorf("array of length %d for dimension %d of length %d", v.Len(), k, n)
+			}
+		}
+		for j := 0; j < n; j++ {
+			if err := walk(v.Index(j), k+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(v, 0)
+}
+
+func unmarshalStruct(ds Dataset, rv reflect.Value) error {
+	fs, err := fields(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fs {
+		fv := rv.Field(f.index)
+		switch f.kind {
+		case groupField:
+			g, err := ds.Group(f.name)
+			if err != nil {
+				return fmt.Errorf("netcdf: reading group %q: %v", f.name, err)
+			}
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := unmarshalStruct(g, fv); err != nil {
+				return err
+			}
+		case attrField:
+			if err := readAttrField(ds.Attr(f.name), fv); err != nil {
+				return fmt.Errorf("netcdf: reading attribute %q: %v", f.name, err)
+			}
+		case varField:
+			if err := readVarField(ds, f, fv); err != nil {
+				return fmt.Errorf("netcdf: reading variable %q: %v", f.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readAttrField sets fv, which holds a string or numbers, to the value of
+// attribute a.
+func readAttrField(a Attr, fv reflect.Value) error {
+	t, val, err := a.read()
+	if err != nil {
+		return err
+	}
+	flat := reflect.ValueOf(val)
+	switch {
+	case fv.Kind() == reflect.String:
+		if t != CHAR {
+			return fmt.Errorf("attribute of type %v for a string", t)
+		}
+		fv.SetString(string(val.([]byte)))
+		return nil
+	case t == CHAR:
+		return fmt.Errorf("attribute of type CHAR for type %v", fv.Type())
+	case fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array:
+		return unflatten(fv, 1, []uint64{uint64(flat.Len())}, flat)
+	case flat.Len() != 1:
+		return fmt.Errorf("%d values for type %v", flat.Len(), fv.Type())
+	}
+	fv.Set(flat.Index(0).Convert(fv.Type()))
+	return nil
+}
+
+// readVarField sets fv, which holds f.depth levels of slices or arrays, to
+// the data of the variable of field f.
+func readVarField(ds Dataset, f field, fv reflect.Value) error {
+	v, err := ds.Var(f.name)
+	if err != nil {
+		return err
+	}
+	t, err := v.Type()
+	if err != nil {
+		return err
+	}
+	shape, err := v.LenDims()
+	if err != nil {
+		return err
+	}
+	if len(shape) != f.depth {
+		return fmt.Errorf("%d dimensions for type %v", len(shape), fv.Type())
+	}
+	if t == CHAR && f.elem.Kind() != reflect.Uint8 {
+		return fmt.Errorf("variable of type CHAR for type %v", fv.Type())
+	}
+	data, err := makeSlice(t, product(shape))
+	if err != nil {
+		return err
+	}
+	if _, err := v.ReadCtx(context.Background(), data, nil); err != nil {
+		return err
+	}
+	return unflatten(fv, f.depth, shape, reflect.ValueOf(data))
+}
+
+// read returns the type and the value of attribute a.
+func (a Attr) read() (t Type, val interface{}, err error) {
+	if t, err = a.Type(); err != nil {
+		return
+	}
+	n, err := a.Len()
+	if err != nil {
+		return
+	}
+	if val, err = makeSlice(t, n); err != nil {
+		return
+	}
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return
+	}
+	return t, val, d.GetAttr(a.v.id, a.name, int(t), val)
+}
+
+// write sets the value of attribute a to val, a slice of the Go type
+// corresponding to t.
+func (a Attr) write(t Type, val interface{}) error {
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutAttr(a.v.id, a.name, int(t), val)
+}
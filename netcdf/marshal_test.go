@@ -0,0 +1,199 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+type Calibration struct {
+	Gain   float64    `nc:"gain"`
+	Matrix [2][2]int8 `nc:"matrix,dims=row|col"`
+}
+
+type Product struct {
+	Title       string       `nc:"title"`
+	Version     int32        `nc:"version"`
+	ValidRange  []float32    `nc:"valid_range,attr"`
+	Lat         []float64    `nc:"lat,units=degrees_north"`
+	Lon         []float64    `nc:"lon,units=degrees_east"`
+	Temp        [][]float32  `nc:"temp,dims=lat|lon,units=K"`
+	Count       []int        `nc:",dims=lat"`
+	Calibration *Calibration `nc:"calibration"`
+	Ignored     string       `nc:"-"`
+	unexported  int
+}
+
+func TestMarshal(t *testing.T) {
+	p := Product{
+		Title:      "gopher",
+		Version:    3,
+		ValidRange: []float32{200, 350},
+		Lat:        []float64{10, 20},
+		Lon:        []float64{-5, 0, 5},
+		Temp:       [][]float32{{280, 281, 282}, {283, 284, 285}},
+		Count:      []int{7, 8},
+		Calibration: &Calibration{
+			Gain:   1.5,
+			Matrix: [2][2]int8{{1, 0}, {0, -1}},
+		},
+		Ignored: "ignored",
+	}
+	ds := netcdftest.New()
+	defer ds.Close()
+	if err := netcdf.Marshal(ds, &p); err != nil {
+		t.Fatalf("Marshal failed: %v\n", err)
+	}
+
+	want := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "lat", Len: 2}, {Name: "lon", Len: 3}},
+		Attrs: []netcdftest.Attr{
+			{Name: "title", Value: "gopher"},
+			{Name: "version", Value: int32(3)},
+			{Name: "valid_range", Value: []float32{200, 350}},
+		},
+		Vars: []netcdftest.Var{
+			{Name: "lat", Dims: []string{"lat"}, Data: p.Lat,
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "degrees_north"}}},
+			{Name: "lon", Dims: []string{"lon"}, Data: p.Lon,
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "degrees_east"}}},
+			{Name: "temp", Dims: []string{"lat", "lon"}, Data: []float32{280, 281, 282, 283, 284, 285},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "K"}}},
+			{Name: "Count", Dims: []string{"lat"}, Data: []int64{7, 8}},
+		},
+	})
+	defer want.Close()
+	netcdftest.AssertEqual(t, ds, want)
+
+	g, err := ds.Group("calibration")
+	if err != nil {
+		t.Fatalf("Group failed: %v\n", err)
+	}
+	gain, err := netcdf.GetFloat64s(g.Attr("gain"))
+	if err != nil || !reflect.DeepEqual(gain, []float64{1.5}) {
+		t.Errorf("gain is %v, %v; expected [1.5]\n", gain, err)
+	}
+	v, err := g.Var("matrix")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	matrix, err := netcdf.GetInt8s(v)
+	if err != nil || !reflect.DeepEqual(matrix, []int8{1, 0, 0, -1}) {
+		t.Errorf("matrix is %v, %v\n", matrix, err)
+	}
+	if _, err := ds.Attr("Ignored").Len(); err != netcdf.ENOTATT {
+		t.Errorf("ignored field was stored: %v\n", err)
+	}
+
+	var q Product
+	if err := netcdf.Unmarshal(ds, &q); err != nil {
+		t.Fatalf("Unmarshal failed: %v\n", err)
+	}
+	p.Ignored = ""
+	if !reflect.DeepEqual(p, q) {
+		t.Errorf("Unmarshal returned %+v; expected %+v\n", q, p)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	for _, tc := range []struct {
+		v   interface{}
+		err string
+	}{
+		{3, "Marshal of non-struct int"},
+		{struct{ B bool }{}, "field .B: unsupported type bool"},
+		{struct{ M map[string]int }{}, "unsupported type map[string]int"},
+		{struct{ S []string }{}, "unsupported type []string"},
+		{struct{ T time.Time }{}, "struct type time.Time has no fields to store"},
+		{struct{ P *struct{ x int } }{}, "has no fields to store"},
+		{struct {
+			X [][]int `nc:"x,dims=a"`
+		}{}, "1 dimensions for type [][]int"},
+		{struct {
+			X [][]int `nc:"x"`
+		}{}, "0 dimensions for type [][]int"},
+		{struct {
+			X int `nc:"x,units=K"`
+		}{}, "dimensions or attributes given for a field that's not a variable"},
+		{struct {
+			X [][]int `nc:"x,attr"`
+		}{}, "attribute of nested type [][]int"},
+		{struct {
+			X []int `nc:"x,bogus"`
+		}{}, `invalid tag option "bogus"`},
+		{struct {
+			X [][]int `nc:"x,dims=a|b"`
+		}{[][]int{{1, 2}, {3}}}, "ragged data"},
+		{struct {
+			X []int `nc:"x,dims=a"`
+			Y []int `nc:"y,dims=a"`
+		}{[]int{1}, []int{1, 2}}, `dimension "a" has length 1, but the data has length 2`},
+		{struct {
+			X []float64 `nc:"x"`
+			Y []float64 `nc:"y"`
+		}{}, `field "x" is empty`},
+		{struct {
+			X [][]float64 `nc:"x,dims=a|b"`
+		}{[][]float64{{}}}, `field "x" is empty`},
+	} {
+		ds := netcdftest.New()
+		err := netcdf.Marshal(ds, tc.v)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Marshal(%#v) returned %v; expected error containing %q\n", tc.v, err, tc.err)
+		}
+		if n, err := ds.NDims(); err == nil && n > 0 && strings.Contains(tc.err, "is empty") {
+			t.Errorf("Marshal(%#v) added %d dimensions\n", tc.v, n)
+		}
+		ds.Close()
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	ds := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims:  []netcdftest.Dim{{Name: "x", Len: 3}},
+		Attrs: []netcdftest.Attr{{Name: "title", Value: "gopher"}, {Name: "pair", Value: []int32{1, 2}}},
+		Vars:  []netcdftest.Var{{Name: "x", Dims: []string{"x"}, Data: []float64{1, 2, 3}}},
+	})
+	defer ds.Close()
+	for _, tc := range []struct {
+		v   interface{}
+		err string
+	}{
+		{struct{}{}, "Unmarshal of non-pointer struct {}"},
+		{new(int), "Unmarshal of non-struct *int"},
+		{&struct {
+			Y []float64 `nc:"y"`
+		}{}, `reading variable "y": ` + netcdf.ENOTVAR.Error()},
+		{&struct {
+			X [2]float64 `nc:"x"`
+		}{}, "array of length 2 for dimension 0 of length 3"},
+		{&struct {
+			X [][]float64 `nc:"x,dims=x|y"`
+		}{}, "1 dimensions for type [][]float64"},
+		{&struct {
+			Title int `nc:"title"`
+		}{}, "attribute of type CHAR for type int"},
+		{&struct {
+			Pair string `nc:"pair"`
+		}{}, "attribute of type INT for a string"},
+		{&struct {
+			Pair int `nc:"pair"`
+		}{}, "2 values for type int"},
+		{&struct {
+			G struct{ X int } `nc:"g"`
+		}{}, `reading group "g": ` + netcdf.ENOGRP.Error()},
+	} {
+		err := netcdf.Unmarshal(ds, tc.v)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Unmarshal(%#v) returned %v; expected error containing %q\n", tc.v, err, tc.err)
+		}
+	}
+}
@@ -7,21 +7,18 @@
 
 package netcdf
 
-import (
-	"fmt"
-	"unsafe"
-)
-
-// #include <stdlib.h>
-// #include <netcdf.h>
-import "C"
+import "fmt"
 
 // WriteBytes writes data as the entire data for variable v.
 func (v Var) WriteBytes(data []byte) error {
 	if err := okData(v, CHAR, len(data)); err != nil {
 		return err
 	}
-	return newError(C.nc_put_var_text(C.int(v.ds), C.int(v.id), (*C.char)(unsafe.Pointer(&data[0]))))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVar(v.id, int(CHAR), data)
 }
 
 // ReadBytes reads the entire variable v into data, which must have enough
@@ -30,17 +27,22 @@ func (v Var) ReadBytes(data []byte) error {
 	if err := okData(v, CHAR, len(data)); err != nil {
 		return err
 	}
-	return newError(C.nc_get_var_text(C.int(v.ds), C.int(v.id), (*C.char)(unsafe.Pointer(&data[0]))))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVar(v.id, int(CHAR), data)
 }
 
 // WriteBytes sets the value of attribute a to val.
 func (a Attr) WriteBytes(val []byte) error {
 	// We don't need okData here because netcdf library doesn't know
 	// the length or type of the attribute yet.
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	return newError(C.nc_put_att_text(C.int(a.v.ds), C.int(a.v.id), cname,
-		C.size_t(len(val)), (*C.char)(unsafe.Pointer(&val[0]))))
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutAttr(a.v.id, a.name, int(CHAR), val)
 }
 
 // ReadBytes reads the entire attribute value into val.
@@ -48,33 +50,32 @@ func (a Attr) ReadBytes(val []byte) (err error) {
 	if err := okData(a, CHAR, len(val)); err != nil {
 		return err
 	}
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	err = newError(C.nc_get_att_text(C.int(a.v.ds), C.int(a.v.id), cname,
-		(*C.char)(unsafe.Pointer(&val[0]))))
-	return
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.GetAttr(a.v.id, a.name, int(CHAR), val)
 }
 
 // ReadBytesAt returns a value via index position
 func (v Var) ReadBytesAt(idx []uint64) (val byte, err error) {
-	var dimPtr *C.size_t
-	if len(idx) > 0 {
-		dimPtr = (*C.size_t)(unsafe.Pointer(&idx[0]))
+	d, err := v.ds.driver()
+	if err != nil {
+		return
 	}
-	err = newError(C.nc_get_var1_text(C.int(v.ds), C.int(v.id),
-		dimPtr, (*C.char)(unsafe.Pointer(&val))))
+	buf := []byte{0}
+	err = d.GetVars(v.id, int(CHAR), idx, nil, nil, buf)
+	val = buf[0]
 	return
 }
 
 // WriteBytesAt sets a value via its index position
 func (v Var) WriteBytesAt(idx []uint64, val byte) (err error) {
-	var dimPtr *C.size_t
-	if len(idx) > 0 {
-		dimPtr = (*C.size_t)(unsafe.Pointer(&idx[0]))
+	d, err := v.ds.driver()
+	if err != nil {
+		return
 	}
-	err = newError(C.nc_put_var1_text(C.int(v.ds), C.int(v.id),
-		dimPtr, (*C.char)(unsafe.Pointer(&val))))
-	return
+	return d.PutVars(v.id, int(CHAR), idx, nil, nil, []byte{val})
 }
 
 // WriteBytesSlice writes data as a slice of variable v. The slice is specified by start and count:
@@ -83,11 +84,11 @@ func (v Var) WriteBytesSlice(data []byte, start, count []uint64) error {
 	if err := okDataSlice(v, CHAR, len(data), start, count); err != nil {
 		return err
 	}
-	return newError(C.nc_put_vara_text(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.char)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVars(v.id, int(CHAR), start, count, nil, data)
 }
 
 // ReadBytesSlice reads a slice of variable v into data, which must have enough
@@ -97,11 +98,11 @@ func (v Var) ReadBytesSlice(data []byte, start, count []uint64) error {
 	if err := okDataSlice(v, CHAR, len(data), start, count); err != nil {
 		return err
 	}
-	return newError(C.nc_get_vara_text(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.char)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVars(v.id, int(CHAR), start, count, nil, data)
 }
 
 // WriteBytesStridedSlice writes data as a slice of variable v. The slice is specified by start, count and stride:
@@ -110,12 +111,11 @@ func (v Var) WriteBytesStridedSlice(data []byte, start, count []uint64, stride [
 	if err := okDataStride(v, CHAR, len(data), start, count, stride); err != nil {
 		return err
 	}
-	return newError(C.nc_put_vars_text(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ptrdiff_t)(unsafe.Pointer(&stride[0])),
-		(*C.char)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVars(v.id, int(CHAR), start, count, stride, data)
 }
 
 // ReadBytesStridedSlice reads a strided slice of variable v into data, which must have enough
@@ -125,12 +125,30 @@ func (v Var) ReadBytesStridedSlice(data []byte, start, count []uint64, stride []
 	if err := okDataStride(v, CHAR, len(data), start, count, stride); err != nil {
 		return err
 	}
-	return newError(C.nc_get_vars_text(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ptrdiff_t)(unsafe.Pointer(&stride[0])),
-		(*C.char)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVars(v.id, int(CHAR), start, count, stride, data)
+}
+
+// ReadBytesSlice reads a slice of the variable into data, which must have enough
+// space for all the values. The slice is specified by start and count, as for
+// Var.ReadBytesSlice, and is read in pieces by the workers of r.
+func (r *ParallelReader) ReadBytesSlice(data []byte, start, count []uint64) error {
+	if err := r.check(CHAR, len(data), start, count); err != nil {
+		return err
+	}
+	return r.read(start, count, func(v Var, p slab) error {
+		buf := make([]byte, product(p.count))
+		if err := v.ReadBytesSlice(buf, p.start, p.count); err != nil {
+			return err
+		}
+		slab{start, count}.runs(p, func(dst, src, n int) {
+			copy(data[dst:dst+n], buf[src:src+n])
+		})
+		return nil
+	})
 }
 
 // BytesReader is a interface that allows reading a sequence of values of fixed length.
@@ -262,6 +280,33 @@ func testReadBytesStridedSlice(v Var, n []uint64) error {
 	return nil
 }
 
+// testParallelReadBytesSlice reads data through r and checks that it's the same as what
+// was read directly from the variable. N is v.LenDim().
+// This function is only used for testing.
+func testParallelReadBytesSlice(r *ParallelReader, n []uint64) error {
+	start, count := make([]uint64, len(n)), make([]uint64, len(n))
+	for i, v := range n {
+		start[i] = v / 3
+		count[i] = v - v/3
+	}
+	for _, s := range []slab{{make([]uint64, len(n)), n}, {start, count}} {
+		want := make([]byte, product(s.count))
+		if err := r.Var().ReadBytesSlice(want, s.start, s.count); err != nil {
+			return err
+		}
+		data := make([]byte, product(s.count))
+		if err := r.ReadBytesSlice(data, s.start, s.count); err != nil {
+			return err
+		}
+		for i := range data {
+			if data[i] != want[i] {
+				return fmt.Errorf("parallel slice data at position %d is %v; expected %v", i, data[i], want[i])
+			}
+		}
+	}
+	return nil
+}
+
 func testReadBytesAt(v Var, n uint64) error {
 	data := make([]byte, n)
 	if err := v.ReadBytes(data); err != nil {
@@ -7,21 +7,18 @@
 
 package netcdf
 
-import (
-	"fmt"
-	"unsafe"
-)
-
-// #include <stdlib.h>
-// #include <netcdf.h>
-import "C"
+import "fmt"
 
 // WriteInt32s writes data as the entire data for variable v.
 func (v Var) WriteInt32s(data []int32) error {
 	if err := okData(v, INT, len(data)); err != nil {
 		return err
 	}
-	return newError(C.nc_put_var_int(C.int(v.ds), C.int(v.id), (*C.int)(unsafe.Pointer(&data[0]))))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVar(v.id, int(INT), data)
 }
 
 // ReadInt32s reads the entire variable v into data, which must have enough
@@ -30,17 +27,22 @@ func (v Var) ReadInt32s(data []int32) error {
 	if err := okData(v, INT, len(data)); err != nil {
 		return err
 	}
-	return newError(C.nc_get_var_int(C.int(v.ds), C.int(v.id), (*C.int)(unsafe.Pointer(&data[0]))))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVar(v.id, int(INT), data)
 }
 
 // WriteInt32s sets the value of attribute a to val.
 func (a Attr) WriteInt32s(val []int32) error {
 	// We don't need okData here because netcdf library doesn't know
 	// the length or type of the attribute yet.
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	return newError(C.nc_put_att_int(C.int(a.v.ds), C.int(a.v.id), cname,
-		C.nc_type(INT), C.size_t(len(val)), (*C.int)(unsafe.Pointer(&val[0]))))
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutAttr(a.v.id, a.name, int(INT), val)
 }
 
 // ReadInt32s reads the entire attribute value into val.
@@ -48,33 +50,32 @@ func (a Attr) ReadInt32s(val []int32) (err error) {
 	if err := okData(a, INT, len(val)); err != nil {
 		return err
 	}
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	err = newError(C.nc_get_att_int(C.int(a.v.ds), C.int(a.v.id), cname,
-		(*C.int)(unsafe.Pointer(&val[0]))))
-	return
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.GetAttr(a.v.id, a.name, int(INT), val)
 }
 
 // ReadInt32At returns a value via index position
 func (v Var) ReadInt32At(idx []uint64) (val int32, err error) {
-	var dimPtr *C.size_t
-	if len(idx) > 0 {
-		dimPtr = (*C.size_t)(unsafe.Pointer(&idx[0]))
+	d, err := v.ds.driver()
+	if err != nil {
+		return
 	}
-	err = newError(C.nc_get_var1_int(C.int(v.ds), C.int(v.id),
-		dimPtr, (*C.int)(unsafe.Pointer(&val))))
+	buf := []int32{0}
+	err = d.GetVars(v.id, int(INT), idx, nil, nil, buf)
+	val = buf[0]
 	return
 }
 
 // WriteInt32At sets a value via its index position
 func (v Var) WriteInt32At(idx []uint64, val int32) (err error) {
-	var dimPtr *C.size_t
-	if len(idx) > 0 {
-		dimPtr = (*C.size_t)(unsafe.Pointer(&idx[0]))
+	d, err := v.ds.driver()
+	if err != nil {
+		return
 	}
-	err = newError(C.nc_put_var1_int(C.int(v.ds), C.int(v.id),
-		dimPtr, (*C.int)(unsafe.Pointer(&val))))
-	return
+	return d.PutVars(v.id, int(INT), idx, nil, nil, []int32{val})
 }
 
 // WriteInt32Slice writes data as a slice of variable v. The slice is specified by start and count:
@@ -83,11 +84,11 @@ func (v Var) WriteInt32Slice(data []int32, start, count []uint64) error {
 	if err := okDataSlice(v, INT, len(data), start, count); err != nil {
 		return err
 	}
-	return newError(C.nc_put_vara_int(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.int)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVars(v.id, int(INT), start, count, nil, data)
 }
 
 // ReadInt32Slice reads a slice of variable v into data, which must have enough
@@ -97,11 +98,11 @@ func (v Var) ReadInt32Slice(data []int32, start, count []uint64) error {
 	if err := okDataSlice(v, INT, len(data), start, count); err != nil {
 		return err
 	}
-	return newError(C.nc_get_vara_int(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.int)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVars(v.id, int(INT), start, count, nil, data)
 }
 
 // WriteInt32StridedSlice writes data as a slice of variable v. The slice is specified by start, count and stride:
@@ -110,12 +111,11 @@ func (v Var) WriteInt32StridedSlice(data []int32, start, count []uint64, stride
 	if err := okDataStride(v, INT, len(data), start, count, stride); err != nil {
 		return err
 	}
-	return newError(C.nc_put_vars_int(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ptrdiff_t)(unsafe.Pointer(&stride[0])),
-		(*C.int)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVars(v.id, int(INT), start, count, stride, data)
 }
 
 // ReadInt32StridedSlice reads a strided slice of variable v into data, which must have enough
@@ -125,12 +125,30 @@ func (v Var) ReadInt32StridedSlice(data []int32, start, count []uint64, stride [
 	if err := okDataStride(v, INT, len(data), start, count, stride); err != nil {
 		return err
 	}
-	return newError(C.nc_get_vars_int(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ptrdiff_t)(unsafe.Pointer(&stride[0])),
-		(*C.int)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVars(v.id, int(INT), start, count, stride, data)
+}
+
+// ReadInt32Slice reads a slice of the variable into data, which must have enough
+// space for all the values. The slice is specified by start and count, as for
+// Var.ReadInt32Slice, and is read in pieces by the workers of r.
+func (r *ParallelReader) ReadInt32Slice(data []int32, start, count []uint64) error {
+	if err := r.check(INT, len(data), start, count); err != nil {
+		return err
+	}
+	return r.read(start, count, func(v Var, p slab) error {
+		buf := make([]int32, product(p.count))
+		if err := v.ReadInt32Slice(buf, p.start, p.count); err != nil {
+			return err
+		}
+		slab{start, count}.runs(p, func(dst, src, n int) {
+			copy(data[dst:dst+n], buf[src:src+n])
+		})
+		return nil
+	})
 }
 
 // Int32sReader is a interface that allows reading a sequence of values of fixed length.
@@ -262,6 +280,33 @@ func testReadInt32StridedSlice(v Var, n []uint64) error {
 	return nil
 }
 
+// testParallelReadInt32Slice reads data through r and checks that it's the same as what
+// was read directly from the variable. N is v.LenDim().
+// This function is only used for testing.
+func testParallelReadInt32Slice(r *ParallelReader, n []uint64) error {
+	start, count := make([]uint64, len(n)), make([]uint64, len(n))
+	for i, v := range n {
+		start[i] = v / 3
+		count[i] = v - v/3
+	}
+	for _, s := range []slab{{make([]uint64, len(n)), n}, {start, count}} {
+		want := make([]int32, product(s.count))
+		if err := r.Var().ReadInt32Slice(want, s.start, s.count); err != nil {
+			return err
+		}
+		data := make([]int32, product(s.count))
+		if err := r.ReadInt32Slice(data, s.start, s.count); err != nil {
+			return err
+		}
+		for i := range data {
+			if data[i] != want[i] {
+				return fmt.Errorf("parallel slice data at position %d is %v; expected %v", i, data[i], want[i])
+			}
+		}
+	}
+	return nil
+}
+
 func testReadInt32At(v Var, n uint64) error {
 	data := make([]int32, n)
 	if err := v.ReadInt32s(data); err != nil {
@@ -7,21 +7,18 @@
 
 package netcdf
 
-import (
-	"fmt"
-	"unsafe"
-)
-
-// #include <stdlib.h>
-// #include <netcdf.h>
-import "C"
+import "fmt"
 
 // WriteUint64s writes data as the entire data for variable v.
 func (v Var) WriteUint64s(data []uint64) error {
 	if err := okData(v, UINT64, len(data)); err != nil {
 		return err
 	}
-	return newError(C.nc_put_var_ulonglong(C.int(v.ds), C.int(v.id), (*C.ulonglong)(unsafe.Pointer(&data[0]))))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVar(v.id, int(UINT64), data)
 }
 
 // ReadUint64s reads the entire variable v into data, which must have enough
@@ -30,17 +27,22 @@ func (v Var) ReadUint64s(data []uint64) error {
 	if err := okData(v, UINT64, len(data)); err != nil {
 		return err
 	}
-	return newError(C.nc_get_var_ulonglong(C.int(v.ds), C.int(v.id), (*C.ulonglong)(unsafe.Pointer(&data[0]))))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVar(v.id, int(UINT64), data)
 }
 
 // WriteUint64s sets the value of attribute a to val.
 func (a Attr) WriteUint64s(val []uint64) error {
 	// We don't need okData here because netcdf library doesn't know
 	// the length or type of the attribute yet.
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	return newError(C.nc_put_att_ulonglong(C.int(a.v.ds), C.int(a.v.id), cname,
-		C.nc_type(UINT64), C.size_t(len(val)), (*C.ulonglong)(unsafe.Pointer(&val[0]))))
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutAttr(a.v.id, a.name, int(UINT64), val)
 }
 
 // ReadUint64s reads the entire attribute value into val.
@@ -48,33 +50,32 @@ func (a Attr) ReadUint64s(val []uint64) (err error) {
 	if err := okData(a, UINT64, len(val)); err != nil {
 		return err
 	}
-	cname := C.CString(a.name)
-	defer C.free(unsafe.Pointer(cname))
-	err = newError(C.nc_get_att_ulonglong(C.int(a.v.ds), C.int(a.v.id), cname,
-		(*C.ulonglong)(unsafe.Pointer(&val[0]))))
-	return
+	d, err := a.v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.GetAttr(a.v.id, a.name, int(UINT64), val)
 }
 
 // ReadUint64At returns a value via index position
 func (v Var) ReadUint64At(idx []uint64) (val uint64, err error) {
-	var dimPtr *C.size_t
-	if len(idx) > 0 {
-		dimPtr = (*C.size_t)(unsafe.Pointer(&idx[0]))
+	d, err := v.ds.driver()
+	if err != nil {
+		return
 	}
-	err = newError(C.nc_get_var1_ulonglong(C.int(v.ds), C.int(v.id),
-		dimPtr, (*C.ulonglong)(unsafe.Pointer(&val))))
+	buf := []uint64{0}
+	err = d.GetVars(v.id, int(UINT64), idx, nil, nil, buf)
+	val = buf[0]
 	return
 }
 
 // WriteUint64At sets a value via its index position
 func (v Var) WriteUint64At(idx []uint64, val uint64) (err error) {
-	var dimPtr *C.size_t
-	if len(idx) > 0 {
-		dimPtr = (*C.size_t)(unsafe.Pointer(&idx[0]))
+	d, err := v.ds.driver()
+	if err != nil {
+		return
 	}
-	err = newError(C.nc_put_var1_ulonglong(C.int(v.ds), C.int(v.id),
-		dimPtr, (*C.ulonglong)(unsafe.Pointer(&val))))
-	return
+	return d.PutVars(v.id, int(UINT64), idx, nil, nil, []uint64{val})
 }
 
 // WriteUint64Slice writes data as a slice of variable v. The slice is specified by start and count:
@@ -83,11 +84,11 @@ func (v Var) WriteUint64Slice(data []uint64, start, count []uint64) error {
 	if err := okDataSlice(v, UINT64, len(data), start, count); err != nil {
 		return err
 	}
-	return newError(C.nc_put_vara_ulonglong(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ulonglong)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVars(v.id, int(UINT64), start, count, nil, data)
 }
 
 // ReadUint64Slice reads a slice of variable v into data, which must have enough
@@ -97,11 +98,11 @@ func (v Var) ReadUint64Slice(data []uint64, start, count []uint64) error {
 	if err := okDataSlice(v, UINT64, len(data), start, count); err != nil {
 		return err
 	}
-	return newError(C.nc_get_vara_ulonglong(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ulonglong)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVars(v.id, int(UINT64), start, count, nil, data)
 }
 
 // WriteUint64StridedSlice writes data as a slice of variable v. The slice is specified by start, count and stride:
@@ -110,12 +111,11 @@ func (v Var) WriteUint64StridedSlice(data []uint64, start, count []uint64, strid
 	if err := okDataStride(v, UINT64, len(data), start, count, stride); err != nil {
 		return err
 	}
-	return newError(C.nc_put_vars_ulonglong(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ptrdiff_t)(unsafe.Pointer(&stride[0])),
-		(*C.ulonglong)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.PutVars(v.id, int(UINT64), start, count, stride, data)
 }
 
 // ReadUint64StridedSlice reads a strided slice of variable v into data, which must have enough
@@ -125,12 +125,30 @@ func (v Var) ReadUint64StridedSlice(data []uint64, start, count []uint64, stride
 	if err := okDataStride(v, UINT64, len(data), start, count, stride); err != nil {
 		return err
 	}
-	return newError(C.nc_get_vars_ulonglong(C.int(v.ds), C.int(v.id),
-		(*C.size_t)(unsafe.Pointer(&start[0])),
-		(*C.size_t)(unsafe.Pointer(&count[0])),
-		(*C.ptrdiff_t)(unsafe.Pointer(&stride[0])),
-		(*C.ulonglong)(unsafe.Pointer(&data[0])),
-	))
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.GetVars(v.id, int(UINT64), start, count, stride, data)
+}
+
+// ReadUint64Slice reads a slice of the variable into data, which must have enough
+// space for all the values. The slice is specified by start and count, as for
+// Var.ReadUint64Slice, and is read in pieces by the workers of r.
+func (r *ParallelReader) ReadUint64Slice(data []uint64, start, count []uint64) error {
+	if err := r.check(UINT64, len(data), start, count); err != nil {
+		return err
+	}
+	return r.read(start, count, func(v Var, p slab) error {
+		buf := make([]uint64, product(p.count))
+		if err := v.ReadUint64Slice(buf, p.start, p.count); err != nil {
+			return err
+		}
+		slab{start, count}.runs(p, func(dst, src, n int) {
+			copy(data[dst:dst+n], buf[src:src+n])
+		})
+		return nil
+	})
 }
 
 // Uint64sReader is a interface that allows reading a sequence of values of fixed length.
@@ -262,6 +280,33 @@ func testReadUint64StridedSlice(v Var, n []uint64) error {
 	return nil
 }
 
+// testParallelReadUint64Slice reads data through r and checks that it's the same as what
+// was read directly from the variable. N is v.LenDim().
+// This function is only used for testing.
+func testParallelReadUint64Slice(r *ParallelReader, n []uint64) error {
+	start, count := make([]uint64, len(n)), make([]uint64, len(n))
+	for i, v := range n {
+		start[i] = v / 3
+		count[i] = v - v/3
+	}
+	for _, s := range []slab{{make([]uint64, len(n)), n}, {start, count}} {
+		want := make([]uint64, product(s.count))
+		if err := r.Var().ReadUint64Slice(want, s.start, s.count); err != nil {
+			return err
+		}
+		data := make([]uint64, product(s.count))
+		if err := r.ReadUint64Slice(data, s.start, s.count); err != nil {
+			return err
+		}
+		for i := range data {
+			if data[i] != want[i] {
+				return fmt.Errorf("parallel slice data at position %d is %v; expected %v", i, data[i], want[i])
+			}
+		}
+	}
+	return nil
+}
+
 func testReadUint64At(v Var, n uint64) error {
 	data := make([]uint64, n)
 	if err := v.ReadUint64s(data); err != nil {
@@ -0,0 +1,466 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package ncarrow reads netCDF variables as Apache Arrow record batches,
+// which can be written with the IPC and Parquet writers of Arrow.
+//
+// The variables are flattened into the rows of a table like
+// netcdf.ExportTable does: the rows span the leading dimensions shared by
+// all the variables, the row dimensions, in row-major order. Each record
+// starts with a column for each row dimension, holding the values of its
+// coordinate variable, or the index along the dimension as a uint64 if it
+// has none. Then comes a column for each variable. A variable with
+// dimensions after the row dimensions is a column of fixed size lists
+// holding those values in row-major order.
+//
+// Records are chunked along the outer row dimension. Values are read from
+// the variables straight into the buffers of the Arrow arrays, without
+// copying each value, except for times and coordinates.
+//
+// Types are mapped by DataType. The values of CHAR variables are fixed
+// size binary strings, whose width is the length of their last dimension,
+// with any trailing NUL characters. The values of variables with CF time
+// units, as read by netcdf.Var.TimeUnits, are decoded into timestamps in
+// microseconds. Values are as stored: fill values and NaN aren't nulls.
+package ncarrow
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/apache/arrow/go/v11/arrow"
+	"github.com/apache/arrow/go/v11/arrow/array"
+	"github.com/apache/arrow/go/v11/arrow/memory"
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+// DataType returns the Arrow type of values of netCDF type t. CHAR values
+// are fixed size binary strings of width 1, as for variables whose strings
+// have length 1.
+func DataType(t netcdf.Type) (arrow.DataType, error) {
+	switch t {
+	case netcdf.BYTE:
+		return arrow.PrimitiveTypes.Int8, nil
+	case netcdf.CHAR:
+		return &arrow.FixedSizeBinaryType{ByteWidth: 1}, nil
+	case netcdf.SHORT:
+		return arrow.PrimitiveTypes.Int16, nil
+	case netcdf.INT:
+		return arrow.PrimitiveTypes.Int32, nil
+	case netcdf.FLOAT:
+		return arrow.PrimitiveTypes.Float32, nil
+	case netcdf.DOUBLE:
+		return arrow.PrimitiveTypes.Float64, nil
+	case netcdf.UBYTE:
+		return arrow.PrimitiveTypes.Uint8, nil
+	case netcdf.USHORT:
+		return arrow.PrimitiveTypes.Uint16, nil
+	case netcdf.UINT:
+		return arrow.PrimitiveTypes.Uint32, nil
+	case netcdf.INT64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case netcdf.UINT64:
+		return arrow.PrimitiveTypes.Uint64, nil
+	}
+	return nil, fmt.Errorf("ncarrow: unsupported type %v", t)
+}
+
+// Options controls NewReader. A nil *Options uses the defaults.
+type Options struct {
+	// BatchLen is the number of indexes along the outer row dimension
+	// in each record. It defaults to 1.
+	BatchLen int
+
+	// RawTimes reads the values of time variables as numbers, instead of
+	// decoding them into timestamps.
+	RawTimes bool
+}
+
+// column is a column of the records.
+type column struct {
+	v      netcdf.Var
+	t      netcdf.Type
+	field  arrow.Field
+	leaf   arrow.DataType    // type of the values
+	size   int               // size of a value of leaf, in bytes
+	inner  []uint64          // shape of the dimensions after the row dimensions
+	str    uint64            // length of strings, for CHAR
+	units  *netcdf.TimeUnits // encoding of times, if decoded
+	strDim bool              // whether the last dimension is the length of strings
+
+	// Coordinate columns hold the values along their row dimension.
+	coord []byte
+}
+
+// Reader reads records from variables of a dataset. It implements
+// array.RecordReader.
+type Reader struct {
+	refs     int64
+	schema   *arrow.Schema
+	coords   []*column
+	vars     []*column
+	rowShape []uint64
+	batchLen uint64
+	next     uint64 // next index along the outer row dimension
+	done     bool
+	rec      arrow.Record
+	err      error
+}
+
+// NewReader returns a reader of the variables of ds named vars, as
+// described in the package documentation. Opts may be nil for the
+// defaults.
+func NewReader(ds netcdf.Dataset, vars []string, opts *Options) (*Reader, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if len(vars) == 0 {
+		return nil, fmt.Errorf("ncarrow: no variables")
+	}
+	if opts.BatchLen < 0 {
+		return nil, fmt.Errorf("ncarrow: negative batch length %d", opts.BatchLen)
+	}
+	r := &Reader{refs: 1, batchLen: uint64(opts.BatchLen)}
+	if r.batchLen == 0 {
+		r.batchLen = 1
+	}
+	var rowDims []string
+	for i, name := range vars {
+		v, err := ds.Var(name)
+		if err != nil {
+			return nil, fmt.Errorf("ncarrow: variable %q: %v", name, err)
+		}
+		c, dims, err := newColumn(v, name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("ncarrow: variable %q: %v", name, err)
+		}
+		if i == 0 {
+			rowDims = dims
+		}
+		n := 0
+		for n < len(rowDims) && n < len(dims) && rowDims[n] == dims[n] {
+			n++
+		}
+		rowDims = rowDims[:n]
+		r.vars = append(r.vars, c)
+	}
+
+	var fields []arrow.Field
+	r.rowShape = make([]uint64, len(rowDims))
+	for k, dim := range rowDims {
+		d, err := ds.Dim(dim)
+		if err != nil {
+			return nil, err
+		}
+		if r.rowShape[k], err = d.Len(); err != nil {
+			return nil, err
+		}
+		c, err := newCoord(ds, dim, r.rowShape[k], opts)
+		if err != nil {
+			return nil, fmt.Errorf("ncarrow: dimension %q: %v", dim, err)
+		}
+		r.coords = append(r.coords, c)
+		fields = append(fields, c.field)
+	}
+	all := r.vars
+	r.vars = r.vars[:0]
+	for _, c := range all {
+		if len(rowDims) == 1 && c.field.Name == rowDims[0] && r.coords[0].v == c.v {
+			// Already a coordinate column.
+			continue
+		}
+		c.inner = c.inner[len(rowDims):]
+		if len(c.inner) > 0 {
+			c.field.Type = arrow.FixedSizeListOfNonNullable(int32(product(c.inner)), c.leaf)
+		}
+		r.vars = append(r.vars, c)
+		fields = append(fields, c.field)
+	}
+	r.schema = arrow.NewSchema(fields, nil)
+	return r, nil
+}
+
+// newColumn returns the column of variable v, named name, and the names
+// of its dimensions, without the length of strings for CHAR.
+func newColumn(v netcdf.Var, name string, opts *Options) (*column, []string, error) {
+	c := &column{v: v, str: 1}
+	var err error
+	if c.t, err = v.Type(); err != nil {
+		return nil, nil, err
+	}
+	if c.leaf, err = DataType(c.t); err != nil {
+		return nil, nil, err
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.inner, err = v.LenDims(); err != nil {
+		return nil, nil, err
+	}
+	if c.t == netcdf.CHAR && len(dims) > 0 {
+		c.str, c.strDim = c.inner[len(dims)-1], true
+		dims, c.inner = dims[:len(dims)-1], c.inner[:len(dims)-1]
+		c.leaf = &arrow.FixedSizeBinaryType{ByteWidth: int(c.str)}
+	}
+	names := make([]string, len(dims))
+	for i, d := range dims {
+		if names[i], err = d.Name(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.t != netcdf.CHAR && !opts.RawTimes {
+		if u, err := v.TimeUnits(); err == nil {
+			c.units = &u
+			c.leaf = arrow.FixedWidthTypes.Timestamp_us
+		}
+	}
+	c.size = c.leaf.(arrow.FixedWidthDataType).BitWidth() / 8
+	md, err := metadata(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.field = arrow.Field{Name: name, Type: c.leaf, Metadata: md}
+	return c, names, nil
+}
+
+// metadata returns the CHAR attributes of v.
+func metadata(v netcdf.Var) (arrow.Metadata, error) {
+	n, err := v.NAttrs()
+	if err != nil {
+		return arrow.Metadata{}, err
+	}
+	var keys, values []string
+	for i := 0; i < n; i++ {
+		a, err := v.AttrN(i)
+		if err != nil {
+			return arrow.Metadata{}, err
+		}
+		if t, err := a.Type(); err != nil {
+			return arrow.Metadata{}, err
+		} else if t != netcdf.CHAR {
+			continue
+		}
+		b, err := netcdf.GetBytes(a)
+		if err != nil {
+			return arrow.Metadata{}, err
+		}
+		keys = append(keys, a.Name())
+		values = append(values, string(b))
+	}
+	return arrow.NewMetadata(keys, values), nil
+}
+
+// newCoord returns the coordinate column of dimension dim of length n,
+// holding the values of its coordinate variable or indexes.
+func newCoord(ds netcdf.Dataset, dim string, n uint64, opts *Options) (*column, error) {
+	cv, err := ds.CoordVar(dim)
+	if err == nil {
+		c, _, err := newColumn(cv, dim, opts)
+		if err != nil {
+			return nil, err
+		}
+		// CHAR coordinate variables hold strings along another
+		// dimension, so they aren't coordinates of rows.
+		if !c.strDim {
+			c.coord, err = read(c, []uint64{0}, []uint64{n})
+			c.inner = nil
+			return c, err
+		}
+	} else if _, err := ds.Var(dim); err != nil && err != netcdf.ENOTVAR {
+		return nil, err
+	}
+	index := make([]uint64, n)
+	for i := range index {
+		index[i] = uint64(i)
+	}
+	return &column{
+		field: arrow.Field{Name: dim, Type: arrow.PrimitiveTypes.Uint64},
+		leaf:  arrow.PrimitiveTypes.Uint64,
+		size:  8,
+		coord: bytesOf(index),
+	}, nil
+}
+
+// Schema returns the schema of the records.
+func (r *Reader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next reads the next record, and reports whether there's one. The
+// previous record is released. Err returns the error that stopped the
+// reader, if any.
+func (r *Reader) Next() bool {
+	if r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+	if r.done || r.err != nil {
+		return false
+	}
+	start := make([]uint64, len(r.rowShape))
+	count := append([]uint64(nil), r.rowShape...)
+	r.done = true
+	if len(count) > 0 {
+		if r.next >= count[0] {
+			return false
+		}
+		start[0] = r.next
+		if count[0]-r.next > r.batchLen {
+			count[0] = r.batchLen
+		} else {
+			count[0] -= r.next
+		}
+		r.next += count[0]
+		r.done = r.next >= r.rowShape[0]
+	}
+	rows := product(count)
+
+	var cols []arrow.Array
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+	bufs := make([][]byte, len(r.coords))
+	for k, c := range r.coords {
+		bufs[k] = make([]byte, rows*uint64(c.size))
+	}
+	idx := append([]uint64(nil), start...)
+	for row := uint64(0); row < rows; row++ {
+		for k, c := range r.coords {
+			size := uint64(c.size)
+			copy(bufs[k][row*size:(row+1)*size], c.coord[idx[k]*size:])
+		}
+		// Move to the next row.
+		for k := len(idx) - 1; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < start[k]+count[k] {
+				break
+			}
+			idx[k] = start[k]
+		}
+	}
+	for k, c := range r.coords {
+		cols = append(cols, c.array(int(rows), bufs[k]))
+	}
+	for _, c := range r.vars {
+		b, err := read(c, append(append([]uint64(nil), start...), make([]uint64, len(c.inner))...), append(append([]uint64(nil), count...), c.inner...))
+		if err != nil {
+			r.err = fmt.Errorf("ncarrow: variable %q: %v", c.field.Name, err)
+			return false
+		}
+		cols = append(cols, c.array(int(rows), b))
+	}
+	r.rec = array.NewRecord(r.schema, cols, int64(rows))
+	return true
+}
+
+// Record returns the record read by Next. It's only valid until the next
+// call to Next or Release; call its Retain method to keep it longer.
+func (r *Reader) Record() arrow.Record {
+	return r.rec
+}
+
+// Err returns the error that stopped the reader, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Retain increases the reference count of r by 1.
+func (r *Reader) Retain() {
+	atomic.AddInt64(&r.refs, 1)
+}
+
+// Release decreases the reference count of r by 1. When it reaches 0, the
+// current record is released.
+func (r *Reader) Release() {
+	if atomic.AddInt64(&r.refs, -1) == 0 && r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+}
+
+// array returns the array of c for the given number of rows, whose values
+// are in b.
+func (c *column) array(rows int, b []byte) arrow.Array {
+	n := rows * int(product(c.inner))
+	data := array.NewData(c.leaf, n, []*memory.Buffer{nil, memory.NewBufferBytes(b)}, nil, 0, 0)
+	if len(c.inner) > 0 {
+		list := array.NewData(c.field.Type, rows, []*memory.Buffer{nil}, []arrow.ArrayData{data}, 0, 0)
+		data.Release()
+		data = list
+	}
+	defer data.Release()
+	return array.MakeFromData(data)
+}
+
+// read reads the hyperslab of the variable of c specified by start and
+// count, without the dimension of strings for CHAR, and returns the bytes
+// of its values.
+func read(c *column, start, count []uint64) ([]byte, error) {
+	if c.strDim {
+		start, count = append(start, 0), append(count, c.str)
+	}
+	switch c.t {
+	case netcdf.BYTE:
+		return readAs[int8](c, start, count)
+	case netcdf.CHAR:
+		data := make([]byte, product(count))
+		_, err := c.v.ReadSliceCtx(context.Background(), data, start, count, nil)
+		return data, err
+	case netcdf.SHORT:
+		return readAs[int16](c, start, count)
+	case netcdf.INT:
+		return readAs[int32](c, start, count)
+	case netcdf.FLOAT:
+		return readAs[float32](c, start, count)
+	case netcdf.DOUBLE:
+		return readAs[float64](c, start, count)
+	case netcdf.UBYTE:
+		return readAs[uint8](c, start, count)
+	case netcdf.USHORT:
+		return readAs[uint16](c, start, count)
+	case netcdf.UINT:
+		return readAs[uint32](c, start, count)
+	case netcdf.INT64:
+		return readAs[int64](c, start, count)
+	case netcdf.UINT64:
+		return readAs[uint64](c, start, count)
+	}
+	return nil, fmt.Errorf("unsupported type %v", c.t)
+}
+
+func readAs[T netcdf.Number](c *column, start, count []uint64) ([]byte, error) {
+	data := make([]T, product(count))
+	if _, err := c.v.ReadSliceCtx(context.Background(), data, start, count, nil); err != nil {
+		return nil, err
+	}
+	if c.units == nil {
+		return bytesOf(data), nil
+	}
+	us := make([]int64, len(data))
+	for i, x := range data {
+		us[i] = c.units.Time(float64(x)).UnixMicro()
+	}
+	return bytesOf(us), nil
+}
+
+// bytesOf returns the memory holding the values of data.
+func bytesOf[T netcdf.Number](data []T) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&data[0])), len(data)*int(unsafe.Sizeof(data[0])))
+}
+
+func product(nums []uint64) uint64 {
+	prod := uint64(1)
+	for _, n := range nums {
+		prod *= n
+	}
+	return prod
+}
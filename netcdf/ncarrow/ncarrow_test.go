@@ -0,0 +1,172 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ncarrow
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v11/arrow"
+	"github.com/apache/arrow/go/v11/arrow/array"
+	"github.com/apache/arrow/go/v11/arrow/ipc"
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+func create(t *testing.T) netcdf.Dataset {
+	return netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "station", Len: 2}, {Name: "level", Len: 2}, {Name: "name_len", Len: 3}},
+		Vars: []netcdftest.Var{
+			{Name: "time", Dims: []string{"time"}, Data: []float64{0, 0.5, 1},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "days since 2020-01-01"}}},
+			{Name: "temp", Dims: []string{"time", "station"}, Data: []float32{1.5, 2, float32(math.NaN()), 4, 5, 6},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "K"}, {Name: "scale", Value: 2.0}}},
+			{Name: "flag", Dims: []string{"time", "station", "level"}, Data: []int16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}},
+			{Name: "code", Type: netcdf.CHAR, Dims: []string{"time", "station", "name_len"}, Data: []byte("ab\x00cdefghijklmnopq")},
+		},
+	})
+}
+
+func TestDataType(t *testing.T) {
+	for typ, want := range map[netcdf.Type]arrow.DataType{
+		netcdf.BYTE:   arrow.PrimitiveTypes.Int8,
+		netcdf.UINT64: arrow.PrimitiveTypes.Uint64,
+		netcdf.DOUBLE: arrow.PrimitiveTypes.Float64,
+		netcdf.CHAR:   &arrow.FixedSizeBinaryType{ByteWidth: 1},
+	} {
+		got, err := DataType(typ)
+		if err != nil {
+			t.Fatalf("DataType(%v) failed: %v\n", typ, err)
+		}
+		if !arrow.TypeEqual(got, want) {
+			t.Errorf("DataType(%v) is %v; want %v\n", typ, got, want)
+		}
+	}
+	if _, err := DataType(netcdf.STRING); err == nil {
+		t.Errorf("DataType(STRING) succeeded\n")
+	}
+}
+
+func TestReader(t *testing.T) {
+	ds := create(t)
+	defer ds.Close()
+	r, err := NewReader(ds, []string{"temp", "flag", "code"}, &Options{BatchLen: 2})
+	if err != nil {
+		t.Fatalf("NewReader failed: %v\n", err)
+	}
+	defer r.Release()
+
+	want := "schema:\n  fields: 5\n" +
+		"    - time: type=timestamp[us, tz=UTC]\n" +
+		"      metadata: [\"units\": \"days since 2020-01-01\"]\n" +
+		"    - station: type=uint64\n" +
+		"    - temp: type=float32\n" +
+		"      metadata: [\"units\": \"K\"]\n" +
+		"    - flag: type=fixed_size_list<item: int16>[2]\n" +
+		"    - code: type=fixed_size_binary[3]"
+	if got := r.Schema().String(); got != want {
+		t.Errorf("schema is\n%s\nwant\n%s\n", got, want)
+	}
+
+	var (
+		times    []time.Time
+		stations []uint64
+		temps    []float32
+		flags    []int16
+		codes    []string
+		lens     []int64
+	)
+	for r.Next() {
+		rec := r.Record()
+		lens = append(lens, rec.NumRows())
+		for _, ts := range rec.Column(0).(*array.Timestamp).TimestampValues() {
+			times = append(times, ts.ToTime(arrow.Microsecond))
+		}
+		stations = append(stations, rec.Column(1).(*array.Uint64).Uint64Values()...)
+		temps = append(temps, rec.Column(2).(*array.Float32).Float32Values()...)
+		list := rec.Column(3).(*array.FixedSizeList)
+		flags = append(flags, list.ListValues().(*array.Int16).Int16Values()...)
+		code := rec.Column(4).(*array.FixedSizeBinary)
+		for i := 0; i < code.Len(); i++ {
+			codes = append(codes, string(code.Value(i)))
+		}
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("Next failed: %v\n", err)
+	}
+	if want := []int64{4, 2}; !reflect.DeepEqual(lens, want) {
+		t.Errorf("records have %v rows; want %v\n", lens, want)
+	}
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if want := []time.Time{t0, t0, t0.Add(12 * time.Hour), t0.Add(12 * time.Hour), t0.Add(24 * time.Hour), t0.Add(24 * time.Hour)}; !reflect.DeepEqual(times, want) {
+		t.Errorf("times are %v; want %v\n", times, want)
+	}
+	if want := []uint64{0, 1, 0, 1, 0, 1}; !reflect.DeepEqual(stations, want) {
+		t.Errorf("stations are %v; want %v\n", stations, want)
+	}
+	if len(temps) != 6 || temps[0] != 1.5 || !math.IsNaN(float64(temps[2])) || temps[5] != 6 {
+		t.Errorf("temps are %v\n", temps)
+	}
+	if want := []int16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}; !reflect.DeepEqual(flags, want) {
+		t.Errorf("flags are %v; want %v\n", flags, want)
+	}
+	if want := []string{"ab\x00", "cde", "fgh", "ijk", "lmn", "opq"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("codes are %q; want %q\n", codes, want)
+	}
+	if r.Next() {
+		t.Errorf("Next succeeded after the last record\n")
+	}
+}
+
+func TestReaderIPC(t *testing.T) {
+	ds := create(t)
+	defer ds.Close()
+	r, err := NewReader(ds, []string{"temp", "flag"}, &Options{BatchLen: 2})
+	if err != nil {
+		t.Fatalf("NewReader failed: %v\n", err)
+	}
+	defer r.Release()
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(r.Schema()))
+	for r.Next() {
+		if err := w.Write(r.Record()); err != nil {
+			t.Fatalf("Write failed: %v\n", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+
+	ir, err := ipc.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("ipc.NewReader failed: %v\n", err)
+	}
+	defer ir.Release()
+	var flags []int16
+	for ir.Next() {
+		list := ir.Record().Column(3).(*array.FixedSizeList)
+		flags = append(flags, list.ListValues().(*array.Int16).Int16Values()...)
+	}
+	if want := []int16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}; !reflect.DeepEqual(flags, want) {
+		t.Errorf("flags are %v; want %v\n", flags, want)
+	}
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	ds := create(t)
+	defer ds.Close()
+	for _, vars := range [][]string{nil, {"nope"}} {
+		if _, err := NewReader(ds, vars, nil); err == nil {
+			t.Errorf("NewReader(%v) succeeded\n", vars)
+		}
+	}
+	if _, err := NewReader(ds, []string{"temp"}, &Options{BatchLen: -1}); err == nil {
+		t.Errorf("NewReader with a negative batch length succeeded\n")
+	}
+}
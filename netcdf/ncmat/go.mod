@@ -0,0 +1,10 @@
+module github.com/fhs/go-netcdf/netcdf/ncmat
+
+go 1.18
+
+require (
+	github.com/fhs/go-netcdf v0.0.0-00010101000000-000000000000
+	gonum.org/v1/gonum v0.12.0
+)
+
+replace github.com/fhs/go-netcdf => ../..
@@ -0,0 +1,311 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package ncmat reads and writes netCDF variables as gonum matrices and
+// vectors.
+//
+// Matrices are read from and written to 2-D hyperslabs of variables, given
+// by start and count as for ReadFloat64Slice. The rows of the matrix are
+// along the first dimension of the hyperslab and the columns along the
+// second one, so element (i, j) of the matrix is the value at index
+// (start[0]+i, start[1]+j) of a 2-D variable. For variables with more
+// dimensions, count must be 1 along all dimensions except two, which are
+// the rows and the columns in the order of the variable's dimensions. If
+// count is 1 along all dimensions except one, the hyperslab is a row vector
+// if that's the last dimension, and a column vector otherwise, as it is for
+// 2-D variables. To use the other orientation, read the matrix and take its
+// transpose with the T method.
+//
+// Variables of types other than DOUBLE are converted. When writing to
+// integer variables, values are rounded to the nearest integer, and values
+// that can't be represented by the type of the variable are reported as
+// errors. CHAR variables aren't supported.
+package ncmat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadDense reads the entire 2-D variable v into a new matrix.
+func ReadDense(v netcdf.Var) (*mat.Dense, error) {
+	shape, err := v.LenDims()
+	if err != nil {
+		return nil, err
+	}
+	if len(shape) != 2 {
+		return nil, fmt.Errorf("ncmat: variable has %d dimensions; expected 2", len(shape))
+	}
+	return ReadDenseSlice(v, []uint64{0, 0}, shape)
+}
+
+// ReadDenseSlice reads the 2-D hyperslab of variable v specified by start
+// and count into a new matrix.
+func ReadDenseSlice(v netcdf.Var, start, count []uint64) (*mat.Dense, error) {
+	r, c, err := matrixShape(count)
+	if err != nil {
+		return nil, err
+	}
+	if r == 0 || c == 0 {
+		return nil, fmt.Errorf("ncmat: empty hyperslab of shape %v", count)
+	}
+	data, err := read(v, start, count)
+	if err != nil {
+		return nil, err
+	}
+	return mat.NewDense(r, c, data), nil
+}
+
+// ReadVecDense reads the entire 1-D variable v into a new vector.
+func ReadVecDense(v netcdf.Var) (*mat.VecDense, error) {
+	shape, err := v.LenDims()
+	if err != nil {
+		return nil, err
+	}
+	if len(shape) != 1 {
+		return nil, fmt.Errorf("ncmat: variable has %d dimensions; expected 1", len(shape))
+	}
+	return ReadVecDenseSlice(v, []uint64{0}, shape)
+}
+
+// ReadVecDenseSlice reads the 1-D hyperslab of variable v specified by start
+// and count into a new vector. Count must be 1 along all dimensions except
+// one, unless v has one dimension.
+func ReadVecDenseSlice(v netcdf.Var, start, count []uint64) (*mat.VecDense, error) {
+	n, err := vectorLen(count)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("ncmat: empty hyperslab of shape %v", count)
+	}
+	data, err := read(v, start, count)
+	if err != nil {
+		return nil, err
+	}
+	return mat.NewVecDense(n, data), nil
+}
+
+// WriteMatrix writes m as the entire data of the 2-D variable v, which must
+// have the shape of m.
+func WriteMatrix(v netcdf.Var, m mat.Matrix) error {
+	shape, err := v.LenDims()
+	if err != nil {
+		return err
+	}
+	if len(shape) != 2 {
+		return fmt.Errorf("ncmat: variable has %d dimensions; expected 2", len(shape))
+	}
+	return WriteMatrixSlice(v, m, []uint64{0, 0}, shape)
+}
+
+// WriteMatrixSlice writes m to the 2-D hyperslab of variable v specified by
+// start and count, which must have the shape of m.
+func WriteMatrixSlice(v netcdf.Var, m mat.Matrix, start, count []uint64) error {
+	r, c, err := matrixShape(count)
+	if err != nil {
+		return err
+	}
+	if mr, mc := m.Dims(); mr != r || mc != c {
+		return fmt.Errorf("ncmat: %d×%d matrix for hyperslab of shape %v", mr, mc, count)
+	}
+	data := make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data = append(data, m.At(i, j))
+		}
+	}
+	return write(v, data, start, count)
+}
+
+// WriteVector writes x as the entire data of the 1-D variable v, which must
+// have the length of x.
+func WriteVector(v netcdf.Var, x mat.Vector) error {
+	shape, err := v.LenDims()
+	if err != nil {
+		return err
+	}
+	if len(shape) != 1 {
+		return fmt.Errorf("ncmat: variable has %d dimensions; expected 1", len(shape))
+	}
+	return WriteVectorSlice(v, x, []uint64{0}, shape)
+}
+
+// WriteVectorSlice writes x to the 1-D hyperslab of variable v specified by
+// start and count, which must have the length of x.
+func WriteVectorSlice(v netcdf.Var, x mat.Vector, start, count []uint64) error {
+	n, err := vectorLen(count)
+	if err != nil {
+		return err
+	}
+	if n != x.Len() {
+		return fmt.Errorf("ncmat: vector of length %d for hyperslab of shape %v", x.Len(), count)
+	}
+	data := make([]float64, n)
+	for i := range data {
+		data[i] = x.AtVec(i)
+	}
+	return write(v, data, start, count)
+}
+
+// matrixShape returns the number of rows and columns of the 2-D hyperslab
+// of shape count. Count has at least two dimensions, and at most two of
+// them aren't 1. A single one that isn't 1 gives a row vector if it's the
+// last dimension, and a column vector otherwise, so [1 1 5] is 1×5 as [1 5]
+// is, and [1 5 1] is 5×1 as [5 1] is.
+func matrixShape(count []uint64) (r, c int, err error) {
+	if len(count) < 2 {
+		return 0, 0, fmt.Errorf("ncmat: hyperslab of shape %v isn't 2-D", count)
+	}
+	var dims []int // dimensions that aren't 1
+	for i, n := range count {
+		if n != 1 {
+			dims = append(dims, i)
+		}
+	}
+	switch len(dims) {
+	case 0:
+		return 1, 1, nil
+	case 1:
+		if i := dims[0]; i == len(count)-1 {
+			return 1, int(count[i]), nil
+		}
+		return int(count[dims[0]]), 1, nil
+	case 2:
+		return int(count[dims[0]]), int(count[dims[1]]), nil
+	}
+	return 0, 0, fmt.Errorf("ncmat: hyperslab of shape %v isn't 2-D", count)
+}
+
+// vectorLen returns the length of the 1-D hyperslab of shape count.
+func vectorLen(count []uint64) (int, error) {
+	if len(count) == 1 {
+		return int(count[0]), nil
+	}
+	n := -1
+	for _, m := range count {
+		if m != 1 {
+			if n >= 0 {
+				return 0, fmt.Errorf("ncmat: hyperslab of shape %v isn't 1-D", count)
+			}
+			n = int(m)
+		}
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("ncmat: hyperslab of shape %v isn't 1-D", count)
+	}
+	return n, nil
+}
+
+// read returns the values of the hyperslab of v specified by start and
+// count, converted to float64.
+func read(v netcdf.Var, start, count []uint64) ([]float64, error) {
+	t, err := v.Type()
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case netcdf.DOUBLE:
+		return readAs[float64](v, start, count)
+	case netcdf.FLOAT:
+		return readAs[float32](v, start, count)
+	case netcdf.BYTE:
+		return readAs[int8](v, start, count)
+	case netcdf.UBYTE:
+		return readAs[uint8](v, start, count)
+	case netcdf.SHORT:
+		return readAs[int16](v, start, count)
+	case netcdf.USHORT:
+		return readAs[uint16](v, start, count)
+	case netcdf.INT:
+		return readAs[int32](v, start, count)
+	case netcdf.UINT:
+		return readAs[uint32](v, start, count)
+	case netcdf.INT64:
+		return readAs[int64](v, start, count)
+	case netcdf.UINT64:
+		return readAs[uint64](v, start, count)
+	}
+	return nil, fmt.Errorf("ncmat: unsupported variable type %v", t)
+}
+
+func readAs[T netcdf.Number](v netcdf.Var, start, count []uint64) ([]float64, error) {
+	a, err := netcdf.ReadArraySlice[T](v, start, count)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]float64, 0, a.Len())
+	a.Each(func(_ []int, val T) {
+		data = append(data, float64(val))
+	})
+	return data, nil
+}
+
+// write writes data, converted to the type of v, to the hyperslab of v
+// specified by start and count.
+func write(v netcdf.Var, data []float64, start, count []uint64) error {
+	t, err := v.Type()
+	if err != nil {
+		return err
+	}
+	switch t {
+	case netcdf.DOUBLE:
+		return writeAs(v, data, start, count, func(x float64) (float64, bool) { return x, true })
+	case netcdf.FLOAT:
+		return writeAs(v, data, start, count, func(x float64) (float32, bool) { return float32(x), true })
+	case netcdf.BYTE:
+		return writeAs(v, data, start, count, toInt[int8](math.MinInt8, math.MaxInt8+1))
+	case netcdf.UBYTE:
+		return writeAs(v, data, start, count, toInt[uint8](0, math.MaxUint8+1))
+	case netcdf.SHORT:
+		return writeAs(v, data, start, count, toInt[int16](math.MinInt16, math.MaxInt16+1))
+	case netcdf.USHORT:
+		return writeAs(v, data, start, count, toInt[uint16](0, math.MaxUint16+1))
+	case netcdf.INT:
+		return writeAs(v, data, start, count, toInt[int32](math.MinInt32, math.MaxInt32+1))
+	case netcdf.UINT:
+		return writeAs(v, data, start, count, toInt[uint32](0, math.MaxUint32+1))
+	case netcdf.INT64:
+		return writeAs(v, data, start, count, toInt[int64](math.MinInt64, math.MaxInt64+1))
+	case netcdf.UINT64:
+		return writeAs(v, data, start, count, toInt[uint64](0, math.MaxUint64+1))
+	}
+	return fmt.Errorf("ncmat: unsupported variable type %v", t)
+}
+
+// toInt returns a function converting values to the integer type T, whose
+// values are in [min, limit). The conversion fails for values outside of
+// that range, after rounding.
+func toInt[T netcdf.Number](min, limit float64) func(x float64) (T, bool) {
+	return func(x float64) (T, bool) {
+		x = math.Round(x)
+		if math.IsNaN(x) || x < min || x >= limit {
+			return 0, false
+		}
+		return T(x), true
+	}
+}
+
+func writeAs[T netcdf.Number](v netcdf.Var, data []float64, start, count []uint64, conv func(float64) (T, bool)) error {
+	vals := make([]T, len(data))
+	for i, x := range data {
+		var ok bool
+		if vals[i], ok = conv(x); !ok {
+			t, _ := v.Type()
+			return fmt.Errorf("ncmat: value %v can't be represented as %v", x, t)
+		}
+	}
+	shape := make([]int, len(count))
+	for i, n := range count {
+		shape[i] = int(n)
+	}
+	a, err := netcdf.ArrayOf(vals, shape...)
+	if err != nil {
+		return err
+	}
+	return netcdf.WriteArraySlice(v, a, start)
+}
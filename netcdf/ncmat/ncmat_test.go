@@ -0,0 +1,179 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package ncmat
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+	"gonum.org/v1/gonum/mat"
+)
+
+// create returns a dataset with a variable of dimensions (time, y, x),
+// whose value at (t, y, x) is 100*t + 10*y + x.
+func create(t *testing.T, typ netcdf.Type) netcdf.Dataset {
+	var data []int
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 4; k++ {
+				data = append(data, 100*i+10*j+k)
+			}
+		}
+	}
+	return netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "time", Len: 2}, {Name: "y", Len: 3}, {Name: "x", Len: 4}},
+		Vars: []netcdftest.Var{
+			{Name: "v", Type: typ, Dims: []string{"time", "y", "x"}, Data: data},
+			{Name: "yx", Type: typ, Dims: []string{"y", "x"}, Data: data[:12]},
+			{Name: "x", Type: typ, Dims: []string{"x"}, Data: data[:4]},
+		},
+	})
+}
+
+func TestReadDense(t *testing.T) {
+	for _, typ := range []netcdf.Type{netcdf.DOUBLE, netcdf.FLOAT, netcdf.SHORT, netcdf.UBYTE} {
+		ds := create(t, typ)
+		v, _ := ds.Var("v")
+
+		// Rows are along y, and columns along x.
+		m, err := ReadDenseSlice(v, []uint64{1, 0, 1}, []uint64{1, 3, 2})
+		if err != nil {
+			t.Fatalf("ReadDenseSlice of %v failed: %v\n", typ, err)
+		}
+		want := mat.NewDense(3, 2, []float64{101, 102, 111, 112, 121, 122})
+		if !mat.Equal(m, want) {
+			t.Errorf("y-x slice of %v is\n%v\nexpected\n%v\n", typ, mat.Formatted(m), mat.Formatted(want))
+		}
+		// Rows are along time, and columns along x.
+		m, err = ReadDenseSlice(v, []uint64{0, 2, 0}, []uint64{2, 1, 4})
+		if err != nil {
+			t.Fatalf("ReadDenseSlice of %v failed: %v\n", typ, err)
+		}
+		want = mat.NewDense(2, 4, []float64{20, 21, 22, 23, 120, 121, 122, 123})
+		if !mat.Equal(m, want) {
+			t.Errorf("time-x slice of %v is\n%v\nexpected\n%v\n", typ, mat.Formatted(m), mat.Formatted(want))
+		}
+
+		yx, _ := ds.Var("yx")
+		m, err = ReadDense(yx)
+		if err != nil {
+			t.Fatalf("ReadDense of %v failed: %v\n", typ, err)
+		}
+		if r, c := m.Dims(); r != 3 || c != 4 || m.At(2, 1) != 21 {
+			t.Errorf("ReadDense of %v returned %d×%d matrix with (2, 1) = %v\n", typ, r, c, m.At(2, 1))
+		}
+
+		x, _ := ds.Var("x")
+		vec, err := ReadVecDense(x)
+		if err != nil {
+			t.Fatalf("ReadVecDense of %v failed: %v\n", typ, err)
+		}
+		if got := vec.RawVector().Data; !reflect.DeepEqual(got, []float64{0, 1, 2, 3}) {
+			t.Errorf("ReadVecDense of %v returned %v\n", typ, got)
+		}
+		vec, err = ReadVecDenseSlice(v, []uint64{0, 0, 3}, []uint64{2, 1, 1})
+		if err != nil {
+			t.Fatalf("ReadVecDenseSlice of %v failed: %v\n", typ, err)
+		}
+		if got := vec.RawVector().Data; !reflect.DeepEqual(got, []float64{3, 103}) {
+			t.Errorf("ReadVecDenseSlice of %v returned %v\n", typ, got)
+		}
+		ds.Close()
+	}
+}
+
+func TestWriteMatrix(t *testing.T) {
+	ds := create(t, netcdf.INT)
+	defer ds.Close()
+	v, _ := ds.Var("v")
+
+	m := mat.NewDense(2, 3, []float64{-1, -2, -3, -4, -5, -6.4})
+	// Writing the transpose stores the columns of m along x.
+	if err := WriteMatrixSlice(v, m.T(), []uint64{1, 0, 1}, []uint64{1, 3, 2}); err != nil {
+		t.Fatalf("WriteMatrixSlice failed: %v\n", err)
+	}
+	got, err := ReadDenseSlice(v, []uint64{1, 0, 0}, []uint64{1, 3, 4})
+	if err != nil {
+		t.Fatalf("ReadDenseSlice failed: %v\n", err)
+	}
+	want := mat.NewDense(3, 4, []float64{100, -1, -4, 103, 110, -2, -5, 113, 120, -3, -6, 123})
+	if !mat.Equal(got, want) {
+		t.Errorf("after WriteMatrixSlice, slice is\n%v\nexpected\n%v\n", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	yx, _ := ds.Var("yx")
+	if err := WriteMatrix(yx, m); err == nil || !strings.Contains(err.Error(), "2×3 matrix") {
+		t.Errorf("WriteMatrix with the wrong shape returned %v\n", err)
+	}
+	x, _ := ds.Var("x")
+	if err := WriteVector(x, mat.NewVecDense(4, []float64{7, 8, 9, 10})); err != nil {
+		t.Fatalf("WriteVector failed: %v\n", err)
+	}
+	if vals, err := netcdf.GetInt32s(x); err != nil || !reflect.DeepEqual(vals, []int32{7, 8, 9, 10}) {
+		t.Errorf("x is %v, %v after WriteVector\n", vals, err)
+	}
+	if err := WriteVectorSlice(v, mat.NewVecDense(2, []float64{1, 3e9}), []uint64{0, 0, 0}, []uint64{2, 1, 1}); err == nil || !strings.Contains(err.Error(), "can't be represented as INT") {
+		t.Errorf("WriteVectorSlice of out of range value returned %v\n", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ds := create(t, netcdf.DOUBLE)
+	defer ds.Close()
+	v, _ := ds.Var("v")
+	if _, err := ReadDense(v); err == nil {
+		t.Errorf("ReadDense of 3-D variable succeeded\n")
+	}
+	if _, err := ReadDenseSlice(v, []uint64{0, 0, 0}, []uint64{2, 3, 4}); err == nil {
+		t.Errorf("ReadDenseSlice of 3-D hyperslab succeeded\n")
+	}
+	if _, err := ReadVecDenseSlice(v, []uint64{0, 0, 0}, []uint64{1, 3, 4}); err == nil {
+		t.Errorf("ReadVecDenseSlice of 2-D hyperslab succeeded\n")
+	}
+	if _, err := ReadVecDense(v); err == nil {
+		t.Errorf("ReadVecDense of 3-D variable succeeded\n")
+	}
+}
+
+func TestMatrixShape(t *testing.T) {
+	ds := create(t, netcdf.DOUBLE)
+	defer ds.Close()
+	v, _ := ds.Var("v")
+	yx, _ := ds.Var("yx")
+
+	// Row and column vectors have the same shape whatever the number of
+	// dimensions of the variable.
+	for _, tc := range []struct {
+		v            netcdf.Var
+		start, count []uint64
+		r, c         int
+	}{
+		{yx, []uint64{1, 0}, []uint64{1, 4}, 1, 4},
+		{v, []uint64{1, 1, 0}, []uint64{1, 1, 4}, 1, 4},
+		{yx, []uint64{0, 2}, []uint64{3, 1}, 3, 1},
+		{v, []uint64{1, 0, 2}, []uint64{1, 3, 1}, 3, 1},
+		{v, []uint64{0, 1, 2}, []uint64{2, 1, 1}, 2, 1},
+		{v, []uint64{1, 1, 1}, []uint64{1, 1, 1}, 1, 1},
+	} {
+		m, err := ReadDenseSlice(tc.v, tc.start, tc.count)
+		if err != nil {
+			t.Errorf("ReadDenseSlice(%v, %v) failed: %v\n", tc.start, tc.count, err)
+			continue
+		}
+		if r, c := m.Dims(); r != tc.r || c != tc.c {
+			t.Errorf("ReadDenseSlice(%v, %v) returned a %d×%d matrix; expected %d×%d\n", tc.start, tc.count, r, c, tc.r, tc.c)
+		}
+	}
+	m, err := ReadDenseSlice(v, []uint64{1, 1, 0}, []uint64{1, 1, 4})
+	if err != nil {
+		t.Fatalf("ReadDenseSlice failed: %v\n", err)
+	}
+	if want := mat.NewDense(1, 4, []float64{110, 111, 112, 113}); !mat.Equal(m, want) {
+		t.Errorf("row vector is\n%v\nexpected\n%v\n", mat.Formatted(m), mat.Formatted(want))
+	}
+}
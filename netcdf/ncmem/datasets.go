@@ -20,6 +20,7 @@ import (
 	"unsafe"
 
 	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/lock"
 )
 
 // Flags define how the netCDF library should manage memory.
@@ -53,12 +54,15 @@ func Open(path string, mode netcdf.FileMode, flags Flags, data []byte) (ds Datas
 	}
 
 	var id C.int
+	lock.Lock()
 	err = newError(C.nc_open_memio(cpath, C.int(mode), &memio, &id))
+	lock.Unlock()
 	if err != nil {
 		C.free(memio.memory)
+		return
 	}
 
-	ds.Dataset = netcdf.Dataset(id)
+	ds.Dataset = netcdf.NewDataset(int(id), path)
 	return
 }
 
@@ -85,12 +89,15 @@ func OpenReader(path string, mode netcdf.FileMode, flags Flags, r io.Reader) (ds
 	}
 
 	var id C.int
+	lock.Lock()
 	err = newError(C.nc_open_memio(cpath, C.int(mode), &memio, &id))
+	lock.Unlock()
 	if err != nil {
 		C.free(memio.memory)
+		return
 	}
 
-	ds.Dataset = netcdf.Dataset(id)
+	ds.Dataset = netcdf.NewDataset(int(id), path)
 	return
 }
 
@@ -103,9 +110,12 @@ func Create(path string, mode netcdf.FileMode, initialSize int) (ds Dataset, err
 	defer C.free(unsafe.Pointer(cpath))
 
 	var id C.int
+	lock.Lock()
 	err = newError(C.nc_create_mem(cpath, C.int(mode), C.size_t(initialSize), &id))
-	ds.Dataset = netcdf.Dataset(id)
-
+	lock.Unlock()
+	if err == nil {
+		ds.Dataset = netcdf.NewDataset(int(id), path)
+	}
 	return
 }
 
@@ -134,22 +144,43 @@ whether NC_MEMIO_LOCKED was specified, then conditionally freeing the memory if
 https://www.unidata.ucar.edu/software/netcdf/docs/md__Volumes_Workspace_releases_netcdf-c-4_87_84_netcdf-c_docs_inmemory.html
 */
 
-// Close closes and releases the memory of the dataset.
+// closeMemio closes the dataset with nc_close_memio, returning the
+// in-memory data in memio. It returns netcdf.ErrClosed if the dataset is
+// already closed.
+func (ds Dataset) closeMemio(memio *C.NC_memio) error {
+	closed := true
+	err := ds.Dataset.CloseFunc(func(id int) error {
+		closed = false
+		lock.Lock()
+		defer lock.Unlock()
+		return newError(C.nc_close_memio(C.int(id), memio))
+	})
+	if closed {
+		return netcdf.ErrClosed
+	}
+	return err
+}
+
+// Close closes and releases the memory of the dataset. Closing a dataset
+// that's already closed does nothing.
 //
 // Use CloseMem to retrieve the in-memory data.
 func (ds Dataset) Close() (err error) {
 	var memio C.NC_memio
-	err = newError(C.nc_close_memio(C.int(ds.Dataset), &memio))
+	err = ds.closeMemio(&memio)
 	if memio.memory != nil {
 		C.free(memio.memory)
 	}
+	if err == netcdf.ErrClosed {
+		err = nil
+	}
 	return
 }
 
 // CloseCopyBytes closes the dataset and returns a copy of the in-memory data.
 func (ds Dataset) CloseCopyBytes() (data []byte, err error) {
 	var memio C.NC_memio
-	err = newError(C.nc_close_memio(C.int(ds.Dataset), &memio))
+	err = ds.closeMemio(&memio)
 	if memio.memory != nil {
 		data = C.GoBytes(memio.memory, C.int(memio.size))
 		C.free(memio.memory)
@@ -161,7 +192,7 @@ func (ds Dataset) CloseCopyBytes() (data []byte, err error) {
 // memory.
 func (ds Dataset) CloseBytes() (*Bytes, error) {
 	var memio C.NC_memio
-	err := newError(C.nc_close_memio(C.int(ds.Dataset), &memio))
+	err := ds.closeMemio(&memio)
 	if err != nil {
 		return nil, err
 	}
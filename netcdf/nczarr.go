@@ -0,0 +1,101 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// NCZarrStore is the kind of storage of an NCZarr dataset.
+type NCZarrStore int
+
+const (
+	// NCZarrDir is a store in a directory of the local file system.
+	NCZarrDir NCZarrStore = iota
+	// NCZarrZip is a store in a zip file.
+	NCZarrZip
+)
+
+func (s NCZarrStore) String() string {
+	switch s {
+	case NCZarrDir:
+		return "file"
+	case NCZarrZip:
+		return "zip"
+	}
+	return fmt.Sprintf("NCZarrStore(%d)", int(s))
+}
+
+// NCZarrURL returns the URL naming the local NCZarr store at path, for
+// CreateFile and OpenFile. Relative paths are made absolute. For example,
+// the store in directory /data/out.zarr is
+//
+//	file:///data/out.zarr#mode=nczarr,file
+//
+// NCZarr datasets have the netCDF-4 data model, so CreateFile needs the
+// NETCDF4 mode. They're only supported by netCDF-C 4.8 and later, when
+// built with NCZarr, as reported by HasNCZarr.
+func NCZarrURL(path string, store NCZarrStore) (string, error) {
+	if store != NCZarrDir && store != NCZarrZip {
+		return "", fmt.Errorf("netcdf: invalid NCZarr store %v", store)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		// Windows paths such as C:/data become /C:/data.
+		p = "/" + p
+	}
+	u := url.URL{Scheme: "file", Path: p, Fragment: "mode=nczarr," + store.String()}
+	return u.String(), nil
+}
+
+var nczarrProbe struct {
+	once sync.Once
+	ok   bool
+}
+
+// HasNCZarr reports whether the C library can create and open NCZarr
+// stores in local directories. The first call finds out by creating a
+// store in a temporary directory; later calls return the same result.
+// It's false without cgo.
+func HasNCZarr() bool {
+	nczarrProbe.once.Do(func() {
+		nczarrProbe.ok = probeNCZarr()
+	})
+	return nczarrProbe.ok
+}
+
+func probeNCZarr() bool {
+	dir, err := ioutil.TempDir("", "netcdf_nczarr")
+	if err != nil {
+		return false
+	}
+	defer os.RemoveAll(dir)
+	path, err := NCZarrURL(filepath.Join(dir, "probe.zarr"), NCZarrDir)
+	if err != nil {
+		return false
+	}
+	ds, err := CreateFile(path, CLOBBER|NETCDF4)
+	if err != nil {
+		return false
+	}
+	if err := ds.Close(); err != nil {
+		return false
+	}
+	ds, err = OpenFile(path, NOWRITE)
+	if err != nil {
+		return false
+	}
+	return ds.Close() == nil
+}
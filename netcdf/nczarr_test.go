@@ -0,0 +1,97 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+func TestNCZarrURL(t *testing.T) {
+	for _, tc := range []struct {
+		path  string
+		store netcdf.NCZarrStore
+		want  string
+	}{
+		{"/data/out.zarr", netcdf.NCZarrDir, "file:///data/out.zarr#mode=nczarr,file"},
+		{"/data/out.zip", netcdf.NCZarrZip, "file:///data/out.zip#mode=nczarr,zip"},
+		{"/my data/a#b", netcdf.NCZarrDir, "file:///my%20data/a%23b#mode=nczarr,file"},
+	} {
+		got, err := netcdf.NCZarrURL(filepath.FromSlash(tc.path), tc.store)
+		if err != nil {
+			t.Fatalf("NCZarrURL(%q) failed: %v\n", tc.path, err)
+		}
+		if filepath.Separator == '/' && got != tc.want {
+			t.Errorf("NCZarrURL(%q, %v) is %q; want %q\n", tc.path, tc.store, got, tc.want)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd failed: %v\n", err)
+	}
+	got, err := netcdf.NCZarrURL("out.zarr", netcdf.NCZarrDir)
+	if err != nil {
+		t.Fatalf("NCZarrURL failed: %v\n", err)
+	}
+	if want := filepath.ToSlash(filepath.Join(wd, "out.zarr")); !strings.Contains(got, want) {
+		t.Errorf("NCZarrURL of a relative path is %q; want it to contain %q\n", got, want)
+	}
+
+	if _, err := netcdf.NCZarrURL("out.zarr", netcdf.NCZarrStore(5)); err == nil {
+		t.Errorf("NCZarrURL with an invalid store succeeded\n")
+	}
+}
+
+func TestNCZarr(t *testing.T) {
+	if !netcdf.HasNCZarr() {
+		t.Skip("the C library doesn't support NCZarr")
+	}
+	dir, err := ioutil.TempDir("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+	path, err := netcdf.NCZarrURL(filepath.Join(dir, "test.zarr"), netcdf.NCZarrDir)
+	if err != nil {
+		t.Fatalf("NCZarrURL failed: %v\n", err)
+	}
+
+	src := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims:  []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "x", Len: 3}},
+		Attrs: []netcdftest.Attr{{Name: "title", Value: "nczarr"}},
+		Vars: []netcdftest.Var{
+			{Name: "temp", Dims: []string{"time", "x"}, Data: []float32{1, 2, 3, 4, 5, 6},
+				Attrs: []netcdftest.Attr{{Name: "units", Value: "K"}}},
+		},
+	})
+	defer src.Close()
+	ds, err := netcdf.CreateFile(path, netcdf.CLOBBER|netcdf.NETCDF4)
+	if err != nil {
+		t.Fatalf("CreateFile(%q) failed: %v\n", path, err)
+	}
+	if err := netcdf.Copy(ds, src, &netcdf.CopyOptions{Chunks: map[string]uint64{"time": 1}}); err != nil {
+		t.Fatalf("Copy failed: %v\n", err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatalf("Close failed: %v\n", err)
+	}
+
+	ds, err = netcdf.OpenFile(path, netcdf.NOWRITE)
+	if err != nil {
+		t.Fatalf("OpenFile(%q) failed: %v\n", path, err)
+	}
+	defer ds.Close()
+	netcdftest.AssertEqual(t, ds, src)
+	if _, err := os.Stat(filepath.Join(dir, "test.zarr")); err != nil {
+		t.Errorf("the store isn't a directory: %v\n", err)
+	}
+}
@@ -10,6 +10,24 @@
 // netCDF 4 support is enabled in the C library.
 // The C library interface used is documented here:
 // http://www.unidata.ucar.edu/software/netcdf/docs/netcdf-c/
+//
+// The C library is not thread-safe by default, so this package serializes
+// all calls into it with a package-wide lock. It's safe to use datasets
+// from multiple goroutines, but calls into the library don't run in
+// parallel. If the C library was built with thread-safety enabled, the
+// lock can be disabled with the netcdf_threadsafe build tag:
+//
+//	go build -tags netcdf_threadsafe
+//
+// Datasets access their storage through a driver. CreateFile and OpenFile
+// use the C library in programs built with cgo. In programs built without
+// cgo, they use package cdf if it's imported, and only support the classic
+// formats:
+//
+//	import _ "github.com/fhs/go-netcdf/netcdf/cdf"
+//
+// Packages of this module provide datasets with other storage, such as
+// the in-memory datasets of package netcdftest.
 package netcdf
 
 import "fmt"
@@ -59,15 +77,15 @@ func okDataSlice(a sliceableTypedArray, t Type, n int, start, count []uint64) er
 		return err
 	}
 	if len(start) != len(d) {
-		return fmt.Errorf("incorrect number of dimensions in start: %d != %d", start, len(d))
+		return fmt.Errorf("incorrect number of dimensions in start: %d != %d", len(start), len(d))
 	}
 	if len(count) != len(d) {
-		return fmt.Errorf("incorrect number of dimensions in count: %d != %d", count, len(d))
+		return fmt.Errorf("incorrect number of dimensions in count: %d != %d", len(count), len(d))
 	}
 
 	for i, id := range d {
 		if start[i] >= id || start[i] < 0 {
-			return fmt.Errorf("start of dimension %d of slice is out of range: 0 <= %d < %d", i, start, id)
+			return fmt.Errorf("start of dimension %d of slice is out of range: 0 <= %d < %d", i, start[i], id)
 		}
 		v := start[i] + count[i]
 		if v > id || v <= 0 {
@@ -97,22 +115,29 @@ func okDataStride(a sliceableTypedArray, t Type, n int, start, count []uint64, s
 		return err
 	}
 	if len(start) != len(d) {
-		return fmt.Errorf("incorrect number of dimensions in start: %d != %d", start, len(d))
+		return fmt.Errorf("incorrect number of dimensions in start: %d != %d", len(start), len(d))
 	}
 	if len(count) != len(d) {
-		return fmt.Errorf("incorrect number of dimensions in count: %d != %d", count, len(d))
+		return fmt.Errorf("incorrect number of dimensions in count: %d != %d", len(count), len(d))
 	}
 	if len(stride) != len(d) {
-		return fmt.Errorf("incorrect number of dimensions in stride: %d != %d", stride, len(d))
+		return fmt.Errorf("incorrect number of dimensions in stride: %d != %d", len(stride), len(d))
 	}
 
 	for i, id := range d {
 		if start[i] >= id || start[i] < 0 {
-			return fmt.Errorf("start of dimension %d of slice is out of range: 0 <= %d < %d", i, start, id)
+			return fmt.Errorf("start of dimension %d of slice is out of range: 0 <= %d < %d", i, start[i], id)
 		}
-		v := int64(start[i]) + int64(count[i])*stride[i]
-		if v > int64(id) || v <= 0 {
-			return fmt.Errorf("end of dimension %d of slice is out of range: 0 < %d <= %d", i, v, id)
+		if stride[i] < 1 {
+			return fmt.Errorf("stride of dimension %d of slice is not positive: %d", i, stride[i])
+		}
+		if count[i] == 0 {
+			continue
+		}
+		// The last index selected along the dimension.
+		v := start[i] + (count[i]-1)*uint64(stride[i])
+		if v >= id {
+			return fmt.Errorf("end of dimension %d of slice is out of range: %d < %d", i, v, id)
 		}
 	}
 
@@ -131,7 +156,9 @@ func product(nums []uint64) (prod uint64) {
 	return
 }
 
-// UnravelIndex calculates coordinate position based on index
+// UnravelIndex returns the coordinates of the value at position idx in the
+// row-major order of an array of the given shape. It's the inverse of
+// RavelIndex.
 func UnravelIndex(idx uint64, shape []uint64) ([]uint64, error) {
 	for _, v := range shape {
 		if v == 0 {
@@ -139,19 +166,30 @@ func UnravelIndex(idx uint64, shape []uint64) ([]uint64, error) {
 		}
 	}
 
-	if idx > product(shape) {
-		return nil, fmt.Errorf("index %v > size %v of shape", idx, shape)
+	if idx >= product(shape) {
+		return nil, fmt.Errorf("index %v >= size %v of shape %v", idx, product(shape), shape)
 	}
 
-	var maxval = product(shape)
-	var ndim = len(shape)
-	var coord = make([]uint64, ndim)
-
-	for i := 0; i < ndim; i++ {
-		shape[i] = 1
-		maxval = product(shape)
-		coord[i] = uint64(idx / maxval)
-		idx -= coord[i] * maxval
+	var coord = make([]uint64, len(shape))
+	for i := len(shape) - 1; i >= 0; i-- {
+		coord[i] = idx % shape[i]
+		idx /= shape[i]
 	}
 	return coord, nil
 }
+
+// RavelIndex returns the position of the value at coordinates coord in the
+// row-major order of an array of the given shape.
+func RavelIndex(coord, shape []uint64) (uint64, error) {
+	if len(coord) != len(shape) {
+		return 0, fmt.Errorf("coordinates %v don't match shape %v", coord, shape)
+	}
+	var idx uint64
+	for i, c := range coord {
+		if c >= shape[i] {
+			return 0, fmt.Errorf("coordinates %v out of range for shape %v", coord, shape)
+		}
+		idx = idx*shape[i] + c
+	}
+	return idx, nil
+}
@@ -0,0 +1,621 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package netcdftest provides in-memory datasets for testing code that
+// uses package netcdf, without files or the netCDF C library.
+//
+// Datasets returned by this package support the same operations as
+// datasets created by netcdf.CreateFile, and return the same errors
+// (e.g. netcdf.ENOTVAR for a variable that doesn't exist). They behave
+// like netCDF-4 datasets: any dimension can be unlimited, and
+// definitions can be added after EndDef.
+//
+// Fixtures are built from Go literals:
+//
+//	ds, err := netcdftest.Build(netcdftest.Fixture{
+//		Dims:  []netcdftest.Dim{{Name: "time"}, {Name: "x", Len: 3}},
+//		Attrs: []netcdftest.Attr{{Name: "title", Value: "example"}},
+//		Vars:  []netcdftest.Var{{
+//			Name:  "temp",
+//			Dims:  []string{"time", "x"},
+//			Attrs: []netcdftest.Attr{{Name: "units", Value: "K"}},
+//			Data:  []float32{280, 281, 282, 283, 284, 285},
+//		}},
+//	})
+package netcdftest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+	"github.com/fhs/go-netcdf/netcdf/internal/fake"
+)
+
+// New returns a new empty in-memory dataset, in define mode.
+func New() netcdf.Dataset {
+	return fake.Create("")
+}
+
+// Fixture describes the contents of a dataset.
+type Fixture struct {
+	Dims  []Dim
+	Attrs []Attr // global attributes
+	Vars  []Var
+}
+
+// Dim describes a dimension. A dimension of length 0 is unlimited.
+type Dim struct {
+	Name string
+	Len  uint64
+}
+
+// Attr describes an attribute. Value is a slice or a single value of a Go
+// type supported by package netcdf (e.g. []float64 or float64 for DOUBLE),
+// or a string for CHAR. Like for Var, Type converts the values to another
+// type, and is needed to create a CHAR attribute from a []byte, which
+// otherwise is UBYTE.
+type Attr struct {
+	Name  string
+	Type  netcdf.Type
+	Value interface{}
+}
+
+// Var describes a variable. Data holds all the values of the variable in
+// row-major order, in a slice of a Go type supported by package netcdf,
+// or a string for CHAR. If the first dimension is unlimited, the variable
+// has as many records as Data holds. If Data is nil, the values are fill
+// values.
+//
+// Type is the type of the variable. It defaults to the type of Data, so
+// it's only needed if Data is nil, for CHAR variables given a []byte, and
+// for values converted to a different type. Values of any numeric Go type,
+// such as a []int literal, are converted to Type.
+type Var struct {
+	Name  string
+	Type  netcdf.Type
+	Dims  []string
+	Attrs []Attr
+	Data  interface{}
+}
+
+// Build returns a new in-memory dataset with the contents described by f,
+// in data mode.
+func Build(f Fixture) (netcdf.Dataset, error) {
+	d := fake.New()
+	ds := driver.NewDataset(d, "").(netcdf.Dataset)
+	if err := build(d, f); err != nil {
+		ds.Close()
+		return netcdf.Dataset{}, err
+	}
+	return ds, nil
+}
+
+// MustBuild is like Build, but stops the test with tb.Fatal if the
+// dataset can't be built.
+func MustBuild(tb testing.TB, f Fixture) netcdf.Dataset {
+	tb.Helper()
+	ds, err := Build(f)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return ds
+}
+
+func build(d *fake.Dataset, f Fixture) error {
+	dims := make(map[string]int)
+	for _, dim := range f.Dims {
+		id, err := d.DefDim(dim.Name, dim.Len)
+		if err != nil {
+			return fmt.Errorf("dimension %q: %v", dim.Name, err)
+		}
+		dims[dim.Name] = id
+	}
+	if err := putAttrs(d, driver.Global, f.Attrs); err != nil {
+		return err
+	}
+	ids := make([]int, len(f.Vars))
+	types := make([]netcdf.Type, len(f.Vars))
+	data := make([]interface{}, len(f.Vars))
+	for i, v := range f.Vars {
+		var err error
+		types[i], data[i], err = value(v.Type, v.Data)
+		if err != nil {
+			return fmt.Errorf("variable %q: %v", v.Name, err)
+		}
+		dimids := make([]int, len(v.Dims))
+		for j, name := range v.Dims {
+			id, ok := dims[name]
+			if !ok {
+				return fmt.Errorf("variable %q: unknown dimension %q", v.Name, name)
+			}
+			dimids[j] = id
+		}
+		if ids[i], err = d.DefVar(v.Name, int(types[i]), dimids); err != nil {
+			return fmt.Errorf("variable %q: %v", v.Name, err)
+		}
+		if err := putAttrs(d, ids[i], v.Attrs); err != nil {
+			return fmt.Errorf("variable %q: %v", v.Name, err)
+		}
+	}
+	if err := d.EndDef(); err != nil {
+		return err
+	}
+	for i, v := range f.Vars {
+		if data[i] == nil {
+			continue
+		}
+		if err := putVar(d, ids[i], types[i], data[i]); err != nil {
+			return fmt.Errorf("variable %q: %v", v.Name, err)
+		}
+	}
+	return nil
+}
+
+func putAttrs(d *fake.Dataset, varid int, attrs []Attr) error {
+	for _, a := range attrs {
+		t, val, err := value(a.Type, a.Value)
+		if err == nil && val == nil {
+			err = fmt.Errorf("no value")
+		}
+		if err == nil {
+			err = d.PutAttr(varid, a.Name, int(t), val)
+		}
+		if err != nil {
+			return fmt.Errorf("attribute %q: %v", a.Name, err)
+		}
+	}
+	return nil
+}
+
+// putVar writes data, a slice of values of type t, as the entire data of
+// variable varid, adding records if its first dimension is unlimited.
+func putVar(d *fake.Dataset, varid int, t netcdf.Type, data interface{}) error {
+	n := reflect.ValueOf(data).Len()
+	dimids, err := d.VarDims(varid)
+	if err != nil {
+		return err
+	}
+	unlimited, err := d.UnlimitedDims()
+	if err != nil {
+		return err
+	}
+	count := make([]uint64, len(dimids))
+	for i, id := range dimids {
+		if count[i], err = d.DimLen(id); err != nil {
+			return err
+		}
+	}
+	if len(count) > 0 && contains(unlimited, dimids[0]) {
+		if m := product(count[1:]); m > 0 {
+			count[0] = uint64(n) / m
+		}
+	}
+	if product(count) != uint64(n) {
+		return fmt.Errorf("%d values don't fill shape %v", n, count)
+	}
+	if n == 0 {
+		return nil
+	}
+	return d.PutVars(varid, int(t), make([]uint64, len(count)), count, nil, data)
+}
+
+// goTypes maps netCDF types to the Go types of their values.
+var goTypes = map[netcdf.Type]reflect.Type{
+	netcdf.BYTE:   reflect.TypeOf(int8(0)),
+	netcdf.CHAR:   reflect.TypeOf(byte(0)),
+	netcdf.SHORT:  reflect.TypeOf(int16(0)),
+	netcdf.INT:    reflect.TypeOf(int32(0)),
+	netcdf.FLOAT:  reflect.TypeOf(float32(0)),
+	netcdf.DOUBLE: reflect.TypeOf(float64(0)),
+	netcdf.UBYTE:  reflect.TypeOf(uint8(0)),
+	netcdf.USHORT: reflect.TypeOf(uint16(0)),
+	netcdf.UINT:   reflect.TypeOf(uint32(0)),
+	netcdf.INT64:  reflect.TypeOf(int64(0)),
+	netcdf.UINT64: reflect.TypeOf(uint64(0)),
+}
+
+// typeOf returns the netCDF type of values of Go type gt, or 0 if there's
+// none. Bytes are UBYTE values.
+func typeOf(gt reflect.Type) netcdf.Type {
+	for t, u := range goTypes {
+		if u == gt && t != netcdf.CHAR {
+			return t
+		}
+	}
+	return 0
+}
+
+// value returns the type of the values in val, which is a string, a slice
+// or a single value, and the values in a slice of type t if t is not 0.
+// The slice is nil if val is nil.
+func value(t netcdf.Type, val interface{}) (netcdf.Type, interface{}, error) {
+	if val == nil {
+		if t == 0 {
+			return 0, nil, fmt.Errorf("no type or value")
+		}
+		if _, ok := goTypes[t]; !ok {
+			return 0, nil, fmt.Errorf("unsupported type %v", t)
+		}
+		return t, nil, nil
+	}
+	if s, ok := val.(string); ok {
+		if t != 0 && t != netcdf.CHAR {
+			return 0, nil, fmt.Errorf("string value for type %v", t)
+		}
+		return netcdf.CHAR, []byte(s), nil
+	}
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice {
+		s := reflect.MakeSlice(reflect.SliceOf(v.Type()), 1, 1)
+		s.Index(0).Set(v)
+		v = s
+	}
+	u := typeOf(v.Type().Elem())
+	if u == 0 && (t == 0 || !numeric(v.Type().Elem())) {
+		return 0, nil, fmt.Errorf("unsupported value type %T", val)
+	}
+	switch {
+	case t == 0:
+		t = u
+	case t == netcdf.CHAR:
+		if u != netcdf.UBYTE {
+			return 0, nil, fmt.Errorf("%T value for type CHAR", val)
+		}
+		return t, v.Interface(), nil
+	default:
+		if _, ok := goTypes[t]; !ok {
+			return 0, nil, fmt.Errorf("unsupported type %v", t)
+		}
+	}
+	// Convert the values, so attributes are stored with type t.
+	s := reflect.MakeSlice(reflect.SliceOf(goTypes[t]), v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		s.Index(i).Set(v.Index(i).Convert(goTypes[t]))
+	}
+	return t, s.Interface(), nil
+}
+
+// numeric reports whether values of type gt are numbers, which can be
+// converted to the Go type of any netCDF type other than CHAR.
+func numeric(gt reflect.Type) bool {
+	switch gt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func contains(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func product(nums []uint64) uint64 {
+	prod := uint64(1)
+	for _, n := range nums {
+		prod *= n
+	}
+	return prod
+}
+
+// AssertEqual reports a test error with tb.Error if datasets got and want
+// aren't equal, as reported by Equal.
+func AssertEqual(tb testing.TB, got, want netcdf.Dataset) {
+	tb.Helper()
+	if err := Equal(got, want); err != nil {
+		tb.Error(err)
+	}
+}
+
+// Equal returns an error describing the first difference between datasets
+// a and b, or nil if they're equal. Datasets are equal if they have the
+// same dimensions, global attributes and variables, in the same order.
+// Variables are equal if they have the same name, type, dimensions,
+// attributes and values. NaN values are equal to each other. Compression
+// and chunking aren't compared.
+func Equal(a, b netcdf.Dataset) error {
+	na, err := a.NDims()
+	if err != nil {
+		return err
+	}
+	nb, err := b.NDims()
+	if err != nil {
+		return err
+	}
+	if na != nb {
+		return fmt.Errorf("datasets have %d and %d dimensions", na, nb)
+	}
+	ua, err := unlimitedIDs(a)
+	if err != nil {
+		return err
+	}
+	ub, err := unlimitedIDs(b)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < na; i++ {
+		da, err := describeDim(a.DimN(i), ua)
+		if err != nil {
+			return err
+		}
+		db, err := describeDim(b.DimN(i), ub)
+		if err != nil {
+			return err
+		}
+		if da != db {
+			return fmt.Errorf("dimension %d is %s and %s", i, da, db)
+		}
+	}
+
+	if err := equalAttrs(a.AttrN, b.AttrN, a.NAttrs, b.NAttrs); err != nil {
+		return fmt.Errorf("global %v", err)
+	}
+
+	na, err = a.NVars()
+	if err != nil {
+		return err
+	}
+	nb, err = b.NVars()
+	if err != nil {
+		return err
+	}
+	if na != nb {
+		return fmt.Errorf("datasets have %d and %d variables", na, nb)
+	}
+	for i := 0; i < na; i++ {
+		if err := equalVars(i, a.VarN(i), b.VarN(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unlimitedIDs(ds netcdf.Dataset) ([]int, error) {
+	dims, err := ds.UnlimitedDims()
+	ids := make([]int, len(dims))
+	for i, d := range dims {
+		ids[i] = d.ID()
+	}
+	return ids, err
+}
+
+// describeDim returns the name and length of dim, e.g. "time(2, unlimited)".
+func describeDim(dim netcdf.Dim, unlimited []int) (string, error) {
+	name, err := dim.Name()
+	if err != nil {
+		return "", err
+	}
+	n, err := dim.Len()
+	if err != nil {
+		return "", err
+	}
+	if contains(unlimited, dim.ID()) {
+		return fmt.Sprintf("%s(%d, unlimited)", name, n), nil
+	}
+	return fmt.Sprintf("%s(%d)", name, n), nil
+}
+
+func equalAttrs(attrA, attrB func(n int) (netcdf.Attr, error), nattrsA, nattrsB func() (int, error)) error {
+	na, err := nattrsA()
+	if err != nil {
+		return err
+	}
+	nb, err := nattrsB()
+	if err != nil {
+		return err
+	}
+	if na != nb {
+		return fmt.Errorf("attributes: %d and %d attributes", na, nb)
+	}
+	for i := 0; i < na; i++ {
+		aa, err := attrA(i)
+		if err != nil {
+			return err
+		}
+		ab, err := attrB(i)
+		if err != nil {
+			return err
+		}
+		if aa.Name() != ab.Name() {
+			return fmt.Errorf("attribute %d is named %q and %q", i, aa.Name(), ab.Name())
+		}
+		ta, va, err := readAttr(aa)
+		if err != nil {
+			return err
+		}
+		tb, vb, err := readAttr(ab)
+		if err != nil {
+			return err
+		}
+		if ta != tb {
+			return fmt.Errorf("attribute %q has types %v and %v", aa.Name(), ta, tb)
+		}
+		if i, ok := equalValues(va, vb); !ok {
+			return fmt.Errorf("attribute %q differs at index %d: %v and %v", aa.Name(), i, va, vb)
+		}
+	}
+	return nil
+}
+
+func equalVars(id int, a, b netcdf.Var) error {
+	name, err := a.Name()
+	if err != nil {
+		return err
+	}
+	nameB, err := b.Name()
+	if err != nil {
+		return err
+	}
+	if name != nameB {
+		return fmt.Errorf("variable %d is named %q and %q", id, name, nameB)
+	}
+	ta, err := a.Type()
+	if err != nil {
+		return err
+	}
+	tb, err := b.Type()
+	if err != nil {
+		return err
+	}
+	if ta != tb {
+		return fmt.Errorf("variable %q has types %v and %v", name, ta, tb)
+	}
+	da, err := dimNames(a)
+	if err != nil {
+		return err
+	}
+	db, err := dimNames(b)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(da, db) {
+		return fmt.Errorf("variable %q has dimensions %v and %v", name, da, db)
+	}
+	if err := equalAttrs(a.AttrN, b.AttrN, a.NAttrs, b.NAttrs); err != nil {
+		return fmt.Errorf("variable %q: %v", name, err)
+	}
+	va, err := readVar(a, ta)
+	if err != nil {
+		return err
+	}
+	vb, err := readVar(b, tb)
+	if err != nil {
+		return err
+	}
+	if i, ok := equalValues(va, vb); !ok {
+		shape, err := a.LenDims()
+		if err != nil {
+			return err
+		}
+		idx, err := netcdf.UnravelIndex(uint64(i), shape)
+		if err != nil {
+			return fmt.Errorf("variable %q has %d and %d values", name,
+				reflect.ValueOf(va).Len(), reflect.ValueOf(vb).Len())
+		}
+		return fmt.Errorf("variable %q differs at %v: %v and %v", name, idx,
+			reflect.ValueOf(va).Index(i), reflect.ValueOf(vb).Index(i))
+	}
+	return nil
+}
+
+func dimNames(v netcdf.Var) ([]string, error) {
+	dims, err := v.Dims()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(dims))
+	for i, d := range dims {
+		if names[i], err = d.Name(); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// readVar returns all the values of variable v, of type t.
+func readVar(v netcdf.Var, t netcdf.Type) (interface{}, error) {
+	n, err := v.Len()
+	if err != nil {
+		return nil, err
+	}
+	gt, ok := goTypes[t]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %v", t)
+	}
+	data := reflect.MakeSlice(reflect.SliceOf(gt), int(n), int(n)).Interface()
+	if n == 0 {
+		// Variables along an empty unlimited dimension have no values.
+		return data, nil
+	}
+	if _, err := v.ReadCtx(context.Background(), data, nil); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readAttr returns the type and the value of attribute a.
+func readAttr(a netcdf.Attr) (netcdf.Type, interface{}, error) {
+	t, err := a.Type()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := a.Len()
+	if err != nil {
+		return 0, nil, err
+	}
+	switch t {
+	case netcdf.BYTE:
+		val := make([]int8, n)
+		return t, val, a.ReadInt8s(val)
+	case netcdf.CHAR:
+		val := make([]byte, n)
+		return t, val, a.ReadBytes(val)
+	case netcdf.SHORT:
+		val := make([]int16, n)
+		return t, val, a.ReadInt16s(val)
+	case netcdf.INT:
+		val := make([]int32, n)
+		return t, val, a.ReadInt32s(val)
+	case netcdf.FLOAT:
+		val := make([]float32, n)
+		return t, val, a.ReadFloat32s(val)
+	case netcdf.DOUBLE:
+		val := make([]float64, n)
+		return t, val, a.ReadFloat64s(val)
+	case netcdf.UBYTE:
+		val := make([]uint8, n)
+		return t, val, a.ReadUint8s(val)
+	case netcdf.USHORT:
+		val := make([]uint16, n)
+		return t, val, a.ReadUint16s(val)
+	case netcdf.UINT:
+		val := make([]uint32, n)
+		return t, val, a.ReadUint32s(val)
+	case netcdf.INT64:
+		val := make([]int64, n)
+		return t, val, a.ReadInt64s(val)
+	case netcdf.UINT64:
+		val := make([]uint64, n)
+		return t, val, a.ReadUint64s(val)
+	}
+	return 0, nil, fmt.Errorf("attribute %q has unsupported type %v", a.Name(), t)
+}
+
+// equalValues reports whether slices a and b of the same type hold equal
+// values. If not, it returns the index of the first difference.
+func equalValues(a, b interface{}) (int, bool) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	n := va.Len()
+	if vb.Len() < n {
+		n = vb.Len()
+	}
+	for i := 0; i < n; i++ {
+		x, y := va.Index(i), vb.Index(i)
+		switch x.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if f, g := x.Float(), y.Float(); f != g && !(math.IsNaN(f) && math.IsNaN(g)) {
+				return i, false
+			}
+		default:
+			if x.Interface() != y.Interface() {
+				return i, false
+			}
+		}
+	}
+	if va.Len() != vb.Len() {
+		return n, false
+	}
+	return 0, true
+}
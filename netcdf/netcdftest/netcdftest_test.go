@@ -0,0 +1,153 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdftest
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+func fixture() Fixture {
+	return Fixture{
+		Dims: []Dim{{"time", 0}, {"x", 3}, {"len", 5}},
+		Attrs: []Attr{
+			{Name: "title", Value: "gopher"},
+			{Name: "version", Value: int32(2)},
+		},
+		Vars: []Var{
+			{
+				Name:  "temp",
+				Dims:  []string{"time", "x"},
+				Attrs: []Attr{{Name: "valid_range", Value: []float32{0, 400}}},
+				Data:  []float32{280, 281, 282, 283, float32(math.NaN()), 285},
+			},
+			{Name: "x", Type: netcdf.DOUBLE, Dims: []string{"x"}, Data: []int{10, 20, 30}},
+			{Name: "name", Dims: []string{"len"}, Data: "hello"},
+			{Name: "flags", Type: netcdf.UBYTE, Dims: []string{"time"}},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ds := MustBuild(t, fixture())
+	defer ds.Close()
+
+	time, err := ds.Dim("time")
+	if err != nil {
+		t.Fatalf("Dim failed: %v\n", err)
+	}
+	if n, err := time.Len(); err != nil || n != 2 {
+		t.Errorf("time has length %v, %v; expected 2\n", n, err)
+	}
+	title, err := netcdf.GetBytes(ds.Attr("title"))
+	if err != nil || string(title) != "gopher" {
+		t.Errorf("title is %q, %v\n", title, err)
+	}
+	version, err := netcdf.GetInt32s(ds.Attr("version"))
+	if err != nil || !reflect.DeepEqual(version, []int32{2}) {
+		t.Errorf("version is %v, %v; expected [2]\n", version, err)
+	}
+
+	v, err := ds.Var("temp")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	temp := make([]float32, 3)
+	if err := v.ReadFloat32Slice(temp, []uint64{1, 0}, []uint64{1, 3}); err != nil {
+		t.Fatalf("ReadFloat32Slice failed: %v\n", err)
+	}
+	if temp[0] != 283 || !math.IsNaN(float64(temp[1])) || temp[2] != 285 {
+		t.Errorf("second record of temp is %v\n", temp)
+	}
+	x, err := ds.Var("x")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if typ, err := x.Type(); err != nil || typ != netcdf.DOUBLE {
+		t.Errorf("x has type %v, %v; expected DOUBLE\n", typ, err)
+	}
+	if val, err := x.ReadFloat64At([]uint64{2}); err != nil || val != 30 {
+		t.Errorf("x[2] is %v, %v; expected 30\n", val, err)
+	}
+	name, err := ds.Var("name")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if b, err := netcdf.GetBytes(name); err != nil || string(b) != "hello" {
+		t.Errorf("name is %q, %v\n", b, err)
+	}
+	flags, err := ds.Var("flags")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if b, err := netcdf.GetUint8s(flags); err != nil || !reflect.DeepEqual(b, []uint8{255, 255}) {
+		t.Errorf("flags is %v, %v; expected fill values\n", b, err)
+	}
+
+	if _, err := ds.Var("nonexistent"); err != netcdf.ENOTVAR {
+		t.Errorf("Var returned %v; expected %v\n", err, netcdf.ENOTVAR)
+	}
+	if err := v.WriteFloat32s(make([]float32, 2)); err == nil {
+		t.Errorf("writing too few values succeeded\n")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	for _, tc := range []struct {
+		f   Fixture
+		err string
+	}{
+		{Fixture{Vars: []Var{{Name: "v", Dims: []string{"x"}, Data: []int8{1}}}}, `unknown dimension "x"`},
+		{Fixture{Vars: []Var{{Name: "v"}}}, "no type or value"},
+		{Fixture{Vars: []Var{{Name: "v", Data: []string{"a"}}}}, "unsupported value type []string"},
+		{Fixture{Vars: []Var{{Name: "v", Type: netcdf.INT, Data: "a"}}}, "string value for type INT"},
+		{Fixture{Vars: []Var{{Name: "v", Type: netcdf.CHAR, Data: []int16{1}}}}, "[]int16 value for type CHAR"},
+		{Fixture{Dims: []Dim{{"x", 2}}, Vars: []Var{{Name: "v", Dims: []string{"x"}, Data: []int8{1}}}}, "1 values don't fill shape [2]"},
+		{Fixture{Dims: []Dim{{"x", 2}, {"x", 3}}}, `dimension "x"`},
+		{Fixture{Attrs: []Attr{{Name: "a"}}}, `attribute "a": no type or value`},
+	} {
+		_, err := Build(tc.f)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Build(%+v) returned %v; expected error containing %q\n", tc.f, err, tc.err)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := MustBuild(t, fixture())
+	defer a.Close()
+	b := MustBuild(t, fixture())
+	defer b.Close()
+	AssertEqual(t, a, b)
+
+	for _, tc := range []struct {
+		change func(f *Fixture)
+		err    string
+	}{
+		{func(f *Fixture) { f.Dims[2].Len = 6; f.Vars[2].Data = "hello!" }, "dimension 2 is len(5) and len(6)"},
+		{func(f *Fixture) { f.Dims[0].Len = 2 }, "dimension 0 is time(2, unlimited) and time(2)"},
+		{func(f *Fixture) { f.Attrs = f.Attrs[:1] }, "global attributes: 2 and 1 attributes"},
+		{func(f *Fixture) { f.Attrs[1].Value = int16(2) }, `attribute "version" has types INT and SHORT`},
+		{func(f *Fixture) { f.Attrs[0].Value = "gophers" }, `attribute "title" differs at index 6`},
+		{func(f *Fixture) { f.Vars[1].Name = "y" }, `variable 1 is named "x" and "y"`},
+		{func(f *Fixture) { f.Vars[1].Type = netcdf.FLOAT }, `variable "x" has types DOUBLE and FLOAT`},
+		{func(f *Fixture) { f.Vars[0].Attrs = nil }, `variable "temp": attributes: 1 and 0 attributes`},
+		{func(f *Fixture) { f.Vars[0].Data = []float32{280, 281, 282, 283, 284, 285} }, `variable "temp" differs at [1 1]: NaN and 284`},
+		{func(f *Fixture) { f.Vars = f.Vars[:3] }, "datasets have 4 and 3 variables"},
+	} {
+		f := fixture()
+		tc.change(&f)
+		c := MustBuild(t, f)
+		err := Equal(a, c)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Equal returned %v; expected error containing %q\n", err, tc.err)
+		}
+		c.Close()
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+// +build !cgo
+
+package netcdf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// errNoCgo is returned when opening or creating a dataset file, which is
+// done through the C library, unless package cdf is imported.
+var errNoCgo = errors.New("netcdf: the C library is not available without cgo")
+
+var errorMessages = map[Error]string{
+	EBADID:       "NetCDF: Not a valid ID",
+	EEXIST:       "NetCDF: File exists && NC_NOCLOBBER",
+	EINVAL:       "NetCDF: Invalid argument",
+	EPERM:        "NetCDF: Write to read only",
+	ENOTINDEFINE: "NetCDF: Operation not allowed in data mode",
+	EINDEFINE:    "NetCDF: Operation not allowed in define mode",
+	EINVALCOORDS: "NetCDF: Index exceeds dimension bound",
+	ENAMEINUSE:   "NetCDF: String match to name in use",
+	ENOTATT:      "NetCDF: Attribute not found",
+	EBADTYPE:     "NetCDF: Not a valid data type or _FillValue type mismatch",
+	EBADDIM:      "NetCDF: Invalid dimension ID or name",
+	EUNLIMPOS:    "NetCDF: NC_UNLIMITED in the wrong index",
+	ENOTVAR:      "NetCDF: Variable not found",
+	ENOTNC:       "NetCDF: Unknown file format",
+	EUNLIMIT:     "NetCDF: NC_UNLIMITED size already in use",
+	ECHAR:        "NetCDF: Attempt to convert between text & numbers",
+	EEDGE:        "NetCDF: Start+count exceeds dimension bound",
+	ESTRIDE:      "NetCDF: Illegal stride",
+	EBADNAME:     "NetCDF: Name contains illegal characters",
+	ERANGE:       "NetCDF: Numeric conversion not representable",
+	EVARSIZE:     "NetCDF: One or more variable sizes violate format constraints",
+	EDIMSIZE:     "NetCDF: Invalid dimension size",
+	ENOTNC4:      "NetCDF: Attempting netcdf-4 operation on netcdf-3 file",
+	ENOGRP:       "NetCDF: No group found.",
+}
+
+func strerror(e Error) string {
+	if s, ok := errorMessages[e]; ok {
+		return s
+	}
+	return fmt.Sprintf("NetCDF: error %d", int(e))
+}
+
+// Version returns a string identifying the version of the netCDF library,
+// and when it was built. It's empty if the C library isn't available.
+func Version() string {
+	return ""
+}
+
+func libMeta() map[string]int {
+	return nil
+}
+
+// createDriver and openDriver use package cdf, if it's imported, which
+// only supports the classic formats.
+func createDriver(path string, mode FileMode) (driver.Dataset, error) {
+	if driver.CreateFile == nil {
+		return nil, errNoCgo
+	}
+	return driver.CreateFile(path, int(mode))
+}
+
+func createDriverWithOptions(path string, mode FileMode, initialSize, bufferSize uint64) (driver.Dataset, error) {
+	return createDriver(path, mode)
+}
+
+func openDriver(path string, mode FileMode) (driver.Dataset, error) {
+	if driver.OpenFile == nil {
+		return nil, errNoCgo
+	}
+	return driver.OpenFile(path, int(mode))
+}
+
+func copyDriverAttr(src driver.Dataset, varid int, name string, dst driver.Dataset, dstVarid int) (ok bool, err error) {
+	return false, nil
+}
+
+func rawTypeSize(d driver.Dataset, t Type) (size uint64, ok bool, err error) {
+	return 0, false, nil
+}
+
+func transferDriverRaw(d driver.Dataset, varid int, start, count []uint64, buf []byte, write bool) (ok bool, err error) {
+	return false, nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"sync"
+)
+
+// minPieceLen is the number of values a ParallelReader tries to read in
+// each sub-request, so that small chunks don't result in many tiny reads.
+const minPieceLen = 1 << 16
+
+// ParallelReader reads slices of a variable using a pool of workers. Each
+// worker has its own handle to the dataset. A read is split into pieces
+// aligned to the chunks of the variable, which are read by the workers and
+// assembled into the caller's buffer.
+//
+// Calls into the C library are still serialized (unless the netcdf_threadsafe
+// build tag is used), but decompression of one piece can overlap with
+// copying of others.
+type ParallelReader struct {
+	ds    []Dataset
+	vars  []Var
+	piece []uint64 // shape of the pieces, in number of values
+}
+
+// NewParallelReader opens the dataset at path once for each of the workers
+// and returns a ParallelReader for the variable named name. The variable
+// must have at least one dimension.
+func NewParallelReader(path, name string, workers int) (r *ParallelReader, err error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("invalid number of workers %d", workers)
+	}
+	r = &ParallelReader{}
+	defer func() {
+		if err != nil {
+			r.Close()
+			r = nil
+		}
+	}()
+	for i := 0; i < workers; i++ {
+		ds, err := OpenFile(path, NOWRITE)
+		if err != nil {
+			return r, err
+		}
+		r.ds = append(r.ds, ds)
+		v, err := ds.Var(name)
+		if err != nil {
+			return r, err
+		}
+		r.vars = append(r.vars, v)
+	}
+	shape, err := r.vars[0].LenDims()
+	if err != nil {
+		return r, err
+	}
+	if len(shape) == 0 {
+		return r, fmt.Errorf("variable %s has no dimensions", name)
+	}
+	contiguous, chunks, err := r.vars[0].Chunking()
+	if err != nil {
+		return r, err
+	}
+	if contiguous {
+		// Treat each row along the first dimension as a chunk.
+		chunks = append([]uint64{1}, shape[1:]...)
+	}
+	r.piece = pieceShape(shape, chunks, minPieceLen)
+	return r, nil
+}
+
+// Close closes all the dataset handles of the reader.
+func (r *ParallelReader) Close() (err error) {
+	for _, ds := range r.ds {
+		if e := ds.Close(); err == nil {
+			err = e
+		}
+	}
+	r.ds = nil
+	r.vars = nil
+	return
+}
+
+// Var returns the variable of the first worker. It can be used to inquire
+// about the variable, but isn't safe to use after Close.
+func (r *ParallelReader) Var() Var {
+	return r.vars[0]
+}
+
+// pieceShape returns the shape of the pieces in which a variable of the
+// given shape and chunk shape is read. Neighboring chunks are grouped,
+// starting from the innermost dimension, until a piece holds at least
+// minLen values or covers the whole variable.
+func pieceShape(shape, chunks []uint64, minLen uint64) []uint64 {
+	piece := make([]uint64, len(chunks))
+	for i, c := range chunks {
+		piece[i] = c
+		if piece[i] == 0 {
+			piece[i] = 1
+		}
+	}
+	for i := len(piece) - 1; i >= 0; i-- {
+		for product(piece) < minLen && piece[i] < shape[i] {
+			piece[i] *= 2
+		}
+		if piece[i] > shape[i] && shape[i] > 0 {
+			piece[i] = shape[i]
+		}
+	}
+	return piece
+}
+
+// slab is a hyperslab of a variable.
+type slab struct {
+	start, count []uint64
+}
+
+// split calls f for each piece of the hyperslab s. The pieces are the
+// intersection of s with a grid of the given piece shape.
+func (s slab) split(piece []uint64, f func(p slab)) {
+	n := len(s.start)
+	if product(s.count) == 0 {
+		return
+	}
+	p := slab{start: make([]uint64, n), count: make([]uint64, n)}
+	var walk func(d int)
+	walk = func(d int) {
+		if d == n {
+			f(slab{
+				start: append([]uint64(nil), p.start...),
+				count: append([]uint64(nil), p.count...),
+			})
+			return
+		}
+		end := s.start[d] + s.count[d]
+		for i := s.start[d]; i < end; {
+			next := (i/piece[d] + 1) * piece[d]
+			if next > end {
+				next = end
+			}
+			p.start[d], p.count[d] = i, next-i
+			walk(d + 1)
+			i = next
+		}
+	}
+	walk(0)
+}
+
+// runs calls f for each contiguous run of values of the piece p, which is
+// contained in s. Dst is the offset of the run in a buffer holding s, src
+// is the offset in a buffer holding p, and n is the length of the run.
+func (s slab) runs(p slab, f func(dst, src, n int)) {
+	// Merge the innermost dimensions that p covers entirely into
+	// one run, since they are contiguous in both buffers.
+	last := len(s.count) - 1
+	n := p.count[last]
+	for last > 0 && p.count[last] == s.count[last] {
+		last--
+		n *= p.count[last]
+	}
+	strides := make([]uint64, len(s.count))
+	stride := uint64(1)
+	for i := len(s.count) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= s.count[i]
+	}
+	idx := make([]uint64, last)
+	for src := uint64(0); ; src += n {
+		dst := uint64(0)
+		for i := range s.count {
+			off := p.start[i] - s.start[i]
+			if i < last {
+				off += idx[i]
+			}
+			dst += off * strides[i]
+		}
+		f(int(dst), int(src), int(n))
+
+		// Advance to the next run in row-major order.
+		i := last - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < p.count[i] {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
+
+// read splits the hyperslab given by start and count into pieces and calls
+// f for each of them from the workers, passing the worker's variable.
+// It returns the first error returned by f.
+func (r *ParallelReader) read(start, count []uint64, f func(v Var, p slab) error) error {
+	pieces := make(chan slab)
+	done := make(chan struct{})
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		err     error
+	)
+	for _, v := range r.vars {
+		wg.Add(1)
+		go func(v Var) {
+			defer wg.Done()
+			for p := range pieces {
+				if e := f(v, p); e != nil {
+					errOnce.Do(func() {
+						err = e
+						close(done)
+					})
+				}
+			}
+		}(v)
+	}
+	slab{start, count}.split(r.piece, func(p slab) {
+		select {
+		case pieces <- p:
+		case <-done:
+		}
+	})
+	close(pieces)
+	wg.Wait()
+	return err
+}
+
+// check returns an error if the reader is closed, or if data of type t and
+// length n can't hold the slice given by start and count.
+func (r *ParallelReader) check(t Type, n int, start, count []uint64) error {
+	if len(r.vars) == 0 {
+		return fmt.Errorf("parallel reader is closed")
+	}
+	return okDataSlice(r.vars[0], t, n, start, count)
+}
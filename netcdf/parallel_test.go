@@ -0,0 +1,314 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestPieceShape(t *testing.T) {
+	tests := []struct {
+		shape, chunks []uint64
+		minLen        uint64
+		piece         []uint64
+	}{
+		{[]uint64{100, 100}, []uint64{10, 10}, 1, []uint64{10, 10}},
+		{[]uint64{100, 100}, []uint64{10, 10}, 400, []uint64{10, 40}},
+		{[]uint64{100, 100}, []uint64{10, 10}, 2000, []uint64{20, 100}},
+		{[]uint64{100, 100}, []uint64{10, 10}, 1e6, []uint64{100, 100}},
+		{[]uint64{0, 30}, []uint64{1, 30}, 100, []uint64{1, 30}},
+		{[]uint64{7, 3}, []uint64{1, 3}, 1 << 16, []uint64{7, 3}},
+	}
+	for _, test := range tests {
+		piece := pieceShape(test.shape, test.chunks, test.minLen)
+		if !reflect.DeepEqual(piece, test.piece) {
+			t.Errorf("pieceShape(%v, %v, %v) is %v; expected %v\n",
+				test.shape, test.chunks, test.minLen, piece, test.piece)
+		}
+	}
+}
+
+func TestSlabPieces(t *testing.T) {
+	tests := []struct {
+		s     slab
+		piece []uint64
+	}{
+		{slab{[]uint64{0}, []uint64{10}}, []uint64{3}},
+		{slab{[]uint64{1, 2}, []uint64{6, 5}}, []uint64{4, 3}},
+		{slab{[]uint64{0, 0}, []uint64{6, 5}}, []uint64{2, 5}},
+		{slab{[]uint64{3, 1, 2}, []uint64{4, 5, 6}}, []uint64{2, 2, 4}},
+		{slab{[]uint64{0, 0, 0}, []uint64{4, 5, 6}}, []uint64{1, 5, 6}},
+	}
+	for _, test := range tests {
+		// Fill a buffer for s by copying each piece into it, where the
+		// value of each element of a piece is its offset within s.
+		n := product(test.s.count)
+		buf := make([]int, n)
+		for i := range buf {
+			buf[i] = -1
+		}
+		test.s.split(test.piece, func(p slab) {
+			for i := range p.start {
+				if p.start[i]/test.piece[i] != (p.start[i]+p.count[i]-1)/test.piece[i] {
+					t.Errorf("piece %v of %v crosses the grid %v\n", p, test.s, test.piece)
+				}
+			}
+			src := make([]int, product(p.count))
+			for i := range src {
+				coord, _ := UnravelIndex(uint64(i), append([]uint64(nil), p.count...))
+				off := 0
+				for d := range coord {
+					off = off*int(test.s.count[d]) + int(p.start[d]-test.s.start[d]+coord[d])
+				}
+				src[i] = off
+			}
+			test.s.runs(p, func(dst, s, n int) {
+				copy(buf[dst:dst+n], src[s:s+n])
+			})
+		})
+		for i, v := range buf {
+			if v != i {
+				t.Errorf("value at position %d of %v split by %v is %d; expected %d\n",
+					i, test.s, test.piece, v, i)
+				break
+			}
+		}
+	}
+}
+
+func TestParallelReader(t *testing.T) {
+	skipWithoutC(t)
+	for _, ft := range getFileTests() {
+		if len(ft.DimNames) == 0 {
+			continue
+		}
+		f, err := ioutil.TempFile("", "netcdf_test")
+		if err != nil {
+			t.Fatalf("creating temporary file failed: %v\n", err)
+		}
+		createFile(t, f.Name(), &ft)
+		r, err := NewParallelReader(f.Name(), ft.VarName, 3)
+		if err != nil {
+			t.Fatalf("NewParallelReader failed: %v\n", err)
+		}
+		n, err := r.Var().LenDims()
+		if err != nil {
+			t.Fatalf("Var.LenDims failed: %v\n", err)
+		}
+		switch ft.DataType {
+		default:
+			t.Fatalf("unexpected type %v\n", ft.DataType)
+		case UINT64:
+			err = testParallelReadUint64Slice(r, n)
+		case INT64:
+			err = testParallelReadInt64Slice(r, n)
+		case DOUBLE:
+			err = testParallelReadFloat64Slice(r, n)
+		case UINT:
+			err = testParallelReadUint32Slice(r, n)
+		case INT:
+			err = testParallelReadInt32Slice(r, n)
+		case FLOAT:
+			err = testParallelReadFloat32Slice(r, n)
+		case USHORT:
+			err = testParallelReadUint16Slice(r, n)
+		case SHORT:
+			err = testParallelReadInt16Slice(r, n)
+		case UBYTE:
+			err = testParallelReadUint8Slice(r, n)
+		case BYTE:
+			err = testParallelReadInt8Slice(r, n)
+		case CHAR:
+			err = testParallelReadBytesSlice(r, n)
+		}
+		if err != nil {
+			t.Errorf("%v: parallel read failed: %v\n", ft.DataType, err)
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("Close failed: %v\n", err)
+		}
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}
+}
+
+// createChunkedFile creates a dataset at filename containing a compressed
+// variable "gopher" of the given shape and chunk shape, filled with
+// testWriteFloat64s data.
+func createChunkedFile(filename string, shape, chunks []uint64) error {
+	ds, err := CreateFile(filename, CLOBBER|NETCDF4)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+	dims := make([]Dim, len(shape))
+	for i, n := range shape {
+		if dims[i], err = ds.AddDim(fmt.Sprintf("dim%d", i), n); err != nil {
+			return err
+		}
+	}
+	v, err := ds.AddVar("gopher", DOUBLE, dims)
+	if err != nil {
+		return err
+	}
+	if err := v.SetChunking(false, chunks); err != nil {
+		return err
+	}
+	if err := v.SetCompression(true, true, 1); err != nil {
+		return err
+	}
+	return testWriteFloat64s(v, product(shape))
+}
+
+func TestParallelReaderChunked(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	shape := []uint64{20, 300, 40}
+	if err := createChunkedFile(f.Name(), shape, []uint64{3, 70, 40}); err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	r, err := NewParallelReader(f.Name(), "gopher", 4)
+	if err != nil {
+		t.Fatalf("NewParallelReader failed: %v\n", err)
+	}
+	defer r.Close()
+	if err := testParallelReadFloat64Slice(r, shape); err != nil {
+		t.Errorf("parallel read failed: %v\n", err)
+	}
+
+	// The first error of the workers is returned.
+	errPiece := fmt.Errorf("bad piece")
+	err = r.read([]uint64{0, 0, 0}, shape, func(v Var, p slab) error {
+		if p.start[0] == 6 {
+			return errPiece
+		}
+		return nil
+	})
+	if err != errPiece {
+		t.Errorf("parallel read returned error %v; expected %v\n", err, errPiece)
+	}
+}
+
+func TestParallelReaderErrors(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	ft := FileTest{VarName: "gopher", DataType: INT}
+	createFile(t, f.Name(), &ft)
+
+	if _, err := NewParallelReader(f.Name(), "gopher", 0); err == nil {
+		t.Errorf("NewParallelReader with no workers succeeded\n")
+	}
+	if _, err := NewParallelReader(f.Name(), "gopher", 2); err == nil {
+		t.Errorf("NewParallelReader for a scalar variable succeeded\n")
+	}
+	if _, err := NewParallelReader(f.Name(), "nonexistent", 2); err != Error(-49) {
+		t.Errorf("NewParallelReader for nonexistent variable returned %v; expected %v\n",
+			err, Error(-49))
+	}
+}
+
+func TestChunking(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	shape, chunks := []uint64{20, 30}, []uint64{4, 30}
+	if err := createChunkedFile(f.Name(), shape, chunks); err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	ds, err := OpenFile(f.Name(), NOWRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer ds.Close()
+	v, err := ds.Var("gopher")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	contiguous, sizes, err := v.Chunking()
+	if err != nil {
+		t.Fatalf("Chunking failed: %v\n", err)
+	}
+	if contiguous || !reflect.DeepEqual(sizes, chunks) {
+		t.Errorf("Chunking is (%v, %v); expected (%v, %v)\n", contiguous, sizes, false, chunks)
+	}
+}
+
+func benchmarkRead(b *testing.B, workers int) {
+	skipWithoutC(b)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		b.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer os.Remove(f.Name())
+	shape := []uint64{32, 256, 256}
+	if err := createChunkedFile(f.Name(), shape, []uint64{4, 64, 64}); err != nil {
+		b.Fatalf("creating file failed: %v\n", err)
+	}
+	start := make([]uint64, len(shape))
+	data := make([]float64, product(shape))
+	b.SetBytes(int64(8 * len(data)))
+	b.ResetTimer()
+
+	if workers == 0 {
+		ds, err := OpenFile(f.Name(), NOWRITE)
+		if err != nil {
+			b.Fatalf("Open failed: %v\n", err)
+		}
+		defer ds.Close()
+		v, err := ds.Var("gopher")
+		if err != nil {
+			b.Fatalf("Var failed: %v\n", err)
+		}
+		for i := 0; i < b.N; i++ {
+			if err := v.ReadFloat64Slice(data, start, shape); err != nil {
+				b.Fatalf("ReadFloat64Slice failed: %v\n", err)
+			}
+		}
+		return
+	}
+	r, err := NewParallelReader(f.Name(), "gopher", workers)
+	if err != nil {
+		b.Fatalf("NewParallelReader failed: %v\n", err)
+	}
+	defer r.Close()
+	for i := 0; i < b.N; i++ {
+		if err := r.ReadFloat64Slice(data, start, shape); err != nil {
+			b.Fatalf("ReadFloat64Slice failed: %v\n", err)
+		}
+	}
+}
+
+func BenchmarkReadFloat64Slice(b *testing.B)           { benchmarkRead(b, 0) }
+func BenchmarkParallelReadFloat64Slice1(b *testing.B)  { benchmarkRead(b, 1) }
+func BenchmarkParallelReadFloat64Slice4(b *testing.B)  { benchmarkRead(b, 4) }
+func BenchmarkParallelReadFloat64Slice16(b *testing.B) { benchmarkRead(b, 16) }
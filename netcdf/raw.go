@@ -0,0 +1,182 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ReadRaw reads a slice of variable v into buf, whatever the type of v.
+// The values are stored as by nc_get_vara of the C library: in the type
+// of v, in the byte order of the machine, without conversion. Buf must
+// have space for all the values, i.e. len(buf) must be at least
+// product(count) times the size of the type of v, as reported by
+// nc_inq_type. The slice is specified by start and count, as for
+// ReadFloat64Slice. RawValues and RawSlice turn the bytes of atomic types
+// back into values.
+//
+// Datasets opened through the C library support enum, opaque and compound
+// types, whose values are laid out as in C. Values of STRING and VLEN
+// types hold pointers, so they can't be read raw. Other datasets only
+// support atomic types.
+func (v Var) ReadRaw(buf []byte, start, count []uint64) error {
+	return v.transferRaw(buf, start, count, false)
+}
+
+// WriteRaw writes buf as a slice of variable v, whatever the type of v.
+// Buf holds values of the type of v in the byte order of the machine, as
+// read by ReadRaw, or as returned by RawBytes. The slice is specified by
+// start and count, as for WriteFloat64Slice. The same types as for
+// ReadRaw are supported.
+func (v Var) WriteRaw(buf []byte, start, count []uint64) error {
+	return v.transferRaw(buf, start, count, true)
+}
+
+func (v Var) transferRaw(buf []byte, start, count []uint64, write bool) error {
+	t, err := v.Type()
+	if err != nil {
+		return err
+	}
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	size := t.Size()
+	if s, ok, err := rawTypeSize(d, t); ok {
+		if err != nil {
+			return err
+		}
+		size = s
+	}
+	if size == 0 {
+		return fmt.Errorf("unsupported type %v", t)
+	}
+	if uint64(len(buf))%size != 0 {
+		return fmt.Errorf("buffer length %d is not a multiple of the size %d of %v values", len(buf), size, t)
+	}
+	if err := okDataSlice(v, t, int(uint64(len(buf))/size), start, count); err != nil {
+		return err
+	}
+	n := product(count) * size
+	if ok, err := transferDriverRaw(d, v.id, start, count, buf[:n], write); ok {
+		return err
+	}
+	// Other drivers take slices of the Go type of t.
+	data, err := RawValues(t, buf[:n])
+	if err != nil {
+		// Buf isn't aligned for values of type t, so the values go
+		// through a copy.
+		if data, err = makeSlice(t, product(count)); err != nil {
+			return err
+		}
+		tmp := rawBytes(data, n)
+		if write {
+			copy(tmp, buf)
+			return d.PutVars(v.id, int(t), start, count, nil, data)
+		}
+		if err := d.GetVars(v.id, int(t), start, count, nil, data); err != nil {
+			return err
+		}
+		copy(buf, tmp)
+		return nil
+	}
+	if write {
+		return d.PutVars(v.id, int(t), start, count, nil, data)
+	}
+	return d.GetVars(v.id, int(t), start, count, nil, data)
+}
+
+// RawValues returns the values of type t held by buf, as read by ReadRaw,
+// as a slice of the Go type of t, such as []float32 for FLOAT and []byte
+// for CHAR. The slice shares the memory of buf. Buf must hold a whole
+// number of values, and be aligned for the Go type.
+func RawValues(t Type, buf []byte) (interface{}, error) {
+	switch t {
+	case BYTE:
+		return RawSlice[int8](buf)
+	case CHAR, UBYTE:
+		return buf, nil
+	case SHORT:
+		return RawSlice[int16](buf)
+	case USHORT:
+		return RawSlice[uint16](buf)
+	case INT:
+		return RawSlice[int32](buf)
+	case UINT:
+		return RawSlice[uint32](buf)
+	case FLOAT:
+		return RawSlice[float32](buf)
+	case INT64:
+		return RawSlice[int64](buf)
+	case UINT64:
+		return RawSlice[uint64](buf)
+	case DOUBLE:
+		return RawSlice[float64](buf)
+	}
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
+
+// RawSlice returns the values held by buf, as read by ReadRaw, as a
+// []T sharing the memory of buf. Buf must hold a whole number of values,
+// and be aligned for T.
+func RawSlice[T Number](buf []byte) ([]T, error) {
+	var x T
+	size := int(unsafe.Sizeof(x))
+	if len(buf)%size != 0 {
+		return nil, fmt.Errorf("buffer length %d is not a multiple of the size %d of %T values", len(buf), size, x)
+	}
+	if len(buf) == 0 {
+		return []T{}, nil
+	}
+	if uintptr(unsafe.Pointer(&buf[0]))%unsafe.Alignof(x) != 0 {
+		return nil, fmt.Errorf("buffer isn't aligned for %T values", x)
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&buf[0])), len(buf)/size), nil
+}
+
+// RawBytes returns the memory holding the values of data, in the byte
+// order of the machine, as WriteRaw takes them. The returned slice shares
+// the memory of data.
+func RawBytes[T Number](data []T) []byte {
+	if len(data) == 0 {
+		return []byte{}
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&data[0])), len(data)*int(unsafe.Sizeof(data[0])))
+}
+
+// rawBytes returns the n bytes of memory holding the values of data, a
+// slice of numbers.
+func rawBytes(data interface{}, n uint64) []byte {
+	if n == 0 {
+		return []byte{}
+	}
+	var p unsafe.Pointer
+	switch d := data.(type) {
+	case []int8:
+		p = unsafe.Pointer(&d[0])
+	case []uint8:
+		p = unsafe.Pointer(&d[0])
+	case []int16:
+		p = unsafe.Pointer(&d[0])
+	case []uint16:
+		p = unsafe.Pointer(&d[0])
+	case []int32:
+		p = unsafe.Pointer(&d[0])
+	case []uint32:
+		p = unsafe.Pointer(&d[0])
+	case []int64:
+		p = unsafe.Pointer(&d[0])
+	case []uint64:
+		p = unsafe.Pointer(&d[0])
+	case []float32:
+		p = unsafe.Pointer(&d[0])
+	case []float64:
+		p = unsafe.Pointer(&d[0])
+	default:
+		panic(fmt.Sprintf("unsupported data type %T", data))
+	}
+	return unsafe.Slice((*byte)(p), n)
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+func TestTypeSize(t *testing.T) {
+	for typ, want := range map[netcdf.Type]uint64{
+		netcdf.BYTE:   1,
+		netcdf.CHAR:   1,
+		netcdf.SHORT:  2,
+		netcdf.FLOAT:  4,
+		netcdf.UINT:   4,
+		netcdf.DOUBLE: 8,
+		netcdf.UINT64: 8,
+		netcdf.STRING: 0,
+	} {
+		if got := typ.Size(); got != want {
+			t.Errorf("%v.Size() is %d; want %d\n", typ, got, want)
+		}
+	}
+}
+
+func TestReadWriteRaw(t *testing.T) {
+	src := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "y", Len: 2}, {Name: "x", Len: 3}},
+		Vars: []netcdftest.Var{
+			{Name: "temp", Dims: []string{"y", "x"}, Data: []float64{1.5, 2, 3, 4, 5, 6}},
+			{Name: "flag", Dims: []string{"y", "x"}, Data: []int16{1, -2, 3, 4, 5, 6}},
+			{Name: "name", Type: netcdf.CHAR, Dims: []string{"x"}, Data: []byte("abc")},
+		},
+	})
+	defer src.Close()
+	dst := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "y", Len: 2}, {Name: "x", Len: 3}},
+		Vars: []netcdftest.Var{
+			{Name: "temp", Type: netcdf.DOUBLE, Dims: []string{"y", "x"}},
+			{Name: "flag", Type: netcdf.SHORT, Dims: []string{"y", "x"}},
+			{Name: "name", Type: netcdf.CHAR, Dims: []string{"x"}},
+		},
+	})
+	defer dst.Close()
+
+	// Copy all the variables without caring about their types.
+	for i := 0; i < 3; i++ {
+		v, w := src.VarN(i), dst.VarN(i)
+		typ, err := v.Type()
+		if err != nil {
+			t.Fatalf("Type failed: %v\n", err)
+		}
+		shape, err := v.LenDims()
+		if err != nil {
+			t.Fatalf("LenDims failed: %v\n", err)
+		}
+		n, err := v.Len()
+		if err != nil {
+			t.Fatalf("Len failed: %v\n", err)
+		}
+		buf := make([]byte, n*typ.Size())
+		if err := v.ReadRaw(buf, make([]uint64, len(shape)), shape); err != nil {
+			t.Fatalf("ReadRaw failed: %v\n", err)
+		}
+		if err := w.WriteRaw(buf, make([]uint64, len(shape)), shape); err != nil {
+			t.Fatalf("WriteRaw failed: %v\n", err)
+		}
+	}
+	netcdftest.AssertEqual(t, dst, src)
+
+	// Read a slice, into a buffer that isn't aligned for float64 values.
+	v := src.VarN(0)
+	buf := make([]byte, 17)
+	if err := v.ReadRaw(buf[1:], []uint64{1, 1}, []uint64{1, 2}); err != nil {
+		t.Fatalf("ReadRaw failed: %v\n", err)
+	}
+	aligned := make([]byte, 16)
+	copy(aligned, buf[1:])
+	got, err := netcdf.RawSlice[float64](aligned)
+	if err != nil {
+		t.Fatalf("RawSlice failed: %v\n", err)
+	}
+	if want := []float64{5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("read %v; want %v\n", got, want)
+	}
+
+	// Write from typed values.
+	if err := dst.VarN(1).WriteRaw(netcdf.RawBytes([]int16{7, 8}), []uint64{0, 1}, []uint64{1, 2}); err != nil {
+		t.Fatalf("WriteRaw failed: %v\n", err)
+	}
+	flags, err := netcdf.GetInt16s(dst.VarN(1))
+	if want := []int16{1, 7, 8, 4, 5, 6}; err != nil || !reflect.DeepEqual(flags, want) {
+		t.Errorf("flags are %v (error %v); want %v\n", flags, err, want)
+	}
+
+	for _, tc := range []struct {
+		buf          []byte
+		start, count []uint64
+	}{
+		{make([]byte, 7), []uint64{0, 0}, []uint64{1, 1}}, // not whole values
+		{make([]byte, 8), []uint64{0, 0}, []uint64{1, 2}}, // too short
+		{make([]byte, 8), []uint64{0, 3}, []uint64{1, 1}}, // out of range
+		{make([]byte, 8), []uint64{0}, []uint64{1}},       // wrong rank
+	} {
+		if err := v.ReadRaw(tc.buf, tc.start, tc.count); err == nil {
+			t.Errorf("ReadRaw(%d bytes, %v, %v) succeeded\n", len(tc.buf), tc.start, tc.count)
+		}
+	}
+}
+
+func TestRawValues(t *testing.T) {
+	b := netcdf.RawBytes([]int32{1, -1})
+	if len(b) != 8 {
+		t.Fatalf("RawBytes returned %d bytes; want 8\n", len(b))
+	}
+	vals, err := netcdf.RawValues(netcdf.INT, b)
+	if err != nil {
+		t.Fatalf("RawValues failed: %v\n", err)
+	}
+	if want := []int32{1, -1}; !reflect.DeepEqual(vals, want) {
+		t.Errorf("RawValues returned %v; want %v\n", vals, want)
+	}
+	// The values share the memory of the bytes.
+	vals.([]int32)[0] = 2
+	if got, _ := netcdf.RawSlice[int32](b); got[0] != 2 {
+		t.Errorf("RawSlice returned %v after setting the first value to 2\n", got)
+	}
+	if vals, err := netcdf.RawValues(netcdf.CHAR, []byte("ab")); err != nil || string(vals.([]byte)) != "ab" {
+		t.Errorf("RawValues(CHAR) returned %v, %v\n", vals, err)
+	}
+
+	if _, err := netcdf.RawValues(netcdf.STRING, b); err == nil {
+		t.Errorf("RawValues(STRING) succeeded\n")
+	}
+	if _, err := netcdf.RawSlice[float64](b[:7]); err == nil {
+		t.Errorf("RawSlice of 7 bytes succeeded\n")
+	}
+	if _, err := netcdf.RawSlice[int32](b[1:5]); err == nil {
+		t.Errorf("RawSlice of a misaligned buffer succeeded\n")
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Selection is a strided slice of a variable, given by start, count and
+// stride as for ReadFloat64StridedSlice.
+type Selection struct {
+	Start  []uint64
+	Count  []uint64
+	Stride []int64
+
+	// Shape is the shape of the selected values. It's Count without the
+	// dimensions selected by a single index.
+	Shape []uint64
+}
+
+// Len returns the number of values selected by s.
+func (s Selection) Len() uint64 {
+	return product(s.Count)
+}
+
+// Select returns the selection of variable v given by expr, a comma
+// separated list of numpy-like index expressions, one per dimension of v:
+//
+//   - i selects index i, and drops the dimension from the shape of the
+//     selection. Negative indices count from the end, so -1 is the last
+//     index.
+//   - start:stop and start:stop:step select the indices from start up to
+//     stop (excluded), every step indices. Start defaults to 0, stop to
+//     the length of the dimension and step to 1, so ":" selects the whole
+//     dimension. Negative start and stop count from the end, and both are
+//     clamped to the dimension. Step must be positive.
+//   - ... stands for ":" for as many dimensions as needed. It can appear
+//     only once.
+//
+// Dimensions missing at the end of expr are selected entirely. For example,
+// for a variable of shape [20 5 8], "0:10:2, :, 5" selects 5 values of the
+// first dimension, all the values of the second one and the sixth value of
+// the third one, so the selection has shape [5 5].
+func (v Var) Select(expr string) (Selection, error) {
+	shape, err := v.LenDims()
+	if err != nil {
+		return Selection{}, err
+	}
+	return parseSelection(expr, shape)
+}
+
+// parseSelection returns the selection given by expr for an array of the
+// given shape, as described for Select.
+func parseSelection(expr string, shape []uint64) (Selection, error) {
+	var items []string
+	if strings.TrimSpace(expr) != "" {
+		items = strings.Split(expr, ",")
+	}
+	ellipsis := -1
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+		if items[i] == "..." {
+			if ellipsis >= 0 {
+				return Selection{}, fmt.Errorf("selection %q has more than one ellipsis", expr)
+			}
+			ellipsis = i
+		}
+	}
+	if ellipsis >= 0 {
+		n := len(shape) - (len(items) - 1)
+		if n < 0 {
+			n = 0
+		}
+		full := make([]string, n)
+		for i := range full {
+			full[i] = ":"
+		}
+		items = append(items[:ellipsis], append(full, items[ellipsis+1:]...)...)
+	}
+	if len(items) > len(shape) {
+		return Selection{}, fmt.Errorf("selection %q has %d dimensions; variable has %d", expr, len(items), len(shape))
+	}
+
+	s := Selection{
+		Start:  make([]uint64, len(shape)),
+		Count:  make([]uint64, len(shape)),
+		Stride: make([]int64, len(shape)),
+	}
+	for i, n := range shape {
+		item := ":"
+		if i < len(items) {
+			item = items[i]
+		}
+		drop, err := s.parseItem(i, item, int64(n))
+		if err != nil {
+			return Selection{}, fmt.Errorf("dimension %d of selection %q: %v", i, expr, err)
+		}
+		if !drop {
+			s.Shape = append(s.Shape, s.Count[i])
+		}
+	}
+	return s, nil
+}
+
+// parseItem sets the start, count and stride of dimension i, of length n,
+// given by item. It reports whether item is a single index, which drops the
+// dimension from the shape of the selection.
+func (s *Selection) parseItem(i int, item string, n int64) (drop bool, err error) {
+	parts := strings.Split(item, ":")
+	if len(parts) == 1 {
+		idx, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid index %q", item)
+		}
+		if idx < 0 {
+			idx += n
+		}
+		if idx < 0 || idx >= n {
+			return false, fmt.Errorf("index %s out of range for length %d", item, n)
+		}
+		s.Start[i], s.Count[i], s.Stride[i] = uint64(idx), 1, 1
+		return true, nil
+	}
+	if len(parts) > 3 {
+		return false, fmt.Errorf("invalid slice %q", item)
+	}
+	bound := func(part string, def int64) (int64, error) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return def, nil
+		}
+		b, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid slice %q", item)
+		}
+		if b < 0 {
+			b += n
+		}
+		if b < 0 {
+			b = 0
+		}
+		if b > n {
+			b = n
+		}
+		return b, nil
+	}
+	step := int64(1)
+	if len(parts) == 3 && strings.TrimSpace(parts[2]) != "" {
+		if step, err = strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64); err != nil {
+			return false, fmt.Errorf("invalid slice %q", item)
+		}
+		if step < 1 {
+			return false, fmt.Errorf("step of slice %q is not positive", item)
+		}
+	}
+	start, err := bound(parts[0], 0)
+	if err != nil {
+		return false, err
+	}
+	stop, err := bound(parts[1], n)
+	if err != nil {
+		return false, err
+	}
+	s.Start[i], s.Stride[i] = uint64(start), step
+	if stop > start {
+		s.Count[i] = uint64((stop - start + step - 1) / step)
+	}
+	return false, nil
+}
+
+// ReadExpr reads the values of variable v selected by expr, as described
+// for Select, into data. Data must be a slice of the Go type corresponding
+// to the type of v (e.g. []float64 for DOUBLE), with enough space for all
+// the values. The selection is returned, so its Shape can be used to
+// index data.
+func (v Var) ReadExpr(expr string, data interface{}) (Selection, error) {
+	s, err := v.Select(expr)
+	if err != nil {
+		return Selection{}, err
+	}
+	if s.Len() == 0 {
+		return s, nil
+	}
+	return s, v.readStrided(data, s.Start, s.Count, s.Stride)
+}
+
+// ReadArrayExpr reads the values of variable v selected by expr, as
+// described for Select, into a new array whose shape is that of the
+// selection.
+func ReadArrayExpr[T Number](v Var, expr string) (*Array[T], error) {
+	s, err := v.Select(expr)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]T, s.Len())
+	if s.Len() > 0 {
+		if err := v.readStrided(data, s.Start, s.Count, s.Stride); err != nil {
+			return nil, err
+		}
+	}
+	return newArray(data, intShape(s.Shape)), nil
+}
+
+// readStrided reads a strided slice of variable v into data, which must be
+// a slice of the Go type corresponding to the type of v.
+func (v Var) readStrided(data interface{}, start, count []uint64, stride []int64) error {
+	switch d := data.(type) {
+	default:
+		return fmt.Errorf("unsupported data type %T", data)
+	case []uint64:
+		return v.ReadUint64StridedSlice(d, start, count, stride)
+	case []int64:
+		return v.ReadInt64StridedSlice(d, start, count, stride)
+	case []float64:
+		return v.ReadFloat64StridedSlice(d, start, count, stride)
+	case []uint32:
+		return v.ReadUint32StridedSlice(d, start, count, stride)
+	case []int32:
+		return v.ReadInt32StridedSlice(d, start, count, stride)
+	case []float32:
+		return v.ReadFloat32StridedSlice(d, start, count, stride)
+	case []uint16:
+		return v.ReadUint16StridedSlice(d, start, count, stride)
+	case []int16:
+		return v.ReadInt16StridedSlice(d, start, count, stride)
+	case []uint8:
+		// Data for CHAR variables is also a []uint8.
+		if t, err := v.Type(); err == nil && t == CHAR {
+			return v.ReadBytesStridedSlice(d, start, count, stride)
+		}
+		return v.ReadUint8StridedSlice(d, start, count, stride)
+	case []int8:
+		return v.ReadInt8StridedSlice(d, start, count, stride)
+	}
+}
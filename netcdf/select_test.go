@@ -0,0 +1,142 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+// createSelect returns a dataset with a variable of shape [20 5 8], whose
+// value at (i, j, k) is 100*i + 10*j + k.
+func createSelect(t *testing.T) (netcdf.Dataset, netcdf.Var) {
+	var data []int32
+	for i := int32(0); i < 20; i++ {
+		for j := int32(0); j < 5; j++ {
+			for k := int32(0); k < 8; k++ {
+				data = append(data, 100*i+10*j+k)
+			}
+		}
+	}
+	ds := netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "t", Len: 20}, {Name: "y", Len: 5}, {Name: "x", Len: 8}},
+		Vars: []netcdftest.Var{{Name: "v", Dims: []string{"t", "y", "x"}, Data: data}},
+	})
+	v, err := ds.Var("v")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	return ds, v
+}
+
+func TestSelect(t *testing.T) {
+	ds, v := createSelect(t)
+	defer ds.Close()
+	for _, tc := range []struct {
+		expr string
+		want netcdf.Selection
+	}{
+		{"0:10:2, :, 5", netcdf.Selection{
+			Start: []uint64{0, 0, 5}, Count: []uint64{5, 5, 1}, Stride: []int64{2, 1, 1}, Shape: []uint64{5, 5}}},
+		{"", netcdf.Selection{
+			Start: []uint64{0, 0, 0}, Count: []uint64{20, 5, 8}, Stride: []int64{1, 1, 1}, Shape: []uint64{20, 5, 8}}},
+		{"-1", netcdf.Selection{
+			Start: []uint64{19, 0, 0}, Count: []uint64{1, 5, 8}, Stride: []int64{1, 1, 1}, Shape: []uint64{5, 8}}},
+		{"..., -2", netcdf.Selection{
+			Start: []uint64{0, 0, 6}, Count: []uint64{20, 5, 1}, Stride: []int64{1, 1, 1}, Shape: []uint64{20, 5}}},
+		{"3, ..., 1:", netcdf.Selection{
+			Start: []uint64{3, 0, 1}, Count: []uint64{1, 5, 7}, Stride: []int64{1, 1, 1}, Shape: []uint64{5, 7}}},
+		{"1, 2, 3, ...", netcdf.Selection{
+			Start: []uint64{1, 2, 3}, Count: []uint64{1, 1, 1}, Stride: []int64{1, 1, 1}, Shape: nil}},
+		{"-5:100:3, :-3, ::3", netcdf.Selection{
+			Start: []uint64{15, 0, 0}, Count: []uint64{2, 2, 3}, Stride: []int64{3, 1, 3}, Shape: []uint64{2, 2, 3}}},
+		{"5:2", netcdf.Selection{
+			Start: []uint64{5, 0, 0}, Count: []uint64{0, 5, 8}, Stride: []int64{1, 1, 1}, Shape: []uint64{0, 5, 8}}},
+	} {
+		s, err := v.Select(tc.expr)
+		if err != nil {
+			t.Errorf("Select(%q) failed: %v\n", tc.expr, err)
+		} else if !reflect.DeepEqual(s, tc.want) {
+			t.Errorf("Select(%q) is %+v; expected %+v\n", tc.expr, s, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		expr, err string
+	}{
+		{"20", "dimension 0 of selection \"20\": index 20 out of range for length 20"},
+		{":, -6", "index -6 out of range for length 5"},
+		{"1, 2, 3, 4", "selection \"1, 2, 3, 4\" has 4 dimensions; variable has 3"},
+		{"..., ...", "more than one ellipsis"},
+		{"::-1", "step of slice \"::-1\" is not positive"},
+		{"::0", "step of slice \"::0\" is not positive"},
+		{"a", "invalid index \"a\""},
+		{"1:b", "invalid slice \"1:b\""},
+		{"1:2:3:4", "invalid slice \"1:2:3:4\""},
+	} {
+		_, err := v.Select(tc.expr)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Select(%q) returned %v; expected error containing %q\n", tc.expr, err, tc.err)
+		}
+	}
+}
+
+func TestReadExpr(t *testing.T) {
+	ds, v := createSelect(t)
+	defer ds.Close()
+
+	data := make([]int32, 25)
+	s, err := v.ReadExpr("0:10:2, :, 5", data)
+	if err != nil {
+		t.Fatalf("ReadExpr failed: %v\n", err)
+	}
+	if !reflect.DeepEqual(s.Shape, []uint64{5, 5}) {
+		t.Errorf("shape of selection is %v; expected [5 5]\n", s.Shape)
+	}
+	for i, val := range data {
+		if want := int32(200*(i/5) + 10*(i%5) + 5); val != want {
+			t.Errorf("value %d is %v; expected %v\n", i, val, want)
+		}
+	}
+	if _, err := v.ReadExpr(":", make([]float64, 800)); err == nil {
+		t.Errorf("ReadExpr into slice of the wrong type succeeded\n")
+	}
+	if _, err := v.ReadExpr("5:2", nil); err != nil {
+		t.Errorf("ReadExpr of empty selection failed: %v\n", err)
+	}
+
+	a, err := netcdf.ReadArrayExpr[int32](v, "-1, ::2, -3:")
+	if err != nil {
+		t.Fatalf("ReadArrayExpr failed: %v\n", err)
+	}
+	if !reflect.DeepEqual(a.Shape(), []int{3, 3}) || a.At(1, 2) != 1927 {
+		t.Errorf("ReadArrayExpr returned array of shape %v with (1, 2) = %v\n", a.Shape(), a.At(1, 2))
+	}
+}
+
+func TestStridedSliceErrors(t *testing.T) {
+	ds, v := createSelect(t)
+	defer ds.Close()
+	data := make([]int32, 800)
+	for _, tc := range []struct {
+		start, count []uint64
+		stride       []int64
+		err          string
+	}{
+		{[]uint64{0, 0}, []uint64{1, 1, 1}, []int64{1, 1, 1}, "incorrect number of dimensions in start: 2 != 3"},
+		{[]uint64{0, 5, 0}, []uint64{1, 1, 1}, []int64{1, 1, 1}, "start of dimension 1 of slice is out of range: 0 <= 5 < 5"},
+		{[]uint64{0, 0, 1}, []uint64{1, 1, 5}, []int64{1, 1, 2}, "end of dimension 2 of slice is out of range: 9 < 8"},
+		{[]uint64{0, 0, 0}, []uint64{1, 1, 1}, []int64{1, 0, 1}, "stride of dimension 1 of slice is not positive: 0"},
+	} {
+		err := v.ReadInt32StridedSlice(data, tc.start, tc.count, tc.stride)
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("ReadInt32StridedSlice(%v, %v, %v) returned %v; expected %q\n", tc.start, tc.count, tc.stride, err, tc.err)
+		}
+	}
+}
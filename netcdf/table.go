@@ -0,0 +1,580 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableOptions controls ExportTable and ImportTable. A nil *TableOptions
+// uses the defaults.
+type TableOptions struct {
+	// Comma is the field separator. It defaults to ',' for CSV. Use '\t'
+	// for TSV.
+	Comma rune
+
+	// TimeLayout is the layout, as in package time, of decoded times.
+	// It defaults to time.RFC3339Nano. ImportTable tries it before the
+	// other layouts it recognizes.
+	TimeLayout string
+
+	// RawTimes exports the values of time variables as numbers, instead
+	// of decoding them.
+	RawTimes bool
+
+	// Dim is the name of the dimension of the rows created by
+	// ImportTable. It defaults to "row".
+	Dim string
+
+	// Types gives the type of columns imported by ImportTable, by name,
+	// instead of inferring it. Only INT, INT64, DOUBLE and CHAR are
+	// supported.
+	Types map[string]Type
+
+	// Transfer controls how the data is read by ExportTable, in pieces of
+	// at most Transfer.PieceLen values of each variable.
+	Transfer *TransferOptions
+}
+
+func (o *TableOptions) comma() rune {
+	if o == nil || o.Comma == 0 {
+		return ','
+	}
+	return o.Comma
+}
+
+func (o *TableOptions) timeLayout() string {
+	if o == nil || o.TimeLayout == "" {
+		return time.RFC3339Nano
+	}
+	return o.TimeLayout
+}
+
+func (o *TableOptions) pieceLen() uint64 {
+	if o == nil {
+		return (*TransferOptions)(nil).pieceLen()
+	}
+	return o.Transfer.pieceLen()
+}
+
+// tableVar is a variable exported as columns of a table.
+type tableVar struct {
+	v     Var
+	name  string
+	t     Type
+	inner []uint64   // shape of the dimensions after the row dimensions
+	str   uint64     // length of strings, for CHAR
+	units *TimeUnits // encoding of times, if decoded
+	buf   interface{}
+}
+
+// width returns the number of values of v in a row.
+func (tv *tableVar) width() uint64 {
+	return product(tv.inner) * tv.str
+}
+
+// ExportTable writes the variables of ds named vars as a CSV table to w,
+// or as TSV if opts.Comma is '\t'. The first line holds the names of the
+// columns.
+//
+// The rows of the table span the leading dimensions shared by all the
+// variables, the row dimensions, in row-major order. For char variables,
+// the last dimension holds the characters of strings, and isn't counted.
+// Each row starts with a column for each row dimension, holding the value
+// of its coordinate variable, or the index along the dimension if it has
+// none. Then comes a column for each variable, named like it, or several
+// columns named like "temp[1,2]" if the variable has dimensions after the
+// row dimensions.
+//
+// The values of variables with CF time units, as read by Var.TimeUnits,
+// are decoded and formatted with opts.TimeLayout, unless opts.RawTimes is
+// set. Trailing NUL characters of strings are removed. NaN values are
+// written as empty cells.
+//
+// The data is read in pieces, so it's never loaded whole. Opts may be nil
+// for the defaults.
+func ExportTable(ds Dataset, w io.Writer, vars []string, opts *TableOptions) error {
+	if len(vars) == 0 {
+		return fmt.Errorf("no variables to export")
+	}
+	tvs := make([]*tableVar, len(vars))
+	var rowDims []string
+	for i, name := range vars {
+		v, err := ds.Var(name)
+		if err != nil {
+			return fmt.Errorf("variable %q: %v", name, err)
+		}
+		tv, dims, err := newTableVar(v, name, opts)
+		if err != nil {
+			return fmt.Errorf("variable %q: %v", name, err)
+		}
+		if i == 0 {
+			rowDims = dims
+		}
+		n := 0
+		for n < len(rowDims) && n < len(dims) && rowDims[n] == dims[n] {
+			n++
+		}
+		rowDims = rowDims[:n]
+		tvs[i] = tv
+	}
+
+	// The first columns are the coordinates of the row dimensions.
+	coords := make([]*tableVar, len(rowDims))
+	header := make([]string, 0, len(rowDims)+len(vars))
+	rowShape := make([]uint64, len(rowDims))
+	for k, dim := range rowDims {
+		header = append(header, dim)
+		d, err := ds.Dim(dim)
+		if err != nil {
+			return err
+		}
+		if rowShape[k], err = d.Len(); err != nil {
+			return err
+		}
+		if _, err := ds.Var(dim); err == ENOTVAR {
+			continue
+		} else if err != nil {
+			return err
+		}
+		cv, err := ds.CoordVar(dim)
+		if err != nil {
+			// A variable named like the dimension that isn't a
+			// coordinate variable.
+			continue
+		}
+		if coords[k], _, err = newTableVar(cv, dim, opts); err != nil {
+			return fmt.Errorf("variable %q: %v", dim, err)
+		}
+		if coords[k].t == CHAR {
+			coords[k] = nil
+			continue
+		}
+		if coords[k].buf, err = makeSlice(coords[k].t, rowShape[k]); err != nil {
+			return err
+		}
+		if err := transferSlice(cv, coords[k].t, coords[k].buf, false, 0, slab{[]uint64{0}, rowShape[k : k+1]}); err != nil {
+			return fmt.Errorf("variable %q: %v", dim, err)
+		}
+	}
+
+	// Then come the variables, except coordinate variables already
+	// written.
+	pieceLen := opts.pieceLen()
+	maxWidth := uint64(1)
+	all := tvs
+	tvs = tvs[:0]
+	for _, tv := range all {
+		if len(rowDims) == 1 && tv.name == rowDims[0] && coords[0] != nil {
+			continue
+		}
+		tvs = append(tvs, tv)
+		tv.inner = tv.inner[len(rowShape):]
+		if tv.width() > maxWidth {
+			maxWidth = tv.width()
+		}
+		header = append(header, columnNames(tv.name, tv.inner)...)
+	}
+	rowsPerPiece := pieceLen / maxWidth
+	if rowsPerPiece == 0 {
+		rowsPerPiece = 1
+	}
+	for _, tv := range tvs {
+		var err error
+		if tv.buf, err = makeSlice(tv.t, rowsPerPiece*tv.width()); err != nil {
+			return err
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = opts.comma()
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	layout := opts.timeLayout()
+	record := make([]string, 0, len(header))
+	s := slab{make([]uint64, len(rowShape)), rowShape}
+	err := s.pieces(rowsPerPiece, func(off uint64, p slab) error {
+		for _, tv := range tvs {
+			vs := slab{
+				start: append(append([]uint64(nil), p.start...), make([]uint64, len(tv.inner))...),
+				count: append(append([]uint64(nil), p.count...), tv.inner...),
+			}
+			if tv.t == CHAR && tv.str > 0 {
+				vs.start = append(vs.start, 0)
+				vs.count = append(vs.count, tv.str)
+			}
+			if product(vs.count) == 0 {
+				continue
+			}
+			if err := transferSlice(tv.v, tv.t, tv.buf, false, 0, vs); err != nil {
+				return fmt.Errorf("variable %q: %v", tv.name, err)
+			}
+		}
+		idx := append([]uint64(nil), p.start...)
+		rows := product(p.count)
+		for r := uint64(0); r < rows; r++ {
+			record = record[:0]
+			for k, cv := range coords {
+				if cv == nil {
+					record = append(record, strconv.FormatUint(idx[k], 10))
+				} else {
+					record = append(record, cv.format(idx[k], layout))
+				}
+			}
+			for _, tv := range tvs {
+				n := product(tv.inner)
+				for j := uint64(0); j < n; j++ {
+					record = append(record, tv.format(r*n+j, layout))
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+			// Move to the next row of the piece.
+			for k := len(idx) - 1; k >= 0; k-- {
+				idx[k]++
+				if idx[k] < p.start[k]+p.count[k] {
+					break
+				}
+				idx[k] = p.start[k]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// newTableVar returns variable v, named name, to export, and the names of
+// its dimensions, without the length of strings for CHAR.
+func newTableVar(v Var, name string, opts *TableOptions) (*tableVar, []string, error) {
+	tv := &tableVar{v: v, name: name, str: 1}
+	var err error
+	if tv.t, err = v.Type(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := makeSlice(tv.t, 0); err != nil {
+		return nil, nil, err
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return nil, nil, err
+	}
+	shape, err := v.LenDims()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tv.t == CHAR && len(dims) > 0 {
+		tv.str = shape[len(shape)-1]
+		dims, shape = dims[:len(dims)-1], shape[:len(shape)-1]
+	}
+	names := make([]string, len(dims))
+	for i, d := range dims {
+		if names[i], err = d.Name(); err != nil {
+			return nil, nil, err
+		}
+	}
+	tv.inner = shape
+	if tv.t != CHAR && (opts == nil || !opts.RawTimes) {
+		if u, err := v.TimeUnits(); err == nil {
+			tv.units = &u
+		}
+	}
+	return tv, names, nil
+}
+
+// columnNames returns the names of the columns holding the values of a
+// variable named name, whose dimensions after the row dimensions have the
+// given shape.
+func columnNames(name string, shape []uint64) []string {
+	if len(shape) == 0 {
+		return []string{name}
+	}
+	n := product(shape)
+	names := make([]string, n)
+	for i := uint64(0); i < n; i++ {
+		idx, _ := UnravelIndex(i, shape)
+		s := make([]string, len(idx))
+		for k, x := range idx {
+			s[k] = strconv.FormatUint(x, 10)
+		}
+		names[i] = fmt.Sprintf("%s[%s]", name, strings.Join(s, ","))
+	}
+	return names
+}
+
+// format returns the cell holding value i of the buffer of tv, or string
+// i for CHAR.
+func (tv *tableVar) format(i uint64, layout string) string {
+	if tv.t == CHAR {
+		b := tv.buf.([]byte)[i*tv.str : (i+1)*tv.str]
+		return string(bytes.TrimRight(b, "\x00"))
+	}
+	x := reflect.ValueOf(tv.buf).Index(int(i))
+	switch x.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := x.Float()
+		if math.IsNaN(f) {
+			return ""
+		}
+		if tv.units != nil {
+			return tv.units.Time(f).Format(layout)
+		}
+		return strconv.FormatFloat(f, 'g', -1, x.Type().Bits())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if tv.units != nil {
+			return tv.units.Time(float64(x.Int())).Format(layout)
+		}
+		return strconv.FormatInt(x.Int(), 10)
+	default:
+		if tv.units != nil {
+			return tv.units.Time(float64(x.Uint())).Format(layout)
+		}
+		return strconv.FormatUint(x.Uint(), 10)
+	}
+}
+
+// Layouts of times recognized by ImportTable, after TableOptions.TimeLayout.
+var tableTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ImportTable defines a variable for each column of the CSV table read
+// from r, or TSV if opts.Comma is '\t', and writes the values of the
+// columns. Ds must be a newly created dataset in define mode. On return,
+// ds is in data mode.
+//
+// The first line holds the names of the columns, which are the names of
+// the variables. The variables have a dimension named opts.Dim, whose
+// length is the number of rows. The type of each column is given by
+// opts.Types, or else inferred from its values, ignoring empty cells:
+//
+//   - INT if all cells are integers that fit in 32 bits, or INT64 if they
+//     fit in 64 bits, and there are no empty cells.
+//   - DOUBLE if all cells are numbers. Empty cells are NaN.
+//   - Times if all cells are times in opts.TimeLayout, RFC 3339, or a
+//     layout like "2006-01-02 15:04:05" with optional seconds or time,
+//     in UTC. Times are DOUBLE variables with CF time units of days since
+//     1970-01-01 if they're all at midnight, or else seconds. Empty cells
+//     are NaN.
+//   - CHAR otherwise, with an additional dimension named like the column
+//     followed by "_len", for the length of the longest string.
+//
+// The table is loaded whole to infer the types. Opts may be nil for the
+// defaults.
+func ImportTable(ds Dataset, r io.Reader, opts *TableOptions) error {
+	cr := csv.NewReader(r)
+	cr.Comma = opts.comma()
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("table has no header")
+	}
+	header, rows := records[0], records[1:]
+	dimName := "row"
+	if opts != nil && opts.Dim != "" {
+		dimName = opts.Dim
+	}
+	dim, err := ds.AddDim(dimName, uint64(len(rows)))
+	if err != nil {
+		return fmt.Errorf("dimension %q: %v", dimName, err)
+	}
+
+	type column struct {
+		v    Var
+		t    Type
+		data interface{}
+		str  uint64
+	}
+	columns := make([]column, len(header))
+	cells := make([]string, len(rows))
+	for j, name := range header {
+		for i, row := range rows {
+			cells[i] = row[j]
+		}
+		t, ok := Type(0), false
+		if opts != nil && opts.Types != nil {
+			t, ok = opts.Types[name]
+		}
+		var units *TimeUnits
+		if !ok {
+			t, units = inferColumn(cells, opts.timeLayout())
+		}
+		col := &columns[j]
+		col.t = t
+		if col.data, col.str, err = parseColumn(cells, t, units, opts.timeLayout()); err != nil {
+			return fmt.Errorf("column %q: %v", name, err)
+		}
+		dims := []Dim{dim}
+		if t == CHAR {
+			d, err := ds.AddDim(name+"_len", col.str)
+			if err != nil {
+				return fmt.Errorf("column %q: %v", name, err)
+			}
+			dims = append(dims, d)
+		}
+		if col.v, err = ds.AddVar(name, t, dims); err != nil {
+			return fmt.Errorf("column %q: %v", name, err)
+		}
+		if units != nil {
+			unit := "seconds"
+			if units.Unit == 24*time.Hour {
+				unit = "days"
+			}
+			err := col.v.Attr("units").WriteBytes([]byte(unit + " since 1970-01-01 00:00:00"))
+			if err == nil {
+				err = col.v.Attr("calendar").WriteBytes([]byte("standard"))
+			}
+			if err != nil {
+				return fmt.Errorf("column %q: %v", name, err)
+			}
+		}
+	}
+	if err := ds.EndDef(); err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	for j, col := range columns {
+		s := slab{[]uint64{0}, []uint64{uint64(len(rows))}}
+		if col.t == CHAR {
+			s = slab{[]uint64{0, 0}, []uint64{uint64(len(rows)), col.str}}
+		}
+		if err := transferSlice(col.v, col.t, col.data, true, 0, s); err != nil {
+			return fmt.Errorf("column %q: %v", header[j], err)
+		}
+	}
+	return nil
+}
+
+// inferColumn returns the type of a column holding cells, and its time
+// units if it holds times.
+func inferColumn(cells []string, layout string) (Type, *TimeUnits) {
+	t := INT
+	for _, c := range cells {
+		if c == "" {
+			t = DOUBLE
+			continue
+		}
+		if t == INT {
+			if _, err := strconv.ParseInt(c, 10, 32); err == nil {
+				continue
+			}
+			t = INT64
+		}
+		if t == INT64 {
+			if _, err := strconv.ParseInt(c, 10, 64); err == nil {
+				continue
+			}
+			t = DOUBLE
+		}
+		if _, err := strconv.ParseFloat(c, 64); err != nil {
+			t = 0
+			break
+		}
+	}
+	if t != 0 {
+		return t, nil
+	}
+
+	units := &TimeUnits{Unit: 24 * time.Hour, Epoch: time.Unix(0, 0).UTC()}
+	for _, c := range cells {
+		if c == "" {
+			continue
+		}
+		tm, ok := parseTableTime(c, layout)
+		if !ok {
+			return CHAR, nil
+		}
+		if tm.Truncate(24*time.Hour) != tm {
+			units.Unit = time.Second
+		}
+	}
+	return DOUBLE, units
+}
+
+func parseTableTime(s, layout string) (time.Time, bool) {
+	if t, err := time.Parse(layout, s); err == nil {
+		return t, true
+	}
+	for _, l := range tableTimeLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseColumn returns the values of cells as a slice of the Go type of t,
+// and the length of strings for CHAR. Units encodes times.
+func parseColumn(cells []string, t Type, units *TimeUnits, layout string) (interface{}, uint64, error) {
+	switch t {
+	case INT, INT64:
+		bits := 32
+		if t == INT64 {
+			bits = 64
+		}
+		data, _ := makeSlice(t, uint64(len(cells)))
+		for i, c := range cells {
+			x, err := strconv.ParseInt(c, 10, bits)
+			if err != nil {
+				return nil, 0, fmt.Errorf("row %d: invalid %v value %q", i+1, t, c)
+			}
+			reflect.ValueOf(data).Index(i).SetInt(x)
+		}
+		return data, 0, nil
+	case DOUBLE:
+		data := make([]float64, len(cells))
+		for i, c := range cells {
+			if c == "" {
+				data[i] = math.NaN()
+				continue
+			}
+			if units != nil {
+				tm, _ := parseTableTime(c, layout)
+				data[i] = units.Value(tm)
+				continue
+			}
+			x, err := strconv.ParseFloat(c, 64)
+			if err != nil {
+				return nil, 0, fmt.Errorf("row %d: invalid %v value %q", i+1, t, c)
+			}
+			data[i] = x
+		}
+		return data, 0, nil
+	case CHAR:
+		n := uint64(1)
+		for _, c := range cells {
+			if uint64(len(c)) > n {
+				n = uint64(len(c))
+			}
+		}
+		data := make([]byte, uint64(len(cells))*n)
+		for i, c := range cells {
+			copy(data[uint64(i)*n:], c)
+		}
+		return data, n, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported type %v", t)
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+var tableFixture = netcdftest.Fixture{
+	Dims: []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "station", Len: 2}, {Name: "level", Len: 2}, {Name: "name_len", Len: 4}},
+	Vars: []netcdftest.Var{
+		{Name: "time", Dims: []string{"time"}, Data: []float64{0, 0.5, 1},
+			Attrs: []netcdftest.Attr{{Name: "units", Value: "days since 2020-01-01"}}},
+		{Name: "temp", Dims: []string{"time", "station"}, Data: []float32{1.5, 2, float32(math.NaN()), 4, 5, 6}},
+		{Name: "flag", Dims: []string{"time", "station", "level"}, Data: []int16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}},
+		{Name: "name", Type: netcdf.CHAR, Dims: []string{"station", "name_len"}, Data: []byte("ab\x00\x00cdef")},
+	},
+}
+
+func TestExportTable(t *testing.T) {
+	ds := netcdftest.MustBuild(t, tableFixture)
+	defer ds.Close()
+
+	for _, tc := range []struct {
+		vars []string
+		opts *netcdf.TableOptions
+		want string
+	}{
+		{
+			vars: []string{"temp", "flag"},
+			// Pieces of 4 values hold 2 rows of flag.
+			opts: &netcdf.TableOptions{Transfer: &netcdf.TransferOptions{PieceLen: 4}},
+			want: "time,station,temp,flag[0],flag[1]\n" +
+				"2020-01-01T00:00:00Z,0,1.5,1,2\n" +
+				"2020-01-01T00:00:00Z,1,2,3,4\n" +
+				"2020-01-01T12:00:00Z,0,,5,6\n" +
+				"2020-01-01T12:00:00Z,1,4,7,8\n" +
+				"2020-01-02T00:00:00Z,0,5,9,10\n" +
+				"2020-01-02T00:00:00Z,1,6,11,12\n",
+		},
+		{
+			vars: []string{"time"},
+			opts: &netcdf.TableOptions{RawTimes: true},
+			want: "time\n0\n0.5\n1\n",
+		},
+		{
+			vars: []string{"time", "temp"},
+			opts: &netcdf.TableOptions{Comma: '\t', TimeLayout: "2006-01-02 15h"},
+			want: "time\ttemp[0]\ttemp[1]\n" +
+				"2020-01-01 00h\t1.5\t2\n" +
+				"2020-01-01 12h\t\t4\n" +
+				"2020-01-02 00h\t5\t6\n",
+		},
+		{
+			vars: []string{"name"},
+			want: "station,name\n0,ab\n1,cdef\n",
+		},
+	} {
+		var buf bytes.Buffer
+		if err := netcdf.ExportTable(ds, &buf, tc.vars, tc.opts); err != nil {
+			t.Fatalf("ExportTable(%v) failed: %v\n", tc.vars, err)
+		}
+		if got := buf.String(); got != tc.want {
+			t.Errorf("ExportTable(%v) wrote\n%s\nwant\n%s\n", tc.vars, got, tc.want)
+		}
+	}
+
+	if err := netcdf.ExportTable(ds, &bytes.Buffer{}, []string{"nope"}, nil); err == nil {
+		t.Errorf("ExportTable of a missing variable succeeded\n")
+	}
+}
+
+func TestImportTable(t *testing.T) {
+	in := "id,big,x,day,when,label,n\n" +
+		"1,5000000000,1.5,2020-01-01,2020-01-01T06:00:00Z,ab,3\n" +
+		"2,-1,,2020-01-03,2020-01-01 00:00,\"c,d\",\n"
+	ds := netcdftest.New()
+	defer ds.Close()
+	opts := &netcdf.TableOptions{Dim: "obs", Types: map[string]netcdf.Type{"id": netcdf.DOUBLE}}
+	if err := netcdf.ImportTable(ds, strings.NewReader(in), opts); err != nil {
+		t.Fatalf("ImportTable failed: %v\n", err)
+	}
+	nan := math.NaN()
+	netcdftest.AssertEqual(t, ds, netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "obs", Len: 2}, {Name: "label_len", Len: 3}},
+		Vars: []netcdftest.Var{
+			{Name: "id", Dims: []string{"obs"}, Data: []float64{1, 2}},
+			{Name: "big", Dims: []string{"obs"}, Data: []int64{5000000000, -1}},
+			{Name: "x", Dims: []string{"obs"}, Data: []float64{1.5, nan}},
+			{Name: "day", Dims: []string{"obs"}, Data: []float64{18262, 18264},
+				Attrs: []netcdftest.Attr{
+					{Name: "units", Value: "days since 1970-01-01 00:00:00"},
+					{Name: "calendar", Value: "standard"},
+				}},
+			{Name: "when", Dims: []string{"obs"}, Data: []float64{1577858400, 1577836800},
+				Attrs: []netcdftest.Attr{
+					{Name: "units", Value: "seconds since 1970-01-01 00:00:00"},
+					{Name: "calendar", Value: "standard"},
+				}},
+			{Name: "label", Type: netcdf.CHAR, Dims: []string{"obs", "label_len"}, Data: []byte("ab\x00c,d")},
+			{Name: "n", Dims: []string{"obs"}, Data: []float64{3, nan}},
+		},
+	}))
+
+	// Exported times are imported as times.
+	src := netcdftest.MustBuild(t, tableFixture)
+	defer src.Close()
+	var buf bytes.Buffer
+	if err := netcdf.ExportTable(src, &buf, []string{"time"}, nil); err != nil {
+		t.Fatalf("ExportTable failed: %v\n", err)
+	}
+	dst := netcdftest.New()
+	defer dst.Close()
+	if err := netcdf.ImportTable(dst, &buf, nil); err != nil {
+		t.Fatalf("ImportTable failed: %v\n", err)
+	}
+	v, err := dst.Var("time")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	u, err := v.TimeUnits()
+	if err != nil {
+		t.Fatalf("TimeUnits failed: %v\n", err)
+	}
+	data, err := netcdf.GetFloat64s(v)
+	if err != nil {
+		t.Fatalf("GetFloat64s failed: %v\n", err)
+	}
+	if len(data) != 3 || u.Time(data[1]).Format("2006-01-02 15:04") != "2020-01-01 12:00" {
+		t.Errorf("imported times %v with units %v\n", data, u)
+	}
+
+	for _, tc := range []struct {
+		in   string
+		opts *netcdf.TableOptions
+		err  string
+	}{
+		{"", nil, "table has no header"},
+		{"a,b\n1\n", nil, "record on line 2: wrong number of fields"},
+		{"a\nx\n", &netcdf.TableOptions{Types: map[string]netcdf.Type{"a": netcdf.INT}}, `column "a": row 1: invalid INT value "x"`},
+	} {
+		ds := netcdftest.New()
+		err := netcdf.ImportTable(ds, strings.NewReader(tc.in), tc.opts)
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("ImportTable(%q) returned error %v; want %s\n", tc.in, err, tc.err)
+		}
+		ds.Close()
+	}
+}
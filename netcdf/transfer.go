@@ -0,0 +1,599 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	// DefaultPieceLen is the default maximum number of values transferred
+	// by each call into the C library during piecewise I/O.
+	DefaultPieceLen = 1 << 20
+
+	// DefaultProgressInterval is the default minimum time between
+	// calls to the progress callback of a transfer.
+	DefaultProgressInterval = 100 * time.Millisecond
+)
+
+// TransferOptions controls piecewise I/O done by methods such as ReadCtx,
+// WriteCtx and CopyVarCtx. A nil *TransferOptions uses the defaults.
+type TransferOptions struct {
+	// PieceLen is the maximum number of values transferred by each call
+	// into the C library. Zero means DefaultPieceLen.
+	PieceLen uint64
+
+	// Progress, if not nil, is called after pieces are transferred, at
+	// most once per ProgressInterval, and once more when the transfer
+	// completes. It's called from the goroutine doing the transfer.
+	Progress func(Progress)
+
+	// ProgressInterval is the minimum time between calls to Progress.
+	// Zero means DefaultProgressInterval.
+	ProgressInterval time.Duration
+}
+
+func (o *TransferOptions) pieceLen() uint64 {
+	if o == nil || o.PieceLen == 0 {
+		return DefaultPieceLen
+	}
+	return o.PieceLen
+}
+
+// Progress describes how far a transfer has progressed.
+type Progress struct {
+	Var           string // name of the variable being transferred
+	Elements      uint64 // number of values transferred so far
+	TotalElements uint64 // total number of values to transfer
+	Bytes         uint64 // number of bytes transferred so far
+	TotalBytes    uint64 // total number of bytes to transfer
+}
+
+// progress keeps track of a transfer and throttles the calls to
+// the callback of TransferOptions. A nil *progress does nothing.
+type progress struct {
+	Progress
+	f        func(Progress)
+	interval time.Duration
+	last     time.Time
+	reported bool // whether the current state was reported
+}
+
+// newProgress returns a progress for a transfer of the given total size,
+// or nil if o doesn't ask for progress reports.
+func (o *TransferOptions) newProgress(totalElements, totalBytes uint64) *progress {
+	if o == nil || o.Progress == nil {
+		return nil
+	}
+	p := &progress{f: o.Progress, interval: o.ProgressInterval}
+	if p.interval == 0 {
+		p.interval = DefaultProgressInterval
+	}
+	p.TotalElements = totalElements
+	p.TotalBytes = totalBytes
+	return p
+}
+
+// setVar sets the name of the variable being transferred.
+func (p *progress) setVar(name string) {
+	if p != nil {
+		p.Var = name
+	}
+}
+
+// add records the transfer of some values, calling the callback if enough
+// time has passed since the last call.
+func (p *progress) add(elements, bytes uint64) {
+	if p == nil {
+		return
+	}
+	p.Elements += elements
+	p.Bytes += bytes
+	p.reported = false
+	if now := time.Now(); now.Sub(p.last) >= p.interval {
+		p.last = now
+		p.reported = true
+		p.f(p.Progress)
+	}
+}
+
+// finish calls the callback with the final state of a completed transfer,
+// unless it was already reported.
+func (p *progress) finish() {
+	if p != nil && !p.reported {
+		p.reported = true
+		p.f(p.Progress)
+	}
+}
+
+// ReadCtx reads the entire variable v into data, like ReadFloat64s and
+// friends. Data must be a slice of the Go type corresponding to the type
+// of v (e.g. []float64 for DOUBLE), with enough space for all the values.
+//
+// The variable is read in pieces of bounded size, and ctx is checked
+// between pieces. If ctx is done, the number of values read so far is
+// returned along with ctx.Err().
+func (v Var) ReadCtx(ctx context.Context, data interface{}, opts *TransferOptions) (n uint64, err error) {
+	shape, err := v.LenDims()
+	if err != nil {
+		return 0, err
+	}
+	return v.ReadSliceCtx(ctx, data, make([]uint64, len(shape)), shape, opts)
+}
+
+// WriteCtx writes data as the entire data for variable v. It's the
+// counterpart of ReadCtx.
+func (v Var) WriteCtx(ctx context.Context, data interface{}, opts *TransferOptions) (n uint64, err error) {
+	shape, err := v.LenDims()
+	if err != nil {
+		return 0, err
+	}
+	return v.WriteSliceCtx(ctx, data, make([]uint64, len(shape)), shape, opts)
+}
+
+// ReadSliceCtx reads a slice of variable v into data, like ReadFloat64Slice
+// and friends. The slice is specified by start and count. Data must be a slice
+// of the Go type corresponding to the type of v. Like ReadCtx, it
+// reads in pieces and stops early if ctx is done.
+func (v Var) ReadSliceCtx(ctx context.Context, data interface{}, start, count []uint64, opts *TransferOptions) (n uint64, err error) {
+	return v.transferCtx(ctx, data, false, start, count, opts)
+}
+
+// WriteSliceCtx writes data as a slice of variable v, like WriteFloat64Slice
+// and friends. It's the counterpart of ReadSliceCtx.
+func (v Var) WriteSliceCtx(ctx context.Context, data interface{}, start, count []uint64, opts *TransferOptions) (n uint64, err error) {
+	return v.transferCtx(ctx, data, true, start, count, opts)
+}
+
+func (v Var) transferCtx(ctx context.Context, data interface{}, write bool, start, count []uint64, opts *TransferOptions) (n uint64, err error) {
+	t, l, err := sliceType(data)
+	if err != nil {
+		return 0, err
+	}
+	if t == UBYTE {
+		// Data for CHAR variables is also a []uint8.
+		if u, err := v.Type(); err == nil && u == CHAR {
+			t = CHAR
+		}
+	}
+	if err := okDataSlice(v, t, l, start, count); err != nil {
+		return 0, err
+	}
+	total := product(count)
+	pr := opts.newProgress(total, total*t.Size())
+	if pr != nil {
+		name, err := v.Name()
+		if err != nil {
+			return 0, err
+		}
+		pr.setVar(name)
+	}
+	err = slab{start, count}.pieces(opts.pieceLen(), func(off uint64, p slab) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := transferSlice(v, t, data, write, off, p); err != nil {
+			return err
+		}
+		m := product(p.count)
+		n += m
+		pr.add(m, m*t.Size())
+		return nil
+	})
+	if err == nil {
+		pr.finish()
+	}
+	return n, err
+}
+
+// CopyVarCtx copies the data of variable src into variable dst, which must
+// have the same type and shape, except that unlimited dimensions of dst may
+// be shorter. The data is copied in pieces using a buffer of bounded size,
+// and ctx is checked between pieces. The number of values copied is returned,
+// along with ctx.Err() if ctx is done before the copy completes.
+func CopyVarCtx(ctx context.Context, dst, src Var, opts *TransferOptions) (n uint64, err error) {
+	t, shape, err := checkCopyVar(dst, src)
+	if err != nil {
+		return 0, err
+	}
+	total := product(shape)
+	pr := opts.newProgress(total, total*t.Size())
+	if pr != nil {
+		name, err := src.Name()
+		if err != nil {
+			return 0, err
+		}
+		pr.setVar(name)
+	}
+	n, err = copyVar(ctx, dst, src, t, shape, opts.pieceLen(), pr)
+	if err == nil {
+		pr.finish()
+	}
+	return n, err
+}
+
+// checkCopyVar checks that the data of src can be copied into dst,
+// and returns the type and shape of src.
+func checkCopyVar(dst, src Var) (t Type, shape []uint64, err error) {
+	t, err = src.Type()
+	if err != nil {
+		return 0, nil, err
+	}
+	if u, err := dst.Type(); err != nil {
+		return 0, nil, err
+	} else if u != t {
+		return 0, nil, fmt.Errorf("destination type %v differs from source type %v", u, t)
+	}
+	shape, err = src.LenDims()
+	if err != nil {
+		return 0, nil, err
+	}
+	dims, err := dst.Dims()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(dims) != len(shape) {
+		return 0, nil, fmt.Errorf("destination has %d dimensions; expected %d", len(dims), len(shape))
+	}
+	unlimited, err := dst.ds.UnlimitedDims()
+	if err != nil {
+		return 0, nil, err
+	}
+	for i, d := range dims {
+		n, err := d.Len()
+		if err != nil {
+			return 0, nil, err
+		}
+		if n != shape[i] && !containsDim(unlimited, d) {
+			return 0, nil, fmt.Errorf("length %d of destination dimension %d differs from source length %d", n, i, shape[i])
+		}
+	}
+	return t, shape, nil
+}
+
+func containsDim(dims []Dim, d Dim) bool {
+	for _, e := range dims {
+		if e == d {
+			return true
+		}
+	}
+	return false
+}
+
+// copyVar copies the data of src, of type t and the given shape, into dst
+// in pieces of at most pieceLen values, recording them in pr.
+func copyVar(ctx context.Context, dst, src Var, t Type, shape []uint64, pieceLen uint64, pr *progress) (n uint64, err error) {
+	if l := product(shape); l < pieceLen {
+		pieceLen = l
+	}
+	buf, err := makeSlice(t, pieceLen)
+	if err != nil {
+		return 0, err
+	}
+	s := slab{make([]uint64, len(shape)), shape}
+	err = s.pieces(pieceLen, func(_ uint64, p slab) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := transferSlice(src, t, buf, false, 0, p); err != nil {
+			return err
+		}
+		if err := putSlice(dst, t, buf, p); err != nil {
+			return err
+		}
+		m := product(p.count)
+		n += m
+		pr.add(m, m*t.Size())
+		return nil
+	})
+	return n, err
+}
+
+// CopyDatasetCtx copies the dimensions, variables, attributes and groups
+// of dataset src into dst, which must be a newly created dataset in define
+// mode, and then copies the data of every variable like CopyVarCtx.
+// On return, dst is in data mode. It's like Copy with the given transfer
+// options, except that ctx is checked between pieces.
+//
+// If opts asks for progress reports, the totals cover the data of all
+// variables, and Progress.Var is the name of the variable being copied.
+func CopyDatasetCtx(ctx context.Context, dst, src Dataset, opts *TransferOptions) error {
+	return copyDataset(ctx, dst, src, &CopyOptions{Transfer: opts})
+}
+
+// putSlice writes the first values of data, which holds values of type t,
+// as the hyperslab p of v. Unlike WriteFloat64Slice and friends, it lets
+// the hyperslab extend the unlimited dimensions of v, which copying record
+// variables requires.
+func putSlice(v Var, t Type, data interface{}, p slab) error {
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	n := product(p.count)
+	data = reflect.ValueOf(data).Slice(0, int(n)).Interface()
+	if len(p.count) == 0 {
+		return d.PutVar(v.id, int(t), data)
+	}
+	return d.PutVars(v.id, int(t), p.start, p.count, nil, data)
+}
+
+// copyAttrs copies all the attributes of variable src into variable dst.
+func copyAttrs(dst, src Var) error {
+	n, err := src.NAttrs()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		a, err := src.AttrN(i)
+		if err != nil {
+			return err
+		}
+		if err := copyAttr(dst, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pieces calls f for consecutive pieces of the hyperslab s, each holding
+// at most maxLen values (or a single value if maxLen is zero). The pieces
+// are contiguous in a buffer holding s, and off is the offset of the
+// piece within the buffer. It stops at the first error returned by f.
+func (s slab) pieces(maxLen uint64, f func(off uint64, p slab) error) error {
+	n := len(s.count)
+	if n == 0 {
+		return f(0, s)
+	}
+	if product(s.count) == 0 {
+		return nil
+	}
+	if maxLen == 0 {
+		maxLen = 1
+	}
+
+	// Find the outermost dimension d such that the dimensions after it
+	// fit in a piece. Pieces hold whole rows along those dimensions,
+	// a range of k values along d, and a single index before d.
+	d, inner := n-1, uint64(1)
+	for d > 0 && inner*s.count[d] <= maxLen {
+		inner *= s.count[d]
+		d--
+	}
+	k := maxLen / inner
+	if k > s.count[d] {
+		k = s.count[d]
+	}
+
+	p := slab{start: make([]uint64, n), count: make([]uint64, n)}
+	copy(p.start, s.start)
+	for i := range p.count {
+		p.count[i] = 1
+		if i > d {
+			p.count[i] = s.count[i]
+		}
+	}
+	var off uint64
+	for {
+		end := s.start[d] + s.count[d]
+		for i := s.start[d]; i < end; i += k {
+			p.start[d], p.count[d] = i, k
+			if i+k > end {
+				p.count[d] = end - i
+			}
+			if err := f(off, p); err != nil {
+				return err
+			}
+			off += p.count[d] * inner
+		}
+
+		// Advance the index of the dimensions before d in row-major order.
+		i := d - 1
+		for ; i >= 0; i-- {
+			p.start[i]++
+			if p.start[i] < s.start[i]+s.count[i] {
+				break
+			}
+			p.start[i] = s.start[i]
+		}
+		if i < 0 {
+			return nil
+		}
+	}
+}
+
+// transferSlice reads (or writes, if write is true) the hyperslab p of v
+// from data[off:], which holds values of type t. An empty hyperslab stands
+// for the entire variable.
+func transferSlice(v Var, t Type, data interface{}, write bool, off uint64, p slab) error {
+	n := product(p.count)
+	switch d := data.(type) {
+	default:
+		return fmt.Errorf("unsupported data type %T", data)
+	case []uint64:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteUint64s(d)
+		case len(p.start) == 0:
+			return v.ReadUint64s(d)
+		case write:
+			return v.WriteUint64Slice(d, p.start, p.count)
+		}
+		return v.ReadUint64Slice(d, p.start, p.count)
+	case []int64:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteInt64s(d)
+		case len(p.start) == 0:
+			return v.ReadInt64s(d)
+		case write:
+			return v.WriteInt64Slice(d, p.start, p.count)
+		}
+		return v.ReadInt64Slice(d, p.start, p.count)
+	case []float64:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteFloat64s(d)
+		case len(p.start) == 0:
+			return v.ReadFloat64s(d)
+		case write:
+			return v.WriteFloat64Slice(d, p.start, p.count)
+		}
+		return v.ReadFloat64Slice(d, p.start, p.count)
+	case []uint32:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteUint32s(d)
+		case len(p.start) == 0:
+			return v.ReadUint32s(d)
+		case write:
+			return v.WriteUint32Slice(d, p.start, p.count)
+		}
+		return v.ReadUint32Slice(d, p.start, p.count)
+	case []int32:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteInt32s(d)
+		case len(p.start) == 0:
+			return v.ReadInt32s(d)
+		case write:
+			return v.WriteInt32Slice(d, p.start, p.count)
+		}
+		return v.ReadInt32Slice(d, p.start, p.count)
+	case []float32:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteFloat32s(d)
+		case len(p.start) == 0:
+			return v.ReadFloat32s(d)
+		case write:
+			return v.WriteFloat32Slice(d, p.start, p.count)
+		}
+		return v.ReadFloat32Slice(d, p.start, p.count)
+	case []uint16:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteUint16s(d)
+		case len(p.start) == 0:
+			return v.ReadUint16s(d)
+		case write:
+			return v.WriteUint16Slice(d, p.start, p.count)
+		}
+		return v.ReadUint16Slice(d, p.start, p.count)
+	case []int16:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteInt16s(d)
+		case len(p.start) == 0:
+			return v.ReadInt16s(d)
+		case write:
+			return v.WriteInt16Slice(d, p.start, p.count)
+		}
+		return v.ReadInt16Slice(d, p.start, p.count)
+	case []uint8:
+		d = d[off : off+n]
+		if t == CHAR {
+			switch {
+			case len(p.start) == 0 && write:
+				return v.WriteBytes(d)
+			case len(p.start) == 0:
+				return v.ReadBytes(d)
+			case write:
+				return v.WriteBytesSlice(d, p.start, p.count)
+			}
+			return v.ReadBytesSlice(d, p.start, p.count)
+		}
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteUint8s(d)
+		case len(p.start) == 0:
+			return v.ReadUint8s(d)
+		case write:
+			return v.WriteUint8Slice(d, p.start, p.count)
+		}
+		return v.ReadUint8Slice(d, p.start, p.count)
+	case []int8:
+		d = d[off : off+n]
+		switch {
+		case len(p.start) == 0 && write:
+			return v.WriteInt8s(d)
+		case len(p.start) == 0:
+			return v.ReadInt8s(d)
+		case write:
+			return v.WriteInt8Slice(d, p.start, p.count)
+		}
+		return v.ReadInt8Slice(d, p.start, p.count)
+	}
+}
+
+// sliceType returns the netCDF type and the length of data, which must be
+// a slice of one of the Go types supported by this package.
+func sliceType(data interface{}) (t Type, n int, err error) {
+	switch d := data.(type) {
+	default:
+		return 0, 0, fmt.Errorf("unsupported data type %T", data)
+	case []uint64:
+		return UINT64, len(d), nil
+	case []int64:
+		return INT64, len(d), nil
+	case []float64:
+		return DOUBLE, len(d), nil
+	case []uint32:
+		return UINT, len(d), nil
+	case []int32:
+		return INT, len(d), nil
+	case []float32:
+		return FLOAT, len(d), nil
+	case []uint16:
+		return USHORT, len(d), nil
+	case []int16:
+		return SHORT, len(d), nil
+	case []uint8:
+		// []byte and []uint8 are the same type, which is
+		// used for both UBYTE and CHAR data.
+		return UBYTE, len(d), nil
+	case []int8:
+		return BYTE, len(d), nil
+	}
+}
+
+// makeSlice returns a slice of length n of the Go type corresponding to t.
+func makeSlice(t Type, n uint64) (interface{}, error) {
+	switch t {
+	default:
+		return nil, fmt.Errorf("unsupported type %v", t)
+	case UINT64:
+		return make([]uint64, n), nil
+	case INT64:
+		return make([]int64, n), nil
+	case DOUBLE:
+		return make([]float64, n), nil
+	case UINT:
+		return make([]uint32, n), nil
+	case INT:
+		return make([]int32, n), nil
+	case FLOAT:
+		return make([]float32, n), nil
+	case USHORT:
+		return make([]uint16, n), nil
+	case SHORT:
+		return make([]int16, n), nil
+	case UBYTE, CHAR:
+		return make([]uint8, n), nil
+	case BYTE:
+		return make([]int8, n), nil
+	}
+}
@@ -0,0 +1,427 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package netcdf
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlabPiecesContiguous(t *testing.T) {
+	tests := []struct {
+		s      slab
+		maxLen uint64
+		pieces int
+	}{
+		{slab{[]uint64{}, []uint64{}}, 10, 1},
+		{slab{[]uint64{0}, []uint64{10}}, 3, 4},
+		{slab{[]uint64{0}, []uint64{10}}, 0, 10},
+		{slab{[]uint64{2, 1}, []uint64{5, 4}}, 100, 1},
+		{slab{[]uint64{2, 1}, []uint64{5, 4}}, 9, 3},
+		{slab{[]uint64{2, 1}, []uint64{5, 4}}, 3, 10},
+		{slab{[]uint64{1, 2, 3}, []uint64{3, 4, 5}}, 20, 3},
+		{slab{[]uint64{1, 2, 3}, []uint64{3, 4, 5}}, 12, 6},
+		{slab{[]uint64{0, 0}, []uint64{0, 5}}, 3, 0},
+	}
+	for _, test := range tests {
+		var next uint64
+		pieces := 0
+		err := test.s.pieces(test.maxLen, func(off uint64, p slab) error {
+			pieces++
+			n := product(p.count)
+			if test.maxLen > 0 && n > test.maxLen {
+				t.Errorf("piece %v of %v has %d values; expected at most %d\n", p, test.s, n, test.maxLen)
+			}
+			if off != next {
+				t.Errorf("piece %v of %v is at offset %d; expected %d\n", p, test.s, off, next)
+			}
+			// The first value of the piece must be at offset off within s.
+			var o uint64
+			for i := range p.start {
+				o = o*test.s.count[i] + p.start[i] - test.s.start[i]
+			}
+			if o != off {
+				t.Errorf("piece %v of %v starts at offset %d; expected %d\n", p, test.s, o, off)
+			}
+			next += n
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("pieces failed: %v\n", err)
+		}
+		if pieces != test.pieces {
+			t.Errorf("%v split in %d pieces of at most %d values; expected %d\n",
+				test.s, pieces, test.maxLen, test.pieces)
+		}
+		if l := product(test.s.count); next != l {
+			t.Errorf("pieces of %v hold %d values; expected %d\n", test.s, next, l)
+		}
+	}
+}
+
+func TestReadWriteCtx(t *testing.T) {
+	skipWithoutC(t)
+	ctx := context.Background()
+	opts := &TransferOptions{PieceLen: 4}
+	for _, ft := range getFileTests() {
+		f, err := ioutil.TempFile("", "netcdf_test")
+		if err != nil {
+			t.Fatalf("creating temporary file failed: %v\n", err)
+		}
+		createFile(t, f.Name(), &ft)
+
+		ds, err := OpenFile(f.Name(), WRITE)
+		if err != nil {
+			t.Fatalf("Open failed: %v\n", err)
+		}
+		v, err := ds.Var(ft.VarName)
+		if err != nil {
+			t.Fatalf("Var failed: %v\n", err)
+		}
+		l, err := v.Len()
+		if err != nil {
+			t.Fatalf("Var.Len failed: %v\n", err)
+		}
+		shape, err := v.LenDims()
+		if err != nil {
+			t.Fatalf("Var.LenDims failed: %v\n", err)
+		}
+		want, _ := makeSlice(ft.DataType, l)
+		if err := transferSlice(v, ft.DataType, want, false, 0, slab{make([]uint64, len(shape)), shape}); err != nil {
+			t.Fatalf("%v: reading data failed: %v\n", ft.DataType, err)
+		}
+
+		data, _ := makeSlice(ft.DataType, l)
+		n, err := v.ReadCtx(ctx, data, opts)
+		if err != nil {
+			t.Fatalf("%v: ReadCtx failed: %v\n", ft.DataType, err)
+		}
+		if n != l {
+			t.Errorf("%v: ReadCtx read %d values; expected %d\n", ft.DataType, n, l)
+		}
+		if !reflect.DeepEqual(data, want) {
+			t.Errorf("%v: ReadCtx read %v; expected %v\n", ft.DataType, data, want)
+		}
+
+		// Write the data back in reverse order, then read it.
+		rv := reflect.ValueOf(data)
+		for i, j := 0, rv.Len()-1; i < j; i, j = i+1, j-1 {
+			x := rv.Index(i).Interface()
+			rv.Index(i).Set(rv.Index(j))
+			rv.Index(j).Set(reflect.ValueOf(x))
+		}
+		if n, err := v.WriteCtx(ctx, data, opts); err != nil || n != l {
+			t.Fatalf("%v: WriteCtx returned (%d, %v); expected (%d, nil)\n", ft.DataType, n, err, l)
+		}
+		got, _ := makeSlice(ft.DataType, l)
+		if _, err := v.ReadCtx(ctx, got, nil); err != nil {
+			t.Fatalf("%v: ReadCtx failed: %v\n", ft.DataType, err)
+		}
+		if !reflect.DeepEqual(got, data) {
+			t.Errorf("%v: ReadCtx after WriteCtx read %v; expected %v\n", ft.DataType, got, data)
+		}
+
+		if err := ds.Close(); err != nil {
+			t.Fatalf("Close failed: %v\n", err)
+		}
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}
+}
+
+// cancelAfter is a context that's canceled after its Err method is called
+// a given number of times.
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n == 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestReadCtxCanceled(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	if err := createConcurrentFile(f.Name(), CLOBBER, 10, 7); err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	ds, err := OpenFile(f.Name(), NOWRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer ds.Close()
+	v, err := ds.Var("gopher")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+
+	data := make([]float64, 70)
+	ctx := &cancelAfter{Context: context.Background(), n: 3}
+	n, err := v.ReadCtx(ctx, data, &TransferOptions{PieceLen: 15})
+	if err != context.Canceled {
+		t.Errorf("ReadCtx returned error %v; expected %v\n", err, context.Canceled)
+	}
+	if n != 3*14 {
+		t.Errorf("ReadCtx read %d values; expected %d\n", n, 3*14)
+	}
+	for i, val := range data[:n] {
+		if want := float64(i + 10); val != want {
+			t.Fatalf("data at position %d is %v; expected %v\n", i, val, want)
+		}
+	}
+
+	cctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if n, err := v.ReadCtx(cctx, data, nil); n != 0 || err != context.Canceled {
+		t.Errorf("ReadCtx returned (%d, %v); expected (0, %v)\n", n, err, context.Canceled)
+	}
+	if _, err := v.ReadCtx(context.Background(), make([]int32, 70), nil); err == nil {
+		t.Errorf("ReadCtx with wrong data type succeeded\n")
+	}
+	if _, err := v.ReadCtx(context.Background(), make([]float64, 69), nil); err == nil {
+		t.Errorf("ReadCtx with short data succeeded\n")
+	}
+	if _, err := v.ReadCtx(context.Background(), []string{}, nil); err == nil {
+		t.Errorf("ReadCtx with unsupported data type succeeded\n")
+	}
+}
+
+func TestCopyVarCtx(t *testing.T) {
+	skipWithoutC(t)
+	dir, err := ioutil.TempDir("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+	src, dst := filepath.Join(dir, "src.nc"), filepath.Join(dir, "dst.nc")
+	if err := createConcurrentFile(src, CLOBBER|NETCDF4, 9, 8); err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	if err := createConcurrentFile(dst, CLOBBER, 9, 8); err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+
+	sds, err := OpenFile(src, NOWRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer sds.Close()
+	sv, err := sds.Var("gopher")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	dds, err := OpenFile(dst, WRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer dds.Close()
+	dv, err := dds.Var("gopher")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+	if err := dv.WriteFloat64s(make([]float64, 72)); err != nil {
+		t.Fatalf("WriteFloat64s failed: %v\n", err)
+	}
+
+	n, err := CopyVarCtx(context.Background(), dv, sv, &TransferOptions{PieceLen: 20})
+	if err != nil {
+		t.Fatalf("CopyVarCtx failed: %v\n", err)
+	}
+	if n != 72 {
+		t.Errorf("CopyVarCtx copied %d values; expected %d\n", n, 72)
+	}
+	if err := checkConcurrentVar(dv); err != nil {
+		t.Errorf("checking copied data failed: %v\n", err)
+	}
+
+	ctx := &cancelAfter{Context: context.Background(), n: 1}
+	n, err = CopyVarCtx(ctx, dv, sv, &TransferOptions{PieceLen: 20})
+	if n != 16 || err != context.Canceled {
+		t.Errorf("CopyVarCtx returned (%d, %v); expected (%d, %v)\n", n, err, 16, context.Canceled)
+	}
+	if _, err := CopyVarCtx(context.Background(), dv, dv, nil); err != nil {
+		t.Errorf("CopyVarCtx to itself failed: %v\n", err)
+	}
+}
+
+func TestReadCtxProgress(t *testing.T) {
+	skipWithoutC(t)
+	f, err := ioutil.TempFile("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary file failed: %v\n", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			t.Errorf("removing temporary file failed: %v\n", err)
+		}
+	}()
+	if err := createConcurrentFile(f.Name(), CLOBBER, 10, 7); err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	ds, err := OpenFile(f.Name(), NOWRITE)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer ds.Close()
+	v, err := ds.Var("gopher")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+
+	tests := []struct {
+		interval time.Duration
+		calls    []uint64 // Elements in each call
+	}{
+		{time.Nanosecond, []uint64{14, 28, 42, 56, 70}},
+		{time.Hour, []uint64{14, 70}},
+	}
+	for _, test := range tests {
+		var calls []uint64
+		opts := &TransferOptions{
+			PieceLen:         15,
+			ProgressInterval: test.interval,
+			Progress: func(p Progress) {
+				calls = append(calls, p.Elements)
+				want := Progress{
+					Var:           "gopher",
+					Elements:      p.Elements,
+					TotalElements: 70,
+					Bytes:         8 * p.Elements,
+					TotalBytes:    8 * 70,
+				}
+				if p != want {
+					t.Errorf("progress is %+v; expected %+v\n", p, want)
+				}
+			},
+		}
+		if _, err := v.ReadCtx(context.Background(), make([]float64, 70), opts); err != nil {
+			t.Fatalf("ReadCtx failed: %v\n", err)
+		}
+		if !reflect.DeepEqual(calls, test.calls) {
+			t.Errorf("progress with interval %v reported %v; expected %v\n", test.interval, calls, test.calls)
+		}
+	}
+}
+
+func TestCopyDatasetCtx(t *testing.T) {
+	skipWithoutC(t)
+	dir, err := ioutil.TempDir("", "netcdf_test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	src, err := CreateFile(filepath.Join(dir, "src.nc"), CLOBBER|NETCDF4)
+	if err != nil {
+		t.Fatalf("creating file failed: %v\n", err)
+	}
+	defer src.Close()
+	dims := make([]Dim, 2)
+	if dims[0], err = src.AddDim("time", 0); err != nil {
+		t.Fatalf("adding dimension failed: %v\n", err)
+	}
+	if dims[1], err = src.AddDim("station", 4); err != nil {
+		t.Fatalf("adding dimension failed: %v\n", err)
+	}
+	temp, err := src.AddVar("temp", FLOAT, dims)
+	if err != nil {
+		t.Fatalf("adding variable failed: %v\n", err)
+	}
+	if err := temp.Attr("units").WriteBytes([]byte("K")); err != nil {
+		t.Fatalf("writing attribute failed: %v\n", err)
+	}
+	id, err := src.AddVar("id", INT, dims[1:])
+	if err != nil {
+		t.Fatalf("adding variable failed: %v\n", err)
+	}
+	if err := src.Attr("title").WriteBytes([]byte("gophers")); err != nil {
+		t.Fatalf("writing attribute failed: %v\n", err)
+	}
+	if err := src.EndDef(); err != nil {
+		t.Fatalf("EndDef failed: %v\n", err)
+	}
+	temps := make([]float32, 6*4)
+	for i := range temps {
+		temps[i] = 270 + float32(i)/2
+	}
+	if err := temp.WriteFloat32Slice(temps, []uint64{0, 0}, []uint64{6, 4}); err != nil {
+		t.Fatalf("writing data failed: %v\n", err)
+	}
+	if err := id.WriteInt32s([]int32{7, 11, 13, 17}); err != nil {
+		t.Fatalf("writing data failed: %v\n", err)
+	}
+
+	for _, mode := range []FileMode{CLOBBER, CLOBBER | NETCDF4} {
+		dst, err := CreateFile(filepath.Join(dir, "dst.nc"), mode)
+		if err != nil {
+			t.Fatalf("creating file failed: %v\n", err)
+		}
+		var last Progress
+		opts := &TransferOptions{
+			PieceLen: 5,
+			Progress: func(p Progress) { last = p },
+		}
+		if err := CopyDatasetCtx(context.Background(), dst, src, opts); err != nil {
+			t.Fatalf("CopyDatasetCtx failed: %v\n", err)
+		}
+		want := Progress{Var: "id", Elements: 28, TotalElements: 28, Bytes: 112, TotalBytes: 112}
+		if last != want {
+			t.Errorf("final progress is %+v; expected %+v\n", last, want)
+		}
+
+		unlimited, err := dst.UnlimitedDims()
+		if err != nil {
+			t.Fatalf("UnlimitedDims failed: %v\n", err)
+		}
+		if len(unlimited) != 1 {
+			t.Fatalf("dataset has %d unlimited dimensions; expected 1\n", len(unlimited))
+		}
+		if name, err := unlimited[0].Name(); err != nil || name != "time" {
+			t.Errorf("unlimited dimension is (%q, %v); expected (%q, nil)\n", name, err, "time")
+		}
+		if title, err := GetBytes(dst.Attr("title")); err != nil || string(title) != "gophers" {
+			t.Errorf("title is (%q, %v); expected (%q, nil)\n", title, err, "gophers")
+		}
+		v, err := dst.Var("temp")
+		if err != nil {
+			t.Fatalf("Var failed: %v\n", err)
+		}
+		if units, err := GetBytes(v.Attr("units")); err != nil || string(units) != "K" {
+			t.Errorf("units is (%q, %v); expected (%q, nil)\n", units, err, "K")
+		}
+		if got, err := GetFloat32s(v); err != nil || !reflect.DeepEqual(got, temps) {
+			t.Errorf("temp is (%v, %v); expected (%v, nil)\n", got, err, temps)
+		}
+		v, err = dst.Var("id")
+		if err != nil {
+			t.Fatalf("Var failed: %v\n", err)
+		}
+		if got, err := GetInt32s(v); err != nil || !reflect.DeepEqual(got, []int32{7, 11, 13, 17}) {
+			t.Errorf("id is (%v, %v); expected (%v, nil)\n", got, err, []int32{7, 11, 13, 17})
+		}
+		if err := dst.Close(); err != nil {
+			t.Fatalf("Close failed: %v\n", err)
+		}
+	}
+}
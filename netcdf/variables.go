@@ -4,37 +4,24 @@
 
 package netcdf
 
-// #include <stdlib.h>
-// #include <netcdf.h>
-import "C"
-
-import (
-	"unsafe"
-)
-
 // Var represents a variable.
 type Var struct {
 	ds Dataset
-	id C.int
+	id int
 }
 
 // Dims returns the dimensions of variable v.
 func (v Var) Dims() (dims []Dim, err error) {
-	var ndims C.int
-	err = newError(C.nc_inq_varndims(C.int(v.ds), C.int(v.id), &ndims))
+	d, err := v.ds.driver()
 	if err != nil {
 		return
 	}
-	if ndims == 0 {
-		return
-	}
-	dimids := make([]C.int, ndims)
-	err = newError(C.nc_inq_vardimid(C.int(v.ds), C.int(v.id), &dimids[0]))
-	if err != nil {
+	ids, err := d.VarDims(v.id)
+	if err != nil || len(ids) == 0 {
 		return
 	}
-	dims = make([]Dim, ndims)
-	for i, id := range dimids {
+	dims = make([]Dim, len(ids))
+	for i, id := range ids {
 		dims[i] = Dim{v.ds, id}
 	}
 	return
@@ -42,8 +29,11 @@ func (v Var) Dims() (dims []Dim, err error) {
 
 // Type returns the data type of variable v.
 func (v Var) Type() (t Type, err error) {
-	var typ C.nc_type
-	err = newError(C.nc_inq_vartype(C.int(v.ds), C.int(v.id), &typ))
+	d, err := v.ds.driver()
+	if err != nil {
+		return
+	}
+	typ, err := d.VarType(v.id)
 	t = Type(typ)
 	return
 }
@@ -83,76 +73,90 @@ func (v Var) LenDims() ([]uint64, error) {
 
 // NAttrs returns the number of attributes assigned to variable v.
 func (v Var) NAttrs() (n int, err error) {
-	var cn C.int
-	err = newError(C.nc_inq_varnatts(C.int(v.ds), C.int(v.id), &cn))
-	n = int(cn)
-	return
+	d, err := v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.NAttrs(v.id)
 }
 
 // Name returns the name of the variable.
 func (v Var) Name() (name string, err error) {
-	buf := C.CString(string(make([]byte, C.NC_MAX_NAME+1)))
-	defer C.free(unsafe.Pointer(buf))
-	err = newError(C.nc_inq_varname(C.int(v.ds), v.id, buf))
-	name = C.GoString(buf)
-	return
+	d, err := v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.VarName(v.id)
 }
 
 // SetCompression sets the deflate parameters for a variable in a NetCDF-4 file.
 func (v Var) SetCompression(shuffle, deflate bool, deflateLevel int) error {
-	sInt := C.int(0)
-	if shuffle {
-		sInt = 1
-	}
-	dInt := C.int(0)
-	if deflate {
-		dInt = 1
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
 	}
-	return newError(C.nc_def_var_deflate(C.int(v.ds), v.id, sInt, dInt, C.int(deflateLevel)))
+	return d.SetDeflate(v.id, shuffle, deflate, deflateLevel)
 }
 
 // Compression returns the deflate settings for a variable in a NetCDF-4 file.
 func (v Var) Compression() (shuffle, deflate bool, deflateLevel int, err error) {
-	var (
-		cShuffle      C.int
-		cDeflate      C.int
-		cDeflateLevel C.int
-	)
-	err = newError(C.nc_inq_var_deflate(C.int(v.ds), v.id, &cShuffle, &cDeflate, &cDeflateLevel))
-	return cShuffle != 0, cDeflate != 0, int(cDeflateLevel), err
+	d, err := v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.Deflate(v.id)
+}
+
+// SetChunking sets the storage layout for a variable in a NetCDF-4 file.
+// If contiguous is false, the variable is chunked and chunkSizes gives the
+// chunk length along each dimension of the variable.
+func (v Var) SetChunking(contiguous bool, chunkSizes []uint64) error {
+	d, err := v.ds.driver()
+	if err != nil {
+		return err
+	}
+	return d.SetChunking(v.id, contiguous, chunkSizes)
+}
+
+// Chunking returns the storage layout of variable v. Contiguous is true if
+// the variable is not chunked, which is always the case for netCDF-3 files.
+// Otherwise, chunkSizes gives the chunk length along each dimension.
+func (v Var) Chunking() (contiguous bool, chunkSizes []uint64, err error) {
+	d, err := v.ds.driver()
+	if err != nil {
+		return
+	}
+	return d.Chunking(v.id)
 }
 
 // AddVar adds a new a variable named name of type t and dimensions dims.
 // The new variable v is returned.
 func (ds Dataset) AddVar(name string, t Type, dims []Dim) (v Var, err error) {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	var varid C.int
-	var dimPtr *C.int
-	if len(dims) > 0 {
-		dimids := make([]C.int, len(dims))
-		for i, d := range dims {
-			dimids[i] = d.id
-		}
-		dimPtr = &dimids[0]
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	dimids := make([]int, len(dims))
+	for i, dim := range dims {
+		dimids[i] = dim.id
 	}
-	err = newError(C.nc_def_var(C.int(ds), cname, C.nc_type(t),
-		C.int(len(dims)), dimPtr, &varid))
-	v = Var{ds, varid}
+	id, err := d.DefVar(name, int(t), dimids)
+	v = Var{ds, id}
 	return
 }
 
 // VarN returns a new variable in File f with ID id.
 func (ds Dataset) VarN(id int) Var {
-	return Var{ds, C.int(id)}
+	return Var{ds, id}
 }
 
 // Var returns the Var for the variable named name.
 func (ds Dataset) Var(name string) (v Var, err error) {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	var id C.int
-	err = newError(C.nc_inq_varid(C.int(ds), cname, &id))
+	d, err := ds.driver()
+	if err != nil {
+		return
+	}
+	id, err := d.VarID(name)
 	v = Var{ds, id}
 	return
 }
@@ -0,0 +1,821 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package zarr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/internal/driver"
+)
+
+// group is a group of an open store. It implements driver.Dataset.
+type group struct {
+	*store
+	parent *group // nil for the root group
+	name   string
+	dimids []int // dimensions defined in this group
+	vars   []*array
+	attrs  []attr // global attributes
+	groups []*group
+}
+
+// store is the state shared by all the groups of an open store. Only
+// closing changes it, so reads don't need to hold the lock.
+type store struct {
+	mu     sync.Mutex
+	closed bool
+	dims   []netcdfDim // dimensions of all the groups, indexed by ID
+}
+
+// array is a variable of an open store.
+type array struct {
+	name       string
+	dir        string
+	t          netcdf.Type
+	order      binary.ByteOrder
+	chunks     []uint64
+	compressor string
+	level      int
+	sep        string
+	fill       reflect.Value
+	dims       []int
+	attrs      []attr
+}
+
+var _ driver.Dataset = (*group)(nil)
+
+// Open opens the Zarr store in directory dir as a read-only dataset. All
+// the metadata is read by Open; chunks are read when values are. Copy
+// can load the store into a writable dataset.
+func Open(dir string) (netcdf.Dataset, error) {
+	g, err := openGroup(&store{}, nil, "", dir)
+	if err != nil {
+		return netcdf.Dataset{}, fmt.Errorf("zarr: %v", err)
+	}
+	return driver.NewDataset(g, dir).(netcdf.Dataset), nil
+}
+
+func openGroup(s *store, parent *group, name, dir string) (*group, error) {
+	var zg struct {
+		ZarrFormat int `json:"zarr_format"`
+	}
+	if err := readJSON(filepath.Join(dir, zgroupFile), &zg); err != nil {
+		return nil, err
+	}
+	if zg.ZarrFormat != 2 {
+		return nil, fmt.Errorf("%s: unsupported Zarr format %d", dir, zg.ZarrFormat)
+	}
+	g := &group{store: s, parent: parent, name: name}
+	attrs, _, meta, err := readZattrs(dir)
+	if err != nil {
+		return nil, err
+	}
+	g.attrs = attrs
+
+	var varNames, groupNames []string
+	if meta != nil {
+		for _, d := range meta.Dimensions {
+			s.dims = append(s.dims, d)
+			g.dimids = append(g.dimids, len(s.dims)-1)
+		}
+		varNames, groupNames = meta.Variables, meta.Groups
+	} else {
+		// Without metadata, the store is searched for arrays and groups.
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			if exists(filepath.Join(dir, e.Name(), zarrayFile)) {
+				varNames = append(varNames, e.Name())
+			} else if exists(filepath.Join(dir, e.Name(), zgroupFile)) {
+				groupNames = append(groupNames, e.Name())
+			}
+		}
+	}
+
+	for _, name := range varNames {
+		v, err := g.openArray(name, filepath.Join(dir, name), meta == nil)
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: %v", name, err)
+		}
+		g.vars = append(g.vars, v)
+	}
+	for _, name := range groupNames {
+		child, err := openGroup(s, g, name, filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("group %q: %v", name, err)
+		}
+		g.groups = append(g.groups, child)
+	}
+	return g, nil
+}
+
+// maxReadChunkLen is the largest number of values in the chunks of the
+// arrays opened by Open, since chunks are read whole into memory.
+const maxReadChunkLen = 1 << 28
+
+// openArray opens the array in directory dir. If define is true, its
+// dimensions are defined in g unless g or its parents already have them.
+func (g *group) openArray(name, dir string, define bool) (*array, error) {
+	var za zarray
+	if err := readJSON(filepath.Join(dir, zarrayFile), &za); err != nil {
+		return nil, err
+	}
+	if za.ZarrFormat != 2 {
+		return nil, fmt.Errorf("unsupported Zarr format %d", za.ZarrFormat)
+	}
+	if za.Order != "C" {
+		return nil, fmt.Errorf("unsupported order %q", za.Order)
+	}
+	if f := string(za.Filters); f != "" && f != "null" && f != "[]" {
+		return nil, fmt.Errorf("unsupported filters %s", za.Filters)
+	}
+	if len(za.Chunks) != len(za.Shape) {
+		return nil, fmt.Errorf("chunks %v don't match shape %v", za.Chunks, za.Shape)
+	}
+	chunkLen := uint64(1)
+	for _, n := range za.Chunks {
+		if n == 0 {
+			return nil, fmt.Errorf("invalid chunks %v", za.Chunks)
+		}
+		if n > maxReadChunkLen/chunkLen {
+			return nil, fmt.Errorf("chunks %v have more than %d values", za.Chunks, uint64(maxReadChunkLen))
+		}
+		chunkLen *= n
+	}
+	v := &array{
+		name:   name,
+		dir:    dir,
+		chunks: za.Chunks,
+		sep:    za.DimensionSeparator,
+	}
+	if v.sep == "" {
+		v.sep = "."
+	}
+	if za.Compressor != nil {
+		switch za.Compressor.ID {
+		case "zlib", "gzip":
+			v.compressor, v.level = za.Compressor.ID, za.Compressor.Level
+		default:
+			return nil, fmt.Errorf("unsupported compressor %q", za.Compressor.ID)
+		}
+	}
+	var err error
+	if v.t, v.order, err = parseDType(za.DType); err != nil {
+		return nil, err
+	}
+	if v.fill, err = decodeFill(v.t, za.FillValue); err != nil {
+		return nil, err
+	}
+
+	attrs, dimNames, _, err := readZattrs(dir)
+	if err != nil {
+		return nil, err
+	}
+	v.attrs = attrs
+	if dimNames == nil {
+		for _, n := range za.Shape {
+			dimNames = append(dimNames, "_Anonymous_Dim_"+strconv.FormatUint(n, 10))
+		}
+	}
+	if len(dimNames) != len(za.Shape) {
+		return nil, fmt.Errorf("dimensions %q don't match shape %v", dimNames, za.Shape)
+	}
+	for i, dname := range dimNames {
+		id := g.dimID(dname)
+		if id < 0 {
+			if !define {
+				return nil, fmt.Errorf("dimension %q not found", dname)
+			}
+			g.dims = append(g.dims, netcdfDim{Name: dname, Length: za.Shape[i]})
+			id = len(g.dims) - 1
+			g.dimids = append(g.dimids, id)
+		}
+		if n := g.dims[id].Length; n != za.Shape[i] {
+			return nil, fmt.Errorf("length %d along dimension %q of length %d", za.Shape[i], dname, n)
+		}
+		v.dims = append(v.dims, id)
+	}
+	return v, nil
+}
+
+// readZattrs decodes the .zattrs file in directory dir, if there's one.
+func readZattrs(dir string) ([]attr, []string, *netcdfMeta, error) {
+	data, err := os.ReadFile(filepath.Join(dir, zattrsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	attrs, dims, meta, err := decodeAttrs(data)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %v", filepath.Join(dir, zattrsFile), err)
+	}
+	return attrs, dims, meta, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// check returns an error if g is closed.
+func (g *group) check() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return netcdf.EBADID
+	}
+	return nil
+}
+
+func (g *group) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return netcdf.EBADID
+	}
+	g.closed = true
+	return nil
+}
+
+func (g *group) EndDef() error {
+	if err := g.check(); err != nil {
+		return err
+	}
+	return netcdf.ENOTINDEFINE
+}
+
+func (g *group) EndDefWithOptions(hMinFree, vAlign, vMinFree, rAlign uint64) error {
+	return g.EndDef()
+}
+
+func (g *group) Redef() error {
+	if err := g.check(); err != nil {
+		return err
+	}
+	return netcdf.EPERM
+}
+
+func (g *group) NDims() (int, error) {
+	if err := g.check(); err != nil {
+		return 0, err
+	}
+	return len(g.dimids), nil
+}
+
+func (g *group) NVars() (int, error) {
+	if err := g.check(); err != nil {
+		return 0, err
+	}
+	return len(g.vars), nil
+}
+
+func (g *group) NAttrs(varid int) (int, error) {
+	if err := g.check(); err != nil {
+		return 0, err
+	}
+	attrs, err := g.attrList(varid)
+	if err != nil {
+		return 0, err
+	}
+	return len(attrs), nil
+}
+
+func (g *group) UnlimitedDims() ([]int, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, id := range g.dimids {
+		if g.dims[id].Unlimited {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (g *group) DefDim(name string, n uint64) (int, error) {
+	if err := g.check(); err != nil {
+		return 0, err
+	}
+	return 0, netcdf.EPERM
+}
+
+// dimID returns the ID of the dimension named name, defined in this group
+// or its ancestors, or -1 if there's none.
+func (g *group) dimID(name string) int {
+	for p := g; p != nil; p = p.parent {
+		for _, id := range p.dimids {
+			if g.dims[id].Name == name {
+				return id
+			}
+		}
+	}
+	return -1
+}
+
+func (g *group) DimID(name string) (int, error) {
+	if err := g.check(); err != nil {
+		return 0, err
+	}
+	if id := g.dimID(name); id >= 0 {
+		return id, nil
+	}
+	return 0, netcdf.EBADDIM
+}
+
+// dim returns the dimension with ID id.
+func (g *group) dim(id int) (*netcdfDim, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	if id < 0 || id >= len(g.dims) {
+		return nil, netcdf.EBADDIM
+	}
+	return &g.dims[id], nil
+}
+
+func (g *group) DimName(dimid int) (string, error) {
+	d, err := g.dim(dimid)
+	if err != nil {
+		return "", err
+	}
+	return d.Name, nil
+}
+
+func (g *group) DimLen(dimid int) (uint64, error) {
+	d, err := g.dim(dimid)
+	if err != nil {
+		return 0, err
+	}
+	return d.Length, nil
+}
+
+func (g *group) DefVar(name string, t int, dimids []int) (int, error) {
+	if err := g.check(); err != nil {
+		return 0, err
+	}
+	return 0, netcdf.EPERM
+}
+
+func (g *group) VarID(name string) (int, error) {
+	if err := g.check(); err != nil {
+		return 0, err
+	}
+	for i, v := range g.vars {
+		if v.name == name {
+			return i, nil
+		}
+	}
+	return 0, netcdf.ENOTVAR
+}
+
+// variable returns the variable with ID id.
+func (g *group) variable(id int) (*array, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	if id < 0 || id >= len(g.vars) {
+		return nil, netcdf.ENOTVAR
+	}
+	return g.vars[id], nil
+}
+
+func (g *group) VarName(varid int) (string, error) {
+	v, err := g.variable(varid)
+	if err != nil {
+		return "", err
+	}
+	return v.name, nil
+}
+
+func (g *group) VarType(varid int) (int, error) {
+	v, err := g.variable(varid)
+	if err != nil {
+		return 0, err
+	}
+	return int(v.t), nil
+}
+
+func (g *group) VarDims(varid int) ([]int, error) {
+	v, err := g.variable(varid)
+	if err != nil {
+		return nil, err
+	}
+	return append([]int(nil), v.dims...), nil
+}
+
+func (g *group) SetDeflate(varid int, shuffle, deflate bool, level int) error {
+	if _, err := g.variable(varid); err != nil {
+		return err
+	}
+	return netcdf.EPERM
+}
+
+// Deflate reports chunks compressed with zlib or gzip as deflated.
+func (g *group) Deflate(varid int) (shuffle, deflate bool, level int, err error) {
+	v, err := g.variable(varid)
+	if err != nil {
+		return false, false, 0, err
+	}
+	if v.compressor == "" {
+		return false, false, 0, nil
+	}
+	if v.level < 1 || v.level > 9 {
+		return false, true, 1, nil
+	}
+	return false, true, v.level, nil
+}
+
+func (g *group) SetChunking(varid int, contiguous bool, sizes []uint64) error {
+	if _, err := g.variable(varid); err != nil {
+		return err
+	}
+	return netcdf.EPERM
+}
+
+func (g *group) Chunking(varid int) (contiguous bool, sizes []uint64, err error) {
+	v, err := g.variable(varid)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(v.chunks) == 0 {
+		return true, nil, nil
+	}
+	return false, append([]uint64(nil), v.chunks...), nil
+}
+
+// attrList returns the attributes of variable varid, which may be
+// driver.Global.
+func (g *group) attrList(varid int) ([]attr, error) {
+	if varid == driver.Global {
+		return g.attrs, nil
+	}
+	v, err := g.variable(varid)
+	if err != nil {
+		return nil, err
+	}
+	return v.attrs, nil
+}
+
+// attr returns the attribute named name of variable varid.
+func (g *group) attr(varid int, name string) (*attr, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	attrs, err := g.attrList(varid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range attrs {
+		if attrs[i].name == name {
+			return &attrs[i], nil
+		}
+	}
+	return nil, netcdf.ENOTATT
+}
+
+func (g *group) AttrName(varid, n int) (string, error) {
+	if err := g.check(); err != nil {
+		return "", err
+	}
+	attrs, err := g.attrList(varid)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 || n >= len(attrs) {
+		return "", netcdf.ENOTATT
+	}
+	return attrs[n].name, nil
+}
+
+func (g *group) AttrType(varid int, name string) (int, error) {
+	a, err := g.attr(varid, name)
+	if err != nil {
+		return 0, err
+	}
+	return int(a.t), nil
+}
+
+func (g *group) AttrLen(varid int, name string) (uint64, error) {
+	a, err := g.attr(varid, name)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(a.val.Len()), nil
+}
+
+func (g *group) GetAttr(varid int, name string, t int, val interface{}) error {
+	a, err := g.attr(varid, name)
+	if err != nil {
+		return err
+	}
+	if err := checkConvert(netcdf.Type(t), a.t); err != nil {
+		return err
+	}
+	dst := reflect.ValueOf(val)
+	for i := 0; i < a.val.Len(); i++ {
+		convert(dst.Index(i), a.val.Index(i))
+	}
+	return nil
+}
+
+func (g *group) PutAttr(varid int, name string, t int, val interface{}) error {
+	if _, err := g.attrList(varid); err != nil {
+		return err
+	}
+	return netcdf.EPERM
+}
+
+func (g *group) GetVar(varid, t int, data interface{}) error {
+	return g.read(varid, t, nil, nil, nil, data)
+}
+
+func (g *group) PutVar(varid, t int, data interface{}) error {
+	if _, err := g.variable(varid); err != nil {
+		return err
+	}
+	return netcdf.EPERM
+}
+
+func (g *group) GetVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	if count == nil {
+		count = make([]uint64, len(start))
+		for i := range count {
+			count[i] = 1
+		}
+	}
+	return g.read(varid, t, start, count, stride, data)
+}
+
+func (g *group) PutVars(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	if _, err := g.variable(varid); err != nil {
+		return err
+	}
+	return netcdf.EPERM
+}
+
+func (g *group) DefGrp(name string) (driver.Dataset, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	return nil, netcdf.EPERM
+}
+
+func (g *group) Grp(name string) (driver.Dataset, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	for _, c := range g.groups {
+		if c.name == name {
+			return c, nil
+		}
+	}
+	return nil, netcdf.ENOGRP
+}
+
+func (g *group) GrpNames() ([]string, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, c := range g.groups {
+		names = append(names, c.name)
+	}
+	return names, nil
+}
+
+func (g *group) DimIDs() ([]int, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	return append([]int(nil), g.dimids...), nil
+}
+
+// read reads the values of variable varid selected by start, count and
+// stride into data, which holds values of type t. A nil count selects all
+// the values. Only the chunks holding selected values are read.
+func (g *group) read(varid, t int, start, count []uint64, stride []int64, data interface{}) error {
+	v, err := g.variable(varid)
+	if err != nil {
+		return err
+	}
+	if err := checkConvert(netcdf.Type(t), v.t); err != nil {
+		return err
+	}
+	shape := make([]uint64, len(v.dims))
+	for i, id := range v.dims {
+		shape[i] = g.dims[id].Length
+	}
+	if count == nil {
+		start, count = make([]uint64, len(shape)), shape
+	}
+	if stride == nil {
+		stride = make([]int64, len(shape))
+		for i := range stride {
+			stride[i] = 1
+		}
+	}
+	if len(start) != len(shape) || len(count) != len(shape) || len(stride) != len(shape) {
+		return netcdf.EINVALCOORDS
+	}
+	for i, n := range shape {
+		if stride[i] < 1 {
+			return netcdf.ESTRIDE
+		}
+		if start[i] > n || start[i] == n && count[i] > 0 {
+			return netcdf.EINVALCOORDS
+		}
+		if count[i] > 0 && start[i]+(count[i]-1)*uint64(stride[i]) >= n {
+			return netcdf.EEDGE
+		}
+	}
+	if product(count) == 0 {
+		return nil
+	}
+
+	// The chunks of the grid between first and last hold the selected
+	// values.
+	first := make([]uint64, len(shape))
+	last := make([]uint64, len(shape))
+	for i := range shape {
+		first[i] = start[i] / v.chunks[i]
+		last[i] = (start[i] + (count[i]-1)*uint64(stride[i])) / v.chunks[i]
+	}
+	buf := reflect.ValueOf(data)
+	idx := append([]uint64(nil), first...)
+	lo := make([]uint64, len(shape)) // selected values in the chunk
+	hi := make([]uint64, len(shape))
+	for {
+		empty := false
+		for i := range shape {
+			c0 := idx[i] * v.chunks[i]
+			c1 := c0 + v.chunks[i]
+			s := uint64(stride[i])
+			lo[i] = 0
+			if c0 > start[i] {
+				lo[i] = (c0 - start[i] + s - 1) / s
+			}
+			hi[i] = (c1 - start[i] + s - 1) / s
+			if hi[i] > count[i] {
+				hi[i] = count[i]
+			}
+			if lo[i] >= hi[i] {
+				empty = true
+			}
+		}
+		if !empty {
+			chunk, err := v.readChunk(idx)
+			if err != nil {
+				return err
+			}
+			v.copyChunk(buf, chunk, idx, start, count, stride, lo, hi)
+		}
+
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			if idx[k] <= last[k] {
+				break
+			}
+			idx[k] = first[k]
+		}
+		if k < 0 {
+			return nil
+		}
+	}
+}
+
+// copyChunk copies to buf the values of chunk, at index idx of the chunk
+// grid, selected by start, count and stride. The selected values are those
+// from lo to hi along each dimension of the selection.
+func (v *array) copyChunk(buf, chunk reflect.Value, idx, start, count []uint64, stride []int64, lo, hi []uint64) {
+	k := append([]uint64(nil), lo...)
+	for {
+		dst, src := uint64(0), uint64(0)
+		for i := range k {
+			dst = dst*count[i] + k[i]
+			src = src*v.chunks[i] + start[i] + k[i]*uint64(stride[i]) - idx[i]*v.chunks[i]
+		}
+		convert(buf.Index(int(dst)), chunk.Index(int(src)))
+
+		i := len(k) - 1
+		for ; i >= 0; i-- {
+			k[i]++
+			if k[i] < hi[i] {
+				break
+			}
+			k[i] = lo[i]
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
+
+// readChunk returns the values of the chunk at index idx of the chunk
+// grid. Missing chunks hold the fill value.
+func (v *array) readChunk(idx []uint64) (reflect.Value, error) {
+	n := int(product(v.chunks))
+	chunk := reflect.MakeSlice(reflect.SliceOf(goTypes[v.t]), n, n)
+	key := chunkKey(idx, v.sep)
+	data, err := os.ReadFile(filepath.Join(v.dir, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		for i := 0; i < n; i++ {
+			chunk.Index(i).Set(v.fill)
+		}
+		return chunk, nil
+	}
+	if err != nil {
+		return chunk, fmt.Errorf("zarr: variable %q: %v", v.name, err)
+	}
+	if err := v.decodeChunk(data, chunk); err != nil {
+		return chunk, fmt.Errorf("zarr: variable %q: chunk %s: %v", v.name, key, err)
+	}
+	return chunk, nil
+}
+
+// decodeChunk decompresses data into chunk.
+func (v *array) decodeChunk(data []byte, chunk reflect.Value) error {
+	var r io.Reader = bytes.NewReader(data)
+	switch v.compressor {
+	case "zlib":
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		r = zr
+	case "gzip":
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	}
+	// A small compressed chunk can expand to any size, so only one byte
+	// more than expected is read.
+	want := chunk.Len() * int(chunk.Type().Elem().Size())
+	raw, err := io.ReadAll(io.LimitReader(r, int64(want)+1))
+	if err != nil {
+		return err
+	}
+	if len(raw) > want {
+		return fmt.Errorf("chunk has more than %d bytes", want)
+	}
+	if len(raw) != want {
+		return fmt.Errorf("chunk has %d bytes; want %d", len(raw), want)
+	}
+	return binary.Read(bytes.NewReader(raw), v.order, chunk.Interface())
+}
+
+// checkConvert returns an error if values of type from can't be converted
+// to values of type to, or the reverse.
+func checkConvert(from, to netcdf.Type) error {
+	if _, ok := goTypes[from]; !ok {
+		return netcdf.EBADTYPE
+	}
+	if (from == netcdf.CHAR) != (to == netcdf.CHAR) {
+		return netcdf.ECHAR
+	}
+	return nil
+}
+
+// convert sets dst to src, converted to the type of dst.
+func convert(dst, src reflect.Value) {
+	dst.Set(src.Convert(dst.Type()))
+}
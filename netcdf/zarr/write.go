@@ -0,0 +1,443 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package zarr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+// maxChunkLen is the maximum number of values of the chunks chosen by
+// Write.
+const maxChunkLen = 1 << 20
+
+// Options controls Write. A nil *Options writes uncompressed chunks.
+type Options struct {
+	// Compressor is "zlib" or "gzip" to compress the chunks, or empty to
+	// leave them uncompressed.
+	Compressor string
+
+	// Level is the compression level, from 1 to 9. It defaults to 1.
+	Level int
+
+	// Chunks gives the length of chunks along dimensions, by name. A
+	// length of 0 means the whole dimension. Along other dimensions,
+	// chunks have the length of the chunks of the variable if it's
+	// chunked. Otherwise, chunks span the whole dimension, except that
+	// the outer dimensions are split so chunks hold at most about a
+	// million values.
+	Chunks map[string]uint64
+}
+
+// Write writes dataset ds as a Zarr store in directory dir, which is
+// created if needed. Files of an existing store are overwritten. The data is
+// read and written one chunk at a time, so it's never loaded whole. Opts
+// may be nil to write uncompressed chunks.
+func Write(dir string, ds netcdf.Dataset, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	switch opts.Compressor {
+	case "", "zlib", "gzip":
+	default:
+		return fmt.Errorf("zarr: unsupported compressor %q", opts.Compressor)
+	}
+	if opts.Level < 0 || opts.Level > 9 {
+		return fmt.Errorf("zarr: invalid compression level %d", opts.Level)
+	}
+	if err := writeGroup(dir, ds, opts); err != nil {
+		return fmt.Errorf("zarr: %v", err)
+	}
+	return nil
+}
+
+func writeGroup(dir string, ds netcdf.Dataset, opts *Options) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(dir, zgroupFile), map[string]int{"zarr_format": 2}); err != nil {
+		return err
+	}
+
+	var meta netcdfMeta
+	dims, err := ds.Dims()
+	if err != nil {
+		return err
+	}
+	unlimited, err := ds.UnlimitedDims()
+	if err != nil {
+		return err
+	}
+	for _, d := range dims {
+		var nd netcdfDim
+		if nd.Name, err = d.Name(); err != nil {
+			return err
+		}
+		if nd.Length, err = d.Len(); err != nil {
+			return err
+		}
+		for _, u := range unlimited {
+			if u.ID() == d.ID() {
+				nd.Unlimited = true
+			}
+		}
+		meta.Dimensions = append(meta.Dimensions, nd)
+	}
+	n, err := ds.NVars()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		v := ds.VarN(i)
+		name, err := v.Name()
+		if err != nil {
+			return err
+		}
+		if err := writeVar(filepath.Join(dir, name), v, opts); err != nil {
+			return fmt.Errorf("variable %q: %v", name, err)
+		}
+		meta.Variables = append(meta.Variables, name)
+	}
+	if meta.Groups, err = ds.GroupNames(); err != nil {
+		return err
+	}
+
+	attrs, err := readAttrs(ds)
+	if err != nil {
+		return err
+	}
+	b, err := encodeAttrs(attrs, nil, nil, meta)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, zattrsFile), b, 0666); err != nil {
+		return err
+	}
+
+	for _, name := range meta.Groups {
+		g, err := ds.Group(name)
+		if err != nil {
+			return err
+		}
+		if err := writeGroup(filepath.Join(dir, name), g, opts); err != nil {
+			return fmt.Errorf("group %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// attrsReader is implemented by netcdf.Var, and by netcdf.Dataset for the
+// global attributes.
+type attrsReader interface {
+	NAttrs() (int, error)
+	AttrN(n int) (netcdf.Attr, error)
+}
+
+// readAttrs returns the attributes of v.
+func readAttrs(v attrsReader) ([]attr, error) {
+	n, err := v.NAttrs()
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]attr, n)
+	for i := range attrs {
+		a, err := v.AttrN(i)
+		if err != nil {
+			return nil, err
+		}
+		attrs[i].name = a.Name()
+		if attrs[i].t, err = a.Type(); err != nil {
+			return nil, err
+		}
+		gt, ok := goTypes[attrs[i].t]
+		if !ok {
+			return nil, fmt.Errorf("attribute %q has unsupported type %v", a.Name(), attrs[i].t)
+		}
+		l, err := a.Len()
+		if err != nil {
+			return nil, err
+		}
+		attrs[i].val = reflect.MakeSlice(reflect.SliceOf(gt), int(l), int(l))
+		if err := readAttr(a, attrs[i].val.Interface()); err != nil {
+			return nil, fmt.Errorf("attribute %q: %v", a.Name(), err)
+		}
+	}
+	return attrs, nil
+}
+
+// readAttr reads the values of a into val, a slice of the Go type of the
+// type of a.
+func readAttr(a netcdf.Attr, val interface{}) error {
+	switch val := val.(type) {
+	case []int8:
+		return a.ReadInt8s(val)
+	case []byte:
+		if t, _ := a.Type(); t == netcdf.CHAR {
+			return a.ReadBytes(val)
+		}
+		return a.ReadUint8s(val)
+	case []int16:
+		return a.ReadInt16s(val)
+	case []int32:
+		return a.ReadInt32s(val)
+	case []float32:
+		return a.ReadFloat32s(val)
+	case []float64:
+		return a.ReadFloat64s(val)
+	case []uint16:
+		return a.ReadUint16s(val)
+	case []uint32:
+		return a.ReadUint32s(val)
+	case []int64:
+		return a.ReadInt64s(val)
+	case []uint64:
+		return a.ReadUint64s(val)
+	}
+	return fmt.Errorf("unsupported value type %T", val)
+}
+
+func writeVar(dir string, v netcdf.Var, opts *Options) error {
+	t, err := v.Type()
+	if err != nil {
+		return err
+	}
+	dtype, ok := dtypes[t]
+	if !ok {
+		return fmt.Errorf("unsupported type %v", t)
+	}
+	shape, err := v.LenDims()
+	if err != nil {
+		return err
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return err
+	}
+	dimNames := make([]string, len(dims))
+	for i, d := range dims {
+		if dimNames[i], err = d.Name(); err != nil {
+			return err
+		}
+	}
+	attrs, err := readAttrs(v)
+	if err != nil {
+		return err
+	}
+	chunks, err := chooseChunks(v, shape, dimNames, opts)
+	if err != nil {
+		return err
+	}
+
+	fill := reflect.ValueOf(fillValues[t])
+	for _, a := range attrs {
+		if a.name == "_FillValue" && a.t == t && a.val.Len() == 1 {
+			fill = a.val.Index(0)
+		}
+	}
+	za := zarray{
+		ZarrFormat:         2,
+		Shape:              shape,
+		Chunks:             chunks,
+		DType:              dtype,
+		FillValue:          encodeFill(t, fill),
+		Order:              "C",
+		Filters:            json.RawMessage("null"),
+		DimensionSeparator: ".",
+	}
+	if za.Shape == nil {
+		za.Shape, za.Chunks = []uint64{}, []uint64{}
+	}
+	if opts.Compressor != "" {
+		za.Compressor = &compressor{ID: opts.Compressor, Level: opts.Level}
+		if za.Compressor.Level == 0 {
+			za.Compressor.Level = 1
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(dir, zarrayFile), za); err != nil {
+		return err
+	}
+	b, err := encodeAttrs(attrs, []string{dimsAttr}, []interface{}{dimNames}, netcdfMeta{})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, zattrsFile), b, 0666); err != nil {
+		return err
+	}
+	return writeChunks(dir, v, t, shape, chunks, za.Compressor)
+}
+
+// chooseChunks returns the shape of the chunks of v, as described by
+// Options.Chunks.
+func chooseChunks(v netcdf.Var, shape []uint64, dimNames []string, opts *Options) ([]uint64, error) {
+	chunks := make([]uint64, len(shape))
+	contiguous, sizes, err := v.Chunking()
+	if err != nil && err != netcdf.ENOTNC4 {
+		return nil, err
+	}
+	if err == nil && !contiguous {
+		copy(chunks, sizes)
+	} else {
+		copy(chunks, shape)
+		for i := range chunks {
+			if chunks[i] == 0 {
+				chunks[i] = 1
+			}
+		}
+		for i := range chunks {
+			rest := product(chunks[i+1:])
+			if chunks[i]*rest <= maxChunkLen {
+				break
+			}
+			chunks[i] = maxChunkLen / rest
+			if chunks[i] == 0 {
+				chunks[i] = 1
+			}
+		}
+	}
+	for i, name := range dimNames {
+		if n, ok := opts.Chunks[name]; ok {
+			chunks[i] = n
+			if n == 0 || n > shape[i] {
+				chunks[i] = shape[i]
+			}
+			if chunks[i] == 0 {
+				chunks[i] = 1
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// writeChunks writes the chunks of v, whose values have type t, in
+// directory dir.
+func writeChunks(dir string, v netcdf.Var, t netcdf.Type, shape, chunks []uint64, comp *compressor) error {
+	gt := goTypes[t]
+	chunkLen := int(product(chunks))
+	full := reflect.MakeSlice(reflect.SliceOf(gt), chunkLen, chunkLen)
+	part := reflect.MakeSlice(reflect.SliceOf(gt), chunkLen, chunkLen)
+	grid := make([]uint64, len(shape))
+	for i := range shape {
+		grid[i] = (shape[i] + chunks[i] - 1) / chunks[i]
+	}
+	if product(grid) == 0 {
+		return nil
+	}
+	idx := make([]uint64, len(shape))
+	var buf bytes.Buffer
+	for {
+		start := make([]uint64, len(shape))
+		count := make([]uint64, len(shape))
+		for i := range shape {
+			start[i] = idx[i] * chunks[i]
+			count[i] = chunks[i]
+			if start[i]+count[i] > shape[i] {
+				count[i] = shape[i] - start[i]
+			}
+		}
+		n := int(product(count))
+		if _, err := v.ReadSliceCtx(context.Background(), part.Slice(0, n).Interface(), start, count, nil); err != nil {
+			return err
+		}
+		data := part
+		if n != chunkLen {
+			// Edge chunks are padded to the shape of chunks.
+			copyBox(full, part, chunks, count)
+			data = full
+		}
+
+		buf.Reset()
+		var w io.Writer = &buf
+		var c io.Closer
+		if comp != nil {
+			var err error
+			switch comp.ID {
+			case "zlib":
+				var zw *zlib.Writer
+				zw, err = zlib.NewWriterLevel(&buf, comp.Level)
+				w, c = zw, zw
+			case "gzip":
+				var gw *gzip.Writer
+				gw, err = gzip.NewWriterLevel(&buf, comp.Level)
+				w, c = gw, gw
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := binary.Write(w, binary.LittleEndian, data.Interface()); err != nil {
+			return err
+		}
+		if c != nil {
+			if err := c.Close(); err != nil {
+				return err
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, chunkKey(idx, ".")), buf.Bytes(), 0666); err != nil {
+			return err
+		}
+
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < grid[k] {
+				break
+			}
+			idx[k] = 0
+		}
+		if k < 0 {
+			return nil
+		}
+	}
+}
+
+// copyBox copies src, holding values of shape count in row-major order,
+// to the start of dst, holding values of shape shape.
+func copyBox(dst, src reflect.Value, shape, count []uint64) {
+	if len(shape) == 0 {
+		reflect.Copy(dst, src)
+		return
+	}
+	last := len(shape) - 1
+	rows := product(count[:last])
+	idx := make([]uint64, last)
+	for r := uint64(0); r < rows; r++ {
+		off := uint64(0)
+		for k := 0; k < last; k++ {
+			off = off*shape[k] + idx[k]
+		}
+		off *= shape[last]
+		reflect.Copy(dst.Slice(int(off), int(off+count[last])), src.Slice(int(r*count[last]), int((r+1)*count[last])))
+		for k := last - 1; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < count[k] {
+				break
+			}
+			idx[k] = 0
+		}
+	}
+}
+
+func writeJSON(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0666)
+}
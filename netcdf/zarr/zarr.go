@@ -0,0 +1,463 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package zarr writes netCDF datasets as Zarr v2 directory stores, and
+// opens such stores as read-only netcdf.Datasets, without using the
+// netCDF C library.
+//
+// Each group of a dataset is a directory holding a .zgroup file, and a
+// .zattrs file with the global attributes. Each variable is a directory
+// within the one of its group, holding a .zarray file, a .zattrs file with
+// its attributes, and its chunks, named like "0.1.2" after their index in
+// the chunk grid. The names of the dimensions of a variable are in its
+// _ARRAY_DIMENSIONS attribute, as for xarray. The values of CHAR variables
+// are arrays of dtype "|S1"; others are arrays of little endian numbers,
+// such as "<f4" for FLOAT. Chunks are compressed with zlib or gzip, or not
+// at all. The fill value of arrays is the fill value of the variables.
+//
+// Zarr doesn't store everything netCDF does, so the .zattrs files also
+// have an attribute named _netcdf. For variables, it holds the netCDF
+// types of the attributes. For groups, it also holds the dimensions
+// defined in the group with their length and whether they're unlimited,
+// and the order of the variables and of the groups. Opened stores don't
+// show the _netcdf and _ARRAY_DIMENSIONS attributes.
+//
+// Stores written by other programs can be opened too. Without a _netcdf
+// attribute, dimensions are defined in the group of the first variable
+// using them, with the length of the variable along them, and the
+// dimensions of variables without an _ARRAY_DIMENSIONS attribute are named
+// like "_Anonymous_Dim_10" after their length. Variables and groups are
+// in the order of their names. String attributes are CHAR, lists of
+// integers are INT64 and other lists of numbers are DOUBLE; other
+// attributes are ignored. Only arrays in C order, without filters, and
+// compressed with zlib, gzip or not at all can be read.
+//
+// The Zarr v2 format is documented here:
+// https://zarr-specs.readthedocs.io/en/latest/v2/v2.0.html
+package zarr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+// Names of the metadata files.
+const (
+	zgroupFile = ".zgroup"
+	zarrayFile = ".zarray"
+	zattrsFile = ".zattrs"
+)
+
+// Attributes with a special meaning.
+const (
+	netcdfAttr = "_netcdf"
+	dimsAttr   = "_ARRAY_DIMENSIONS"
+)
+
+// zarray is the content of a .zarray file.
+type zarray struct {
+	ZarrFormat         int             `json:"zarr_format"`
+	Shape              []uint64        `json:"shape"`
+	Chunks             []uint64        `json:"chunks"`
+	DType              string          `json:"dtype"`
+	Compressor         *compressor     `json:"compressor"`
+	FillValue          json.RawMessage `json:"fill_value"`
+	Order              string          `json:"order"`
+	Filters            json.RawMessage `json:"filters"`
+	DimensionSeparator string          `json:"dimension_separator,omitempty"`
+}
+
+type compressor struct {
+	ID    string `json:"id"`
+	Level int    `json:"level,omitempty"`
+}
+
+// netcdfMeta is the value of the _netcdf attribute.
+type netcdfMeta struct {
+	Dimensions []netcdfDim       `json:"dimensions,omitempty"`
+	Variables  []string          `json:"variables,omitempty"`
+	Groups     []string          `json:"groups,omitempty"`
+	Types      map[string]string `json:"types,omitempty"`
+}
+
+type netcdfDim struct {
+	Name      string `json:"name"`
+	Length    uint64 `json:"length"`
+	Unlimited bool   `json:"unlimited,omitempty"`
+}
+
+// goTypes maps the netCDF types supported by this package to Go types.
+var goTypes = map[netcdf.Type]reflect.Type{
+	netcdf.BYTE:   reflect.TypeOf(int8(0)),
+	netcdf.CHAR:   reflect.TypeOf(byte(0)),
+	netcdf.SHORT:  reflect.TypeOf(int16(0)),
+	netcdf.INT:    reflect.TypeOf(int32(0)),
+	netcdf.FLOAT:  reflect.TypeOf(float32(0)),
+	netcdf.DOUBLE: reflect.TypeOf(float64(0)),
+	netcdf.UBYTE:  reflect.TypeOf(uint8(0)),
+	netcdf.USHORT: reflect.TypeOf(uint16(0)),
+	netcdf.UINT:   reflect.TypeOf(uint32(0)),
+	netcdf.INT64:  reflect.TypeOf(int64(0)),
+	netcdf.UINT64: reflect.TypeOf(uint64(0)),
+}
+
+// fillValues are the default fill values of the C library.
+var fillValues = map[netcdf.Type]interface{}{
+	netcdf.BYTE:   int8(-127),
+	netcdf.CHAR:   byte(0),
+	netcdf.SHORT:  int16(-32767),
+	netcdf.INT:    int32(-2147483647),
+	netcdf.FLOAT:  float32(9.9692099683868690e+36),
+	netcdf.DOUBLE: float64(9.9692099683868690e+36),
+	netcdf.UBYTE:  uint8(255),
+	netcdf.USHORT: uint16(65535),
+	netcdf.UINT:   uint32(4294967295),
+	netcdf.INT64:  int64(-9223372036854775806),
+	netcdf.UINT64: uint64(18446744073709551614),
+}
+
+// dtypes maps netCDF types to Zarr data types.
+var dtypes = map[netcdf.Type]string{
+	netcdf.BYTE:   "|i1",
+	netcdf.CHAR:   "|S1",
+	netcdf.SHORT:  "<i2",
+	netcdf.INT:    "<i4",
+	netcdf.FLOAT:  "<f4",
+	netcdf.DOUBLE: "<f8",
+	netcdf.UBYTE:  "|u1",
+	netcdf.USHORT: "<u2",
+	netcdf.UINT:   "<u4",
+	netcdf.INT64:  "<i8",
+	netcdf.UINT64: "<u8",
+}
+
+// parseDType returns the netCDF type and the byte order of values of Zarr
+// data type dtype.
+func parseDType(dtype string) (netcdf.Type, binary.ByteOrder, error) {
+	if len(dtype) < 2 {
+		return 0, nil, fmt.Errorf("unsupported dtype %q", dtype)
+	}
+	var order binary.ByteOrder
+	switch dtype[0] {
+	case '<', '|':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	default:
+		return 0, nil, fmt.Errorf("unsupported dtype %q", dtype)
+	}
+	for t, s := range dtypes {
+		if s[1:] == dtype[1:] {
+			return t, order, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("unsupported dtype %q", dtype)
+}
+
+// attr is an attribute.
+type attr struct {
+	name string
+	t    netcdf.Type
+	val  reflect.Value // slice of values
+}
+
+// encodeValue returns the JSON encoding of val, a slice of values of
+// type t. Single numbers aren't in a list, and NaN and infinite values
+// are the strings "NaN", "Infinity" and "-Infinity".
+func encodeValue(t netcdf.Type, val reflect.Value) json.RawMessage {
+	if t == netcdf.CHAR {
+		b, _ := json.Marshal(string(val.Bytes()))
+		return b
+	}
+	var b []byte
+	if val.Len() != 1 {
+		b = append(b, '[')
+	}
+	for i := 0; i < val.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendNumber(b, val.Index(i))
+	}
+	if val.Len() != 1 {
+		b = append(b, ']')
+	}
+	return b
+}
+
+func appendNumber(b []byte, x reflect.Value) []byte {
+	switch x.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := x.Float()
+		switch {
+		case math.IsNaN(f):
+			return append(b, `"NaN"`...)
+		case math.IsInf(f, 1):
+			return append(b, `"Infinity"`...)
+		case math.IsInf(f, -1):
+			return append(b, `"-Infinity"`...)
+		}
+		return strconv.AppendFloat(b, f, 'g', -1, x.Type().Bits())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(b, x.Int(), 10)
+	}
+	return strconv.AppendUint(b, x.Uint(), 10)
+}
+
+// decodeValue decodes the JSON value msg of an attribute of type t, or of
+// a type inferred from msg if t is 0. It returns a nil value for values
+// that can't be attributes.
+func decodeValue(msg json.RawMessage, t netcdf.Type) (netcdf.Type, reflect.Value, error) {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return 0, reflect.Value{}, err
+	}
+	if s, ok := v.(string); ok && (t == 0 || t == netcdf.CHAR) {
+		return netcdf.CHAR, reflect.ValueOf([]byte(s)), nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		list = []interface{}{v}
+	}
+	if t == 0 {
+		t = netcdf.INT64
+		for _, x := range list {
+			n, ok := x.(json.Number)
+			if !ok {
+				return 0, reflect.Value{}, nil
+			}
+			if _, err := n.Int64(); err != nil {
+				t = netcdf.DOUBLE
+			}
+		}
+	}
+	gt, ok := goTypes[t]
+	if !ok || t == netcdf.CHAR {
+		return 0, reflect.Value{}, fmt.Errorf("invalid value %s for type %v", msg, t)
+	}
+	val := reflect.MakeSlice(reflect.SliceOf(gt), len(list), len(list))
+	for i, x := range list {
+		if err := setNumber(val.Index(i), x); err != nil {
+			return 0, reflect.Value{}, err
+		}
+	}
+	return t, val, nil
+}
+
+// setNumber sets x to the JSON value v, a json.Number or a string for
+// special float values.
+func setNumber(x reflect.Value, v interface{}) error {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("invalid number %v", v)
+	}
+	switch x.Kind() {
+	case reflect.Float32, reflect.Float64:
+		switch s {
+		case "NaN":
+			x.SetFloat(math.NaN())
+		case "Infinity":
+			x.SetFloat(math.Inf(1))
+		case "-Infinity":
+			x.SetFloat(math.Inf(-1))
+		default:
+			f, err := strconv.ParseFloat(s, x.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("invalid %v value %q", x.Type(), s)
+			}
+			x.SetFloat(f)
+		}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, x.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %v value %q", x.Type(), s)
+		}
+		x.SetInt(n)
+	default:
+		n, err := strconv.ParseUint(s, 10, x.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %v value %q", x.Type(), s)
+		}
+		x.SetUint(n)
+	}
+	return nil
+}
+
+// encodeFill returns the JSON encoding of fill value x of type t.
+func encodeFill(t netcdf.Type, x reflect.Value) json.RawMessage {
+	if t == netcdf.CHAR {
+		b, _ := json.Marshal(base64.StdEncoding.EncodeToString([]byte{byte(x.Uint())}))
+		return b
+	}
+	return appendNumber(nil, x)
+}
+
+// decodeFill returns the fill value of type t encoded by msg, or zero if
+// it's null.
+func decodeFill(t netcdf.Type, msg json.RawMessage) (reflect.Value, error) {
+	x := reflect.New(goTypes[t]).Elem()
+	if len(msg) == 0 || string(msg) == "null" {
+		return x, nil
+	}
+	if t == netcdf.CHAR {
+		var s string
+		if err := json.Unmarshal(msg, &s); err != nil {
+			return x, err
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return x, fmt.Errorf("invalid fill value %s: %v", msg, err)
+		}
+		if len(b) > 0 {
+			x.SetUint(uint64(b[0]))
+		}
+		return x, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return x, err
+	}
+	return x, setNumber(x, v)
+}
+
+// encodeAttrs returns the content of a .zattrs file holding attrs, in
+// order, followed by the extra attributes with their JSON values, and the
+// _netcdf attribute meta with the types of attrs.
+func encodeAttrs(attrs []attr, extra []string, values []interface{}, meta netcdfMeta) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	sep := "\n  "
+	meta.Types = make(map[string]string)
+	write := func(name string, val []byte) {
+		key, _ := json.Marshal(name)
+		b.WriteString(sep)
+		b.Write(key)
+		b.WriteString(": ")
+		b.Write(val)
+		sep = ",\n  "
+	}
+	for _, a := range attrs {
+		if a.name == netcdfAttr || a.name == dimsAttr {
+			return nil, fmt.Errorf("attribute name %q is reserved", a.name)
+		}
+		write(a.name, encodeValue(a.t, a.val))
+		meta.Types[a.name] = dtypes[a.t]
+	}
+	for i, name := range extra {
+		val, err := json.Marshal(values[i])
+		if err != nil {
+			return nil, err
+		}
+		write(name, val)
+	}
+	val, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	write(netcdfAttr, val)
+	b.WriteString("\n}\n")
+	return b.Bytes(), nil
+}
+
+// decodeAttrs decodes the content of a .zattrs file, and returns the
+// attributes in order, the _ARRAY_DIMENSIONS attribute, and the _netcdf
+// attribute.
+func decodeAttrs(data []byte) ([]attr, []string, *netcdfMeta, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, nil, fmt.Errorf("attributes aren't an object")
+	}
+	var names []string
+	values := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		name := tok.(string)
+		var val json.RawMessage
+		if err := dec.Decode(&val); err != nil {
+			return nil, nil, nil, err
+		}
+		names = append(names, name)
+		values[name] = val
+	}
+
+	var meta *netcdfMeta
+	if val, ok := values[netcdfAttr]; ok {
+		meta = new(netcdfMeta)
+		if err := json.Unmarshal(val, meta); err != nil {
+			return nil, nil, nil, fmt.Errorf("attribute %s: %v", netcdfAttr, err)
+		}
+	}
+	var dims []string
+	if val, ok := values[dimsAttr]; ok {
+		if err := json.Unmarshal(val, &dims); err != nil {
+			return nil, nil, nil, fmt.Errorf("attribute %s: %v", dimsAttr, err)
+		}
+	}
+	var attrs []attr
+	for _, name := range names {
+		if name == netcdfAttr || name == dimsAttr {
+			continue
+		}
+		var t netcdf.Type
+		if meta != nil && meta.Types[name] != "" {
+			var err error
+			if t, _, err = parseDType(meta.Types[name]); err != nil {
+				return nil, nil, nil, fmt.Errorf("attribute %q: %v", name, err)
+			}
+		}
+		t, val, err := decodeValue(values[name], t)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("attribute %q: %v", name, err)
+		}
+		if !val.IsValid() {
+			continue
+		}
+		attrs = append(attrs, attr{name: name, t: t, val: val})
+	}
+	return attrs, dims, meta, nil
+}
+
+// chunkKey returns the name of the chunk file at index idx of the chunk
+// grid.
+func chunkKey(idx []uint64, sep string) string {
+	if len(idx) == 0 {
+		return "0"
+	}
+	s := make([]string, len(idx))
+	for i, x := range idx {
+		s[i] = strconv.FormatUint(x, 10)
+	}
+	return strings.Join(s, sep)
+}
+
+func product(nums []uint64) uint64 {
+	prod := uint64(1)
+	for _, n := range nums {
+		prod *= n
+	}
+	return prod
+}
@@ -0,0 +1,368 @@
+// Copyright 2026 The Go-NetCDF Authors. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package zarr
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+	"github.com/fhs/go-netcdf/netcdf/netcdftest"
+)
+
+var fixture = netcdftest.Fixture{
+	Dims: []netcdftest.Dim{{Name: "time", Len: 0}, {Name: "x", Len: 3}, {Name: "name_len", Len: 4}},
+	Attrs: []netcdftest.Attr{
+		{Name: "title", Value: "test <data>"},
+		{Name: "version", Value: int16(2)},
+		{Name: "range", Value: []float32{-1.5, float32(math.Inf(1))}},
+	},
+	Vars: []netcdftest.Var{
+		{Name: "time", Dims: []string{"time"}, Data: []float64{0, 0.5},
+			Attrs: []netcdftest.Attr{{Name: "units", Value: "days since 2020-01-01"}}},
+		{Name: "temp", Dims: []string{"time", "x"}, Data: []float32{1, 2, float32(math.NaN()), 4, 5, 6},
+			Attrs: []netcdftest.Attr{{Name: "_FillValue", Value: float32(-999)}, {Name: "big", Value: uint64(math.MaxUint64)}}},
+		{Name: "flag", Dims: []string{"x"}, Data: []int8{-1, 0, 1}},
+		{Name: "name", Type: netcdf.CHAR, Dims: []string{"x", "name_len"}, Data: []byte("ab\x00\x00cdefgh\x00\x00")},
+		{Name: "count", Dims: nil, Data: []uint32{42}},
+	},
+}
+
+func TestWriteOpen(t *testing.T) {
+	src := netcdftest.MustBuild(t, fixture)
+	defer src.Close()
+
+	for _, opts := range []*Options{
+		nil,
+		{Compressor: "zlib", Level: 9, Chunks: map[string]uint64{"time": 1, "x": 2}},
+		{Compressor: "gzip"},
+	} {
+		dir := t.TempDir()
+		if err := Write(dir, src, opts); err != nil {
+			t.Fatalf("Write(%+v) failed: %v\n", opts, err)
+		}
+		ds, err := Open(dir)
+		if err != nil {
+			t.Fatalf("Open failed: %v\n", err)
+		}
+		netcdftest.AssertEqual(t, ds, src)
+
+		dst := netcdftest.New()
+		if err := netcdf.Copy(dst, ds, nil); err != nil {
+			t.Fatalf("Copy failed: %v\n", err)
+		}
+		netcdftest.AssertEqual(t, dst, src)
+		unlimited, err := dst.UnlimitedDims()
+		if err != nil || len(unlimited) != 1 {
+			t.Errorf("copy has unlimited dimensions %v (error %v)\n", unlimited, err)
+		}
+		dst.Close()
+		ds.Close()
+	}
+}
+
+func TestWriteChunks(t *testing.T) {
+	src := netcdftest.MustBuild(t, fixture)
+	defer src.Close()
+	dir := t.TempDir()
+	if err := Write(dir, src, &Options{Chunks: map[string]uint64{"time": 1, "x": 2}}); err != nil {
+		t.Fatalf("Write failed: %v\n", err)
+	}
+
+	var za zarray
+	if err := readJSON(filepath.Join(dir, "temp", zarrayFile), &za); err != nil {
+		t.Fatalf("reading .zarray failed: %v\n", err)
+	}
+	if want := []uint64{1, 2}; !reflect.DeepEqual(za.Chunks, want) {
+		t.Errorf("chunks are %v; want %v\n", za.Chunks, want)
+	}
+	if za.DType != "<f4" || string(za.FillValue) != "-999" {
+		t.Errorf("dtype is %q and fill value %s\n", za.DType, za.FillValue)
+	}
+	// Edge chunks are padded.
+	data, err := os.ReadFile(filepath.Join(dir, "temp", "1.1"))
+	if err != nil {
+		t.Fatalf("reading chunk failed: %v\n", err)
+	}
+	chunk := make([]float32, 2)
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, chunk); err != nil {
+		t.Fatalf("decoding chunk failed: %v\n", err)
+	}
+	if chunk[0] != 6 {
+		t.Errorf("chunk 1.1 is %v\n", chunk)
+	}
+
+	var attrs map[string]interface{}
+	if err := readJSON(filepath.Join(dir, "name", zattrsFile), &attrs); err != nil {
+		t.Fatalf("reading .zattrs failed: %v\n", err)
+	}
+	if want := []interface{}{"x", "name_len"}; !reflect.DeepEqual(attrs[dimsAttr], want) {
+		t.Errorf("%s is %v; want %v\n", dimsAttr, attrs[dimsAttr], want)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	src := netcdftest.New()
+	defer src.Close()
+	x, err := src.AddDim("x", 2)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	g, err := src.AddGroup("forecast")
+	if err != nil {
+		t.Fatalf("AddGroup failed: %v\n", err)
+	}
+	y, err := g.AddDim("y", 3)
+	if err != nil {
+		t.Fatalf("AddDim failed: %v\n", err)
+	}
+	v, err := g.AddVar("temp", netcdf.DOUBLE, []netcdf.Dim{x, y})
+	if err != nil {
+		t.Fatalf("AddVar failed: %v\n", err)
+	}
+	if err := src.EndDef(); err != nil {
+		t.Fatalf("EndDef failed: %v\n", err)
+	}
+	if err := v.WriteFloat64s([]float64{1, 2, 3, 4, 5, 6}); err != nil {
+		t.Fatalf("WriteFloat64s failed: %v\n", err)
+	}
+
+	dir := t.TempDir()
+	if err := Write(dir, src, nil); err != nil {
+		t.Fatalf("Write failed: %v\n", err)
+	}
+	ds, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer ds.Close()
+	names, err := ds.GroupNames()
+	if err != nil || !reflect.DeepEqual(names, []string{"forecast"}) {
+		t.Fatalf("groups are %v (error %v)\n", names, err)
+	}
+	got, err := ds.Group("forecast")
+	if err != nil {
+		t.Fatalf("Group failed: %v\n", err)
+	}
+	netcdftest.AssertEqual(t, got, g)
+	if _, err := got.Dim("x"); err != nil {
+		t.Errorf("dimension x of the parent isn't found: %v\n", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	src := netcdftest.MustBuild(t, fixture)
+	defer src.Close()
+	dir := t.TempDir()
+	if err := Write(dir, src, &Options{Compressor: "zlib", Chunks: map[string]uint64{"time": 1, "x": 2}}); err != nil {
+		t.Fatalf("Write failed: %v\n", err)
+	}
+	ds, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer ds.Close()
+	v, err := ds.Var("temp")
+	if err != nil {
+		t.Fatalf("Var failed: %v\n", err)
+	}
+
+	// The stride skips the middle of the chunks along x.
+	got := make([]float32, 2)
+	if err := v.ReadFloat32StridedSlice(got, []uint64{1, 0}, []uint64{1, 3}, []int64{1, 2}); err == nil {
+		t.Errorf("ReadFloat32StridedSlice past the end succeeded\n")
+	}
+	if err := v.ReadFloat32StridedSlice(got, []uint64{1, 0}, []uint64{1, 2}, []int64{1, 2}); err != nil {
+		t.Fatalf("ReadFloat32StridedSlice failed: %v\n", err)
+	}
+	if want := []float32{4, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("read %v; want %v\n", got, want)
+	}
+	x, err := v.ReadFloat32At([]uint64{0, 1})
+	if err != nil || x != 2 {
+		t.Errorf("ReadFloat32At returned %v, %v; want 2\n", x, err)
+	}
+
+	var names []byte
+	if names, err = netcdf.GetBytes(mustVar(t, ds, "name")); err != nil {
+		t.Fatalf("GetBytes failed: %v\n", err)
+	}
+	if string(names) != "ab\x00\x00cdefgh\x00\x00" {
+		t.Errorf("names are %q\n", names)
+	}
+	g, err := openGroup(&store{}, nil, "", dir)
+	if err != nil {
+		t.Fatalf("openGroup failed: %v\n", err)
+	}
+	if err := g.GetVar(3, int(netcdf.DOUBLE), make([]float64, 12)); err != netcdf.ECHAR {
+		t.Errorf("reading CHAR values as DOUBLE returned error %v; want %v\n", err, netcdf.ECHAR)
+	}
+	if err := mustVar(t, ds, "flag").WriteInt8s([]int8{1, 2, 3}); err != netcdf.EPERM {
+		t.Errorf("WriteInt8s returned error %v; want %v\n", err, netcdf.EPERM)
+	}
+	if _, err := ds.AddDim("y", 1); err != netcdf.EPERM {
+		t.Errorf("AddDim returned error %v; want %v\n", err, netcdf.EPERM)
+	}
+}
+
+func mustVar(t *testing.T, ds netcdf.Dataset, name string) netcdf.Var {
+	t.Helper()
+	v, err := ds.Var(name)
+	if err != nil {
+		t.Fatalf("Var(%q) failed: %v\n", name, err)
+	}
+	return v
+}
+
+// TestForeign opens a store written without netCDF metadata, like those
+// of xarray.
+func TestForeign(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]interface{}{
+		".zgroup": map[string]int{"zarr_format": 2},
+		".zattrs": map[string]interface{}{"title": "foreign", "n": []int{1, 2}, "scale": 0.5, "skip": map[string]int{}},
+		"b/.zarray": map[string]interface{}{
+			"zarr_format": 2, "shape": []int{4}, "chunks": []int{2}, "dtype": ">i4",
+			"compressor": nil, "fill_value": -1, "order": "C", "filters": nil,
+		},
+		"b/.zattrs": map[string]interface{}{"_ARRAY_DIMENSIONS": []string{"obs"}},
+		"a/.zarray": map[string]interface{}{
+			"zarr_format": 2, "shape": []int{2, 4}, "chunks": []int{2, 2}, "dtype": "<f8",
+			"compressor": nil, "fill_value": "NaN", "order": "C", "filters": nil,
+			"dimension_separator": "/",
+		},
+	}
+	for name, content := range files {
+		b, err := json.Marshal(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, b, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Only the first chunk of b is written; the other is missing.
+	var chunk bytes.Buffer
+	binary.Write(&chunk, binary.BigEndian, []int32{7, 8})
+	if err := os.WriteFile(filepath.Join(dir, "b", "0"), chunk.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+	chunk.Reset()
+	binary.Write(&chunk, binary.LittleEndian, []float64{1, 2, 3, 4})
+	if err := os.MkdirAll(filepath.Join(dir, "a", "0"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a", "0", "1"), chunk.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	defer ds.Close()
+	nan := math.NaN()
+	netcdftest.AssertEqual(t, ds, netcdftest.MustBuild(t, netcdftest.Fixture{
+		Dims: []netcdftest.Dim{{Name: "_Anonymous_Dim_2", Len: 2}, {Name: "_Anonymous_Dim_4", Len: 4}, {Name: "obs", Len: 4}},
+		Attrs: []netcdftest.Attr{
+			{Name: "n", Value: []int64{1, 2}},
+			{Name: "scale", Value: 0.5},
+			{Name: "title", Value: "foreign"},
+		},
+		Vars: []netcdftest.Var{
+			{Name: "a", Dims: []string{"_Anonymous_Dim_2", "_Anonymous_Dim_4"}, Data: []float64{nan, nan, 1, 2, nan, nan, 3, 4}},
+			{Name: "b", Dims: []string{"obs"}, Data: []int32{7, 8, -1, -1}},
+		},
+	}))
+}
+
+func TestErrors(t *testing.T) {
+	src := netcdftest.MustBuild(t, fixture)
+	defer src.Close()
+	for _, opts := range []*Options{{Compressor: "blosc"}, {Compressor: "zlib", Level: 10}} {
+		if err := Write(t.TempDir(), src, opts); err == nil {
+			t.Errorf("Write(%+v) succeeded\n", opts)
+		}
+	}
+	bad := netcdftest.MustBuild(t, netcdftest.Fixture{Attrs: []netcdftest.Attr{{Name: "_netcdf", Value: "x"}}})
+	defer bad.Close()
+	if err := Write(t.TempDir(), bad, nil); err == nil {
+		t.Errorf("Write of a reserved attribute name succeeded\n")
+	}
+
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Errorf("Open of an empty directory succeeded\n")
+	}
+
+	// Chunk shapes that overflow, or are too large to read, are reported
+	// by Open.
+	for _, chunks := range [][]uint64{{1 << 32, 1 << 32}, {1 << 20, 1 << 20}} {
+		dir := t.TempDir()
+		za, err := json.Marshal(map[string]interface{}{
+			"zarr_format": 2, "shape": []int{2, 2}, "chunks": chunks, "dtype": "<i4",
+			"compressor": nil, "fill_value": 0, "order": "C", "filters": nil,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ".zgroup"), []byte(`{"zarr_format":2}`), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Mkdir(filepath.Join(dir, "a"), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "a", ".zarray"), za, 0666); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(dir); err == nil {
+			t.Errorf("Open of chunks %v succeeded\n", chunks)
+		}
+	}
+
+	// Corrupted chunks are reported when they're read.
+	dir := t.TempDir()
+	if err := Write(dir, src, &Options{Compressor: "zlib"}); err != nil {
+		t.Fatalf("Write failed: %v\n", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "flag", "0"), []byte("junk"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	ds, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v\n", err)
+	}
+	if _, err := netcdf.GetInt8s(mustVar(t, ds, "flag")); err == nil {
+		t.Errorf("reading a corrupted chunk succeeded\n")
+	}
+
+	// Chunks that expand past their size are rejected without being
+	// decompressed whole.
+	var bomb bytes.Buffer
+	zw := zlib.NewWriter(&bomb)
+	zw.Write(make([]byte, 1<<24))
+	zw.Close()
+	if err := os.WriteFile(filepath.Join(dir, "flag", "0"), bomb.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := netcdf.GetInt8s(mustVar(t, ds, "flag")); err == nil || !strings.Contains(err.Error(), "more than 3 bytes") {
+		t.Errorf("reading a chunk that's too long returned %v\n", err)
+	}
+	ds.Close()
+	if _, err := ds.NVars(); err == nil {
+		t.Errorf("NVars succeeded after Close\n")
+	}
+}